@@ -1,7 +1,19 @@
-#!bin/bash
-cd ../HomeAssistant/
+#!/bin/bash
+# Запуск стека сервера по манифесту stack.yaml: Mosquitto, затем Home Assistant
+# и zigbee2mqtt, бот последним. Использование: dockerComposeStartServer.sh [up|down|status]
+#
+# Нужен собранный бинарник бота (Go на сервере не требуется), например:
+#   GOOS=linux GOARCH=arm64 go build -o telegram-bot .   (в каталоге telegramBot)
+# Путь можно переопределить переменной TELEGRAM_BOT_BIN.
+set -euo pipefail
+
+SCRIPT_DIR="$(cd "$(dirname "$0")" && pwd)"
+BOT_BIN="${TELEGRAM_BOT_BIN:-$SCRIPT_DIR/../telegramBot/telegram-bot}"
 export DOCKER_HOST=unix:///var/run/docker.sock
-/usr/bin/docker compose up -d
 
-cd ../ telegrammBot/
-/usr/bin/docker compose up -d --build
\ No newline at end of file
+if [ ! -x "$BOT_BIN" ]; then
+    echo "❌ Не найден бинарник бота: $BOT_BIN (соберите: go build -o telegram-bot . в каталоге telegramBot)" >&2
+    exit 1
+fi
+
+exec "$BOT_BIN" stack "${1:-up}" -f "$SCRIPT_DIR/stack.yaml"
@@ -0,0 +1,79 @@
+package alerting
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Alert - активное оповещение
+type Alert struct {
+	Key     string
+	Message string
+	Since   time.Time
+}
+
+// Tracker помнит активные оповещения и сообщает только об изменениях:
+// повторный Raise по уже активному ключу и Clear по неактивному ничего не отправляют.
+// Гистерезис задает вызывающий код - разными порогами для Raise и Clear.
+type Tracker struct {
+	notify func(text string)
+
+	mu     sync.Mutex
+	active map[string]Alert
+}
+
+func NewTracker(notify func(text string)) *Tracker {
+	return &Tracker{
+		notify: notify,
+		active: make(map[string]Alert),
+	}
+}
+
+// Raise активирует оповещение key. Уведомление отправляется только при первом срабатывании.
+func (t *Tracker) Raise(key string, message string) {
+	t.mu.Lock()
+	if _, ok := t.active[key]; ok {
+		t.mu.Unlock()
+		return
+	}
+	t.active[key] = Alert{Key: key, Message: message, Since: time.Now()}
+	t.mu.Unlock()
+
+	t.notify("🚨 " + message)
+}
+
+// Clear снимает оповещение key и сообщает о восстановлении, если оно было активно
+func (t *Tracker) Clear(key string, message string) {
+	t.mu.Lock()
+	_, ok := t.active[key]
+	delete(t.active, key)
+	t.mu.Unlock()
+
+	if ok {
+		t.notify("✅ " + message)
+	}
+}
+
+// IsActive проверяет, активно ли оповещение
+func (t *Tracker) IsActive(key string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, ok := t.active[key]
+	return ok
+}
+
+// Active возвращает активные оповещения, начиная с самых старых
+func (t *Tracker) Active() []Alert {
+	t.mu.Lock()
+	alerts := make([]Alert, 0, len(t.active))
+	for _, alert := range t.active {
+		alerts = append(alerts, alert)
+	}
+	t.mu.Unlock()
+
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].Since.Before(alerts[j].Since)
+	})
+	return alerts
+}
@@ -0,0 +1,151 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Source - каталог, который попадает в архив под именем Name
+type Source struct {
+	Name string
+	Path string
+}
+
+// parseSources разбирает записи вида "имя=путь" или "путь"
+func parseSources(dirs []string) []Source {
+	var sources []Source
+	for _, dir := range dirs {
+		name, dirPath, ok := strings.Cut(dir, "=")
+		if !ok {
+			dirPath = dir
+			name = filepath.Base(dir)
+		}
+		sources = append(sources, Source{Name: strings.TrimSpace(name), Path: strings.TrimSpace(dirPath)})
+	}
+	return sources
+}
+
+// archiveStats - что попало в архив
+type archiveStats struct {
+	Files    int
+	Excluded int
+	Missing  []string
+}
+
+// buildArchive упаковывает каталоги в tar.gz. Каждый каталог лежит в архиве в папке со своим именем.
+func buildArchive(sources []Source, exclude []string) ([]byte, *archiveStats, error) {
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	stats := &archiveStats{}
+
+	for _, source := range sources {
+		if _, err := os.Stat(source.Path); err != nil {
+			stats.Missing = append(stats.Missing, source.Path)
+			continue
+		}
+
+		err := filepath.WalkDir(source.Path, func(filePath string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			relative, err := filepath.Rel(source.Path, filePath)
+			if err != nil {
+				return err
+			}
+			name := path.Join(source.Name, filepath.ToSlash(relative))
+
+			if relative != "." && isExcluded(name, exclude) {
+				stats.Excluded++
+				if entry.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			return addEntry(tarWriter, filePath, name, entry, stats)
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("ошибка архивации %s: %w", source.Path, err)
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return nil, nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, nil, err
+	}
+	return buffer.Bytes(), stats, nil
+}
+
+// addEntry записывает файл, каталог или символьную ссылку в архив
+func addEntry(tarWriter *tar.Writer, filePath string, name string, entry fs.DirEntry, stats *archiveStats) error {
+	info, err := entry.Info()
+	if err != nil {
+		return err
+	}
+
+	// Сокеты, FIFO и устройства не архивируем
+	if !info.Mode().IsRegular() && !info.IsDir() && info.Mode()&fs.ModeSymlink == 0 {
+		return nil
+	}
+
+	link := ""
+	if info.Mode()&fs.ModeSymlink != 0 {
+		if link, err = os.Readlink(filePath); err != nil {
+			return err
+		}
+	}
+
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if info.IsDir() {
+		header.Name += "/"
+	}
+
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(tarWriter, file); err != nil {
+		return err
+	}
+	stats.Files++
+	return nil
+}
+
+// isExcluded сравнивает шаблоны с путем внутри архива и с именем файла
+func isExcluded(name string, exclude []string) bool {
+	base := path.Base(name)
+	for _, pattern := range exclude {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+		if matched, _ := path.Match(pattern, base); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,186 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"telegramBot/config"
+	"telegramBot/logging"
+	"telegramBot/scheduler"
+	"telegramBot/yandexapi"
+	"telegramBot/yandexapi/authenticated"
+)
+
+var logger = logging.For("backup")
+
+// Notifier отправляет отчет в чат администраторов
+type Notifier func(text string)
+
+// Service собирает архивы каталогов и хранит их на Яндекс.Диске
+type Service struct {
+	config  config.BackupConfig
+	sources []Source
+	notify  Notifier
+	mu      sync.Mutex // одновременно выполняется только один бэкап
+}
+
+// Result - итог одного запуска
+type Result struct {
+	Archive  Archive
+	SHA256   string
+	Stats    *archiveStats
+	Deleted  []string
+	Duration time.Duration
+}
+
+func NewService(config *config.Config, notify Notifier) *Service {
+	return &Service{
+		config:  config.Backup,
+		sources: parseSources(config.Backup.Dirs),
+		notify:  notify,
+	}
+}
+
+// Start регистрирует бэкап в планировщике
+func (s *Service) Start() error {
+	if s.config.Schedule == "" {
+		logger.Info("💤 Резервное копирование по расписанию выключено (BACKUP_SCHEDULE пуст)")
+		return nil
+	}
+
+	return scheduler.Add("backup", s.config.Schedule, func() {
+		s.RunAndReport()
+	})
+}
+
+// RunAndReport выполняет бэкап и отправляет отчет администраторам
+func (s *Service) RunAndReport() {
+	result, err := s.Run()
+	if err != nil {
+		logger.Error("❌ Ошибка резервного копирования", "error", err)
+		s.notify(fmt.Sprintf("❌ <b>Ошибка резервного копирования</b>\n%s", html.EscapeString(err.Error())))
+		return
+	}
+	s.notify(FormatResult(result))
+}
+
+// Run собирает архив, загружает его на диск и удаляет устаревшие архивы
+func (s *Service) Run() (*Result, error) {
+	if !s.mu.TryLock() {
+		return nil, fmt.Errorf("резервное копирование уже выполняется")
+	}
+	defer s.mu.Unlock()
+
+	started := time.Now()
+	logger.Info("📦 Начало резервного копирования", "sources", len(s.sources))
+
+	data, stats, err := buildArchive(s.sources, s.config.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	if len(stats.Missing) == len(s.sources) {
+		return nil, fmt.Errorf("ни один из каталогов не найден: %s", strings.Join(stats.Missing, ", "))
+	}
+
+	checksum := sha256.Sum256(data)
+	name := archiveName(s.config.Prefix, started)
+	if err := yandexapi.EnsureDirectory(s.config.RemoteDir); err != nil {
+		return nil, fmt.Errorf("ошибка создания папки %s: %w", s.config.RemoteDir, err)
+	}
+	if err := yandexapi.UploadFile(s.config.RemoteDir, name, data); err != nil {
+		return nil, fmt.Errorf("ошибка загрузки архива: %w", err)
+	}
+
+	result := &Result{
+		Archive: Archive{
+			Name:    name,
+			Path:    path.Join(s.config.RemoteDir, name),
+			Size:    int64(len(data)),
+			Created: started,
+		},
+		SHA256: hex.EncodeToString(checksum[:]),
+		Stats:  stats,
+	}
+
+	deleted, err := s.applyRetention()
+	if err != nil {
+		logger.Warn("⚠️ Ошибка очистки старых архивов", "error", err)
+	}
+	result.Deleted = deleted
+	result.Duration = time.Since(started)
+
+	logger.Info("✅ Резервная копия загружена", "name", name, "size", yandexapi.FormatBytes(result.Archive.Size))
+	return result, nil
+}
+
+// List возвращает архивы бота на диске, от новых к старым.
+// Папки еще нет, пока не было ни одного бэкапа - это пустой список, а не ошибка
+func (s *Service) List() ([]Archive, error) {
+	files, err := yandexapi.ListFiles(s.config.RemoteDir)
+	if authenticated.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var archives []Archive
+	for _, file := range files {
+		created, ok := parseArchiveName(s.config.Prefix, file.Name)
+		if !ok || file.Type != "file" {
+			continue
+		}
+		archives = append(archives, Archive{
+			Name:    file.Name,
+			Path:    path.Join(s.config.RemoteDir, file.Name),
+			Size:    file.Size,
+			Created: created,
+		})
+	}
+
+	sortNewestFirst(archives)
+	return archives, nil
+}
+
+// applyRetention удаляет архивы, не попавшие в схему хранения
+func (s *Service) applyRetention() ([]string, error) {
+	archives, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []string
+	for _, archive := range selectExpired(archives, s.config.KeepDaily, s.config.KeepWeekly, s.config.KeepMonthly) {
+		if err := yandexapi.DeleteFile(archive.Path, true); err != nil {
+			return deleted, fmt.Errorf("ошибка удаления %s: %w", archive.Name, err)
+		}
+		logger.Info("🧹 Удален устаревший архив", "name", archive.Name)
+		deleted = append(deleted, archive.Name)
+	}
+	return deleted, nil
+}
+
+// FormatResult готовит отчет о бэкапе для Telegram
+func FormatResult(result *Result) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "✅ <b>Резервная копия создана</b>\n")
+	fmt.Fprintf(&builder, "📦 Архив: <code>%s</code>\n", html.EscapeString(result.Archive.Path))
+	fmt.Fprintf(&builder, "💾 Размер: <b>%s</b>\n", yandexapi.FormatBytes(result.Archive.Size))
+	fmt.Fprintf(&builder, "📄 Файлов: <b>%d</b>, исключено: <b>%d</b>\n", result.Stats.Files, result.Stats.Excluded)
+	fmt.Fprintf(&builder, "🔐 SHA-256: <code>%s</code>\n", result.SHA256)
+	fmt.Fprintf(&builder, "⏱️ Время: <b>%s</b>", result.Duration.Round(time.Second))
+
+	if len(result.Stats.Missing) > 0 {
+		fmt.Fprintf(&builder, "\n⚠️ Не найдены каталоги: <code>%s</code>", html.EscapeString(strings.Join(result.Stats.Missing, ", ")))
+	}
+	if len(result.Deleted) > 0 {
+		fmt.Fprintf(&builder, "\n🧹 Удалено устаревших архивов: <b>%d</b>", len(result.Deleted))
+	}
+	return builder.String()
+}
@@ -0,0 +1,241 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"telegramBot/yandexapi"
+)
+
+// Статусы файлов в плане восстановления
+const (
+	FileNew       = "new"
+	FileChanged   = "changed"
+	FileUnchanged = "unchanged"
+)
+
+// RestorePlan - результат пробного прогона: что изменится в целевом каталоге
+type RestorePlan struct {
+	Archive   string
+	Target    string
+	Source    string // восстанавливается только этот источник (пусто - все)
+	New       []string
+	Changed   []string
+	Unchanged int
+	Verified  bool // контрольная сумма сверена с Яндекс.Диском
+}
+
+// Download скачивает архив и сверяет его SHA-256 с контрольной суммой, которую хранит Яндекс.Диск
+func (s *Service) Download(name string) ([]byte, bool, error) {
+	name = path.Base(name)
+	if _, ok := parseArchiveName(s.config.Prefix, name); !ok {
+		return nil, false, fmt.Errorf("%s не похож на архив резервной копии", name)
+	}
+	remotePath := path.Join(s.config.RemoteDir, name)
+
+	info, err := yandexapi.FileInfo(remotePath)
+	if err != nil {
+		return nil, false, fmt.Errorf("архив не найден: %w", err)
+	}
+
+	data, err := yandexapi.DownloadFile(remotePath)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if info.SHA256 == "" {
+		logger.Warn("⚠️ Яндекс.Диск не вернул SHA-256, проверка пропущена", "name", name)
+		return data, false, nil
+	}
+
+	checksum := sha256.Sum256(data)
+	if actual := hex.EncodeToString(checksum[:]); !strings.EqualFold(actual, info.SHA256) {
+		return nil, false, fmt.Errorf("контрольная сумма не совпадает: ожидалась %s, получена %s", info.SHA256, actual)
+	}
+	return data, true, nil
+}
+
+// SourceFor возвращает имя источника, если target - его каталог из BACKUP_DIRS.
+// Иначе target считается общим каталогом, в котором лежат папки всех источников
+func (s *Service) SourceFor(target string) string {
+	for _, source := range s.sources {
+		if filepath.Clean(source.Path) == filepath.Clean(target) {
+			return source.Name
+		}
+	}
+	return ""
+}
+
+// entryName - путь записи архива относительно целевого каталога. Каждый источник лежит
+// в архиве в папке со своим именем: при восстановлении одного источника эта папка
+// отбрасывается, а записи остальных источников пропускаются
+func entryName(name string, source string) (string, bool) {
+	if source == "" {
+		return name, true
+	}
+	name = path.Clean(name)
+	if name == source {
+		return ".", true
+	}
+	return strings.CutPrefix(name, source+"/")
+}
+
+// Plan сравнивает содержимое архива с целевым каталогом, ничего не изменяя.
+// source - имя источника, если восстанавливается только он (см. SourceFor)
+func Plan(data []byte, target string, source string) (*RestorePlan, error) {
+	plan := &RestorePlan{Target: target, Source: source}
+
+	err := walkArchive(data, func(header *tar.Header, content io.Reader) error {
+		if header.Typeflag != tar.TypeReg {
+			return nil
+		}
+		name, ok := entryName(header.Name, source)
+		if !ok {
+			return nil
+		}
+
+		destination, err := safeJoin(target, name)
+		if err != nil {
+			return err
+		}
+
+		switch status, err := compareFile(destination, header.Size, content); {
+		case err != nil:
+			return err
+		case status == FileNew:
+			plan.New = append(plan.New, name)
+		case status == FileChanged:
+			plan.Changed = append(plan.Changed, name)
+		default:
+			plan.Unchanged++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Extract распаковывает архив в целевой каталог, перезаписывая существующие файлы.
+// source - как в Plan
+func Extract(data []byte, target string, source string) (int, error) {
+	written := 0
+
+	err := walkArchive(data, func(header *tar.Header, content io.Reader) error {
+		name, ok := entryName(header.Name, source)
+		if !ok {
+			return nil
+		}
+		destination, err := safeJoin(target, name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			return os.MkdirAll(destination, fs.FileMode(header.Mode).Perm()|0o700)
+		case tar.TypeSymlink:
+			if filepath.IsAbs(header.Linkname) || strings.Contains(header.Linkname, "..") {
+				logger.Warn("⚠️ Пропущена ссылка за пределы каталога", "name", header.Name, "target", header.Linkname)
+				return nil
+			}
+			os.Remove(destination)
+			return os.Symlink(header.Linkname, destination)
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(destination, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, fs.FileMode(header.Mode).Perm())
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(file, content); err != nil {
+				file.Close()
+				return err
+			}
+			written++
+			return file.Close()
+		}
+		return nil
+	})
+	return written, err
+}
+
+// walkArchive последовательно передает записи tar.gz в callback
+func walkArchive(data []byte, callback func(header *tar.Header, content io.Reader) error) error {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("архив поврежден: %w", err)
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("архив поврежден: %w", err)
+		}
+		if err := callback(header, tarReader); err != nil {
+			return err
+		}
+	}
+}
+
+// compareFile определяет, новый ли файл, изменился ли он или совпадает с содержимым архива
+func compareFile(destination string, size int64, content io.Reader) (string, error) {
+	info, err := os.Stat(destination)
+	if errors.Is(err, fs.ErrNotExist) {
+		return FileNew, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if info.Size() != size {
+		return FileChanged, nil
+	}
+
+	existing, err := os.Open(destination)
+	if err != nil {
+		return "", err
+	}
+	defer existing.Close()
+
+	archiveHash := sha256.New()
+	if _, err := io.Copy(archiveHash, content); err != nil {
+		return "", err
+	}
+	existingHash := sha256.New()
+	if _, err := io.Copy(existingHash, existing); err != nil {
+		return "", err
+	}
+
+	if !bytes.Equal(archiveHash.Sum(nil), existingHash.Sum(nil)) {
+		return FileChanged, nil
+	}
+	return FileUnchanged, nil
+}
+
+// safeJoin не дает записям архива выйти за пределы целевого каталога
+func safeJoin(target string, name string) (string, error) {
+	destination := filepath.Join(target, filepath.FromSlash(name))
+	relative, err := filepath.Rel(target, destination)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("недопустимый путь в архиве: %s", name)
+	}
+	return destination, nil
+}
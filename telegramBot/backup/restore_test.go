@@ -0,0 +1,208 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// testEntry - запись tar-архива для тестов: файл, каталог или символическая ссылка
+type testEntry struct {
+	name     string
+	typeflag byte
+	body     string
+	link     string
+}
+
+func makeArchive(t *testing.T, entries ...testEntry) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Typeflag: entry.typeflag, Linkname: entry.link, Mode: 0o644, Size: int64(len(entry.body))}
+		if entry.typeflag == tar.TypeDir {
+			header.Mode = 0o755
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tarWriter.Write([]byte(entry.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func TestSafeJoin(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "restore")
+
+	tests := []struct {
+		name    string
+		entry   string
+		want    string
+		wantErr bool
+	}{
+		{name: "обычный файл", entry: "config/configuration.yaml", want: "config/configuration.yaml"},
+		{name: "текущий каталог", entry: "./config/", want: "config"},
+		{name: "сам каталог", entry: ".", want: "."},
+		{name: "выход на уровень вверх", entry: "../x", wantErr: true},
+		{name: "выход вверх после каталога", entry: "a/../../x", wantErr: true},
+		{name: "глубокий выход вверх", entry: "a/b/../../../x", wantErr: true},
+		{name: "родительский каталог", entry: "..", wantErr: true},
+		{name: "возврат внутрь каталога", entry: "a/../b", want: "b"},
+		{name: "имя, начинающееся с точек", entry: "..x/file", want: "..x/file"},
+		{name: "абсолютный путь остается внутри", entry: "/etc/passwd", want: "etc/passwd"},
+		{name: "абсолютный путь с выходом вверх", entry: "/../../etc/passwd", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := safeJoin(target, test.entry)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("safeJoin(%q) = %q, ожидалась ошибка", test.entry, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := filepath.Join(target, filepath.FromSlash(test.want)); got != want {
+				t.Fatalf("safeJoin(%q) = %q, ожидалось %q", test.entry, got, want)
+			}
+		})
+	}
+}
+
+func TestExtractRejectsTraversal(t *testing.T) {
+	for _, name := range []string{"../x", "a/../../x"} {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			target := filepath.Join(root, "restore")
+			data := makeArchive(t, testEntry{name: name, typeflag: tar.TypeReg, body: "evil"})
+
+			if _, err := Extract(data, target, ""); err == nil {
+				t.Fatal("ожидалась ошибка недопустимого пути")
+			}
+			if _, err := Plan(data, target, ""); err == nil {
+				t.Fatal("Plan: ожидалась ошибка недопустимого пути")
+			}
+			if _, err := os.Stat(filepath.Join(root, "x")); !os.IsNotExist(err) {
+				t.Fatalf("файл записан за пределы каталога: %v", err)
+			}
+		})
+	}
+}
+
+func TestExtractSymlinks(t *testing.T) {
+	root := t.TempDir()
+	target := filepath.Join(root, "restore")
+	data := makeArchive(t,
+		testEntry{name: "config", typeflag: tar.TypeDir},
+		testEntry{name: "config/secrets.yaml", typeflag: tar.TypeReg, body: "token: 1"},
+		testEntry{name: "config/link.yaml", typeflag: tar.TypeSymlink, link: "secrets.yaml"},
+		testEntry{name: "config/absolute", typeflag: tar.TypeSymlink, link: "/etc"},
+		testEntry{name: "config/up", typeflag: tar.TypeSymlink, link: "../.."},
+		testEntry{name: "config/hidden", typeflag: tar.TypeSymlink, link: "sub/../../.."},
+		// Запись через отброшенную ссылку должна остаться внутри каталога
+		testEntry{name: "config/up/escaped", typeflag: tar.TypeReg, body: "evil"},
+	)
+
+	written, err := Extract(data, target, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != 2 {
+		t.Fatalf("записано файлов: %d, ожидалось 2", written)
+	}
+
+	if link, err := os.Readlink(filepath.Join(target, "config/link.yaml")); err != nil || link != "secrets.yaml" {
+		t.Fatalf("ссылка внутри каталога: %q, %v", link, err)
+	}
+	for _, name := range []string{"config/absolute", "config/hidden"} {
+		if _, err := os.Lstat(filepath.Join(target, name)); !os.IsNotExist(err) {
+			t.Fatalf("%s: ссылка за пределы каталога не должна создаваться", name)
+		}
+	}
+	if info, err := os.Lstat(filepath.Join(target, "config/up")); err != nil || !info.IsDir() {
+		t.Fatalf("config/up должен быть обычным каталогом: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "escaped")); !os.IsNotExist(err) {
+		t.Fatalf("файл записан за пределы каталога: %v", err)
+	}
+
+	// Plan сравнивает только обычные файлы
+	plan, err := Plan(data, target, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.New) != 0 || len(plan.Changed) != 0 || plan.Unchanged != 2 {
+		t.Fatalf("план после распаковки: %+v", plan)
+	}
+}
+
+func TestPlanSingleSource(t *testing.T) {
+	root := t.TempDir()
+	target := filepath.Join(root, "homeassistant")
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile := func(name, body string) {
+		if err := os.WriteFile(filepath.Join(target, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("configuration.yaml", "old")
+	writeFile("automations.yaml", "same")
+
+	data := makeArchive(t,
+		testEntry{name: "homeassistant", typeflag: tar.TypeDir},
+		testEntry{name: "homeassistant/configuration.yaml", typeflag: tar.TypeReg, body: "new"},
+		testEntry{name: "homeassistant/automations.yaml", typeflag: tar.TypeReg, body: "same"},
+		testEntry{name: "homeassistant/scripts.yaml", typeflag: tar.TypeReg, body: "script"},
+		testEntry{name: "mosquitto/mosquitto.conf", typeflag: tar.TypeReg, body: "listener 1883"},
+	)
+
+	service := &Service{sources: parseSources([]string{"homeassistant=" + target, "mosquitto=/backup/mosquitto"})}
+	source := service.SourceFor(target + "/")
+	if source != "homeassistant" {
+		t.Fatalf("SourceFor = %q", source)
+	}
+	if other := service.SourceFor(root); other != "" {
+		t.Fatalf("SourceFor(родительский каталог) = %q", other)
+	}
+
+	plan, err := Plan(data, target, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(plan.Changed, []string{"configuration.yaml"}) || !slices.Equal(plan.New, []string{"scripts.yaml"}) || plan.Unchanged != 1 {
+		t.Fatalf("план: %+v", plan)
+	}
+
+	written, err := Extract(data, target, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != 3 {
+		t.Fatalf("записано файлов: %d, ожидалось 3", written)
+	}
+	if content, _ := os.ReadFile(filepath.Join(target, "configuration.yaml")); string(content) != "new" {
+		t.Fatalf("configuration.yaml: %q", content)
+	}
+	for _, name := range []string{"homeassistant", "mosquitto"} {
+		if _, err := os.Stat(filepath.Join(target, name)); !os.IsNotExist(err) {
+			t.Fatalf("%s: лишняя папка источника в целевом каталоге", name)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package backup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const archiveTimeLayout = "20060102-150405"
+
+// Archive - архив с бэкапом на Яндекс.Диске
+type Archive struct {
+	Name    string
+	Path    string
+	Size    int64
+	Created time.Time
+}
+
+func archiveName(prefix string, created time.Time) string {
+	return fmt.Sprintf("%s-%s.tar.gz", prefix, created.Format(archiveTimeLayout))
+}
+
+// parseArchiveName извлекает время создания из имени вида <префикс>-20240131-030000.tar.gz
+func parseArchiveName(prefix string, name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, prefix+"-") || !strings.HasSuffix(name, ".tar.gz") {
+		return time.Time{}, false
+	}
+
+	timestamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix+"-"), ".tar.gz")
+	created, err := time.ParseInLocation(archiveTimeLayout, timestamp, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return created, true
+}
+
+// selectExpired применяет схему хранения "дед-отец-сын": последний архив за каждый из
+// keepDaily дней, за каждую из keepWeekly недель и за каждый из keepMonthly месяцев.
+// Возвращает архивы, которые не попали ни в одну из групп.
+func selectExpired(archives []Archive, keepDaily, keepWeekly, keepMonthly int) []Archive {
+	sorted := append([]Archive(nil), archives...)
+	sortNewestFirst(sorted)
+
+	keep := make(map[string]bool)
+	mark := func(limit int, bucket func(time.Time) string) {
+		seen := make(map[string]bool)
+		for _, archive := range sorted {
+			key := bucket(archive.Created)
+			if seen[key] {
+				continue
+			}
+			if len(seen) >= limit {
+				return
+			}
+			seen[key] = true
+			keep[archive.Name] = true
+		}
+	}
+
+	mark(keepDaily, func(t time.Time) string { return t.Format("2006-01-02") })
+	mark(keepWeekly, func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	})
+	mark(keepMonthly, func(t time.Time) string { return t.Format("2006-01") })
+
+	// Самый свежий архив храним всегда, даже если все лимиты равны нулю
+	if len(sorted) > 0 {
+		keep[sorted[0].Name] = true
+	}
+
+	var expired []Archive
+	for _, archive := range sorted {
+		if !keep[archive.Name] {
+			expired = append(expired, archive)
+		}
+	}
+	return expired
+}
+
+func sortNewestFirst(archives []Archive) {
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].Created.After(archives[j].Created)
+	})
+}
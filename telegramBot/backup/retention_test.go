@@ -0,0 +1,120 @@
+package backup
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func archivesAt(times ...string) []Archive {
+	archives := make([]Archive, 0, len(times))
+	for _, value := range times {
+		created, err := time.ParseInLocation("2006-01-02 15:04", value, time.Local)
+		if err != nil {
+			panic(err)
+		}
+		archives = append(archives, Archive{Name: value, Created: created})
+	}
+	return archives
+}
+
+func TestSelectExpired(t *testing.T) {
+	tests := []struct {
+		name                   string
+		archives               []Archive
+		daily, weekly, monthly int
+		want                   []string
+	}{
+		{
+			name:     "пустой список",
+			archives: nil,
+			daily:    7, weekly: 4, monthly: 6,
+		},
+		{
+			name:     "все лимиты нулевые - остается самый свежий",
+			archives: archivesAt("2024-03-01 03:00", "2024-03-02 03:00", "2024-03-03 03:00"),
+			want:     []string{"2024-03-02 03:00", "2024-03-01 03:00"},
+		},
+		{
+			name:     "последний архив за день",
+			archives: archivesAt("2024-03-01 03:00", "2024-03-01 12:00", "2024-03-02 03:00", "2024-03-02 23:59", "2024-03-03 00:00"),
+			daily:    2,
+			want:     []string{"2024-03-02 03:00", "2024-03-01 12:00", "2024-03-01 03:00"},
+		},
+		{
+			name: "граница недели: воскресенье и понедельник",
+			// 10.03.2024 - воскресенье, 11.03.2024 - понедельник следующей ISO-недели
+			archives: archivesAt("2024-03-09 03:00", "2024-03-10 23:00", "2024-03-11 01:00"),
+			weekly:   2,
+			want:     []string{"2024-03-09 03:00"},
+		},
+		{
+			name: "ISO-неделя через границу года",
+			// 30.12.2024 и 05.01.2025 - одна неделя 2025-W01, 29.12.2024 - 2024-W52
+			archives: archivesAt("2024-12-29 03:00", "2024-12-30 03:00", "2025-01-05 03:00", "2025-01-06 03:00"),
+			weekly:   2,
+			want:     []string{"2024-12-30 03:00", "2024-12-29 03:00"},
+		},
+		{
+			name:     "граница месяца",
+			archives: archivesAt("2024-01-15 03:00", "2024-01-31 23:59", "2024-02-01 00:00", "2024-02-29 03:00"),
+			monthly:  2,
+			want:     []string{"2024-02-01 00:00", "2024-01-15 03:00"},
+		},
+		{
+			name:     "граница года для месяцев",
+			archives: archivesAt("2023-12-31 23:00", "2024-01-01 01:00", "2023-11-30 03:00"),
+			monthly:  2,
+			want:     []string{"2023-11-30 03:00"},
+		},
+		{
+			name: "группы дополняют друг друга",
+			archives: archivesAt(
+				"2024-01-20 03:00", "2024-02-20 03:00", "2024-02-25 03:00",
+				"2024-03-04 03:00", "2024-03-09 03:00", "2024-03-10 03:00", "2024-03-11 03:00",
+			),
+			daily:   2,
+			weekly:  2,
+			monthly: 3,
+			// Дни: 11.03, 10.03; недели: 11.03 (W11), 10.03 (W10); месяцы: 11.03, 25.02, 20.01
+			want: []string{"2024-03-09 03:00", "2024-03-04 03:00", "2024-02-20 03:00"},
+		},
+		{
+			name:     "лимит больше числа архивов",
+			archives: archivesAt("2024-03-01 03:00", "2024-03-02 03:00"),
+			daily:    30, weekly: 10, monthly: 12,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Порядок входа не важен
+			archives := slices.Clone(test.archives)
+			slices.Reverse(archives)
+
+			var got []string
+			for _, archive := range selectExpired(archives, test.daily, test.weekly, test.monthly) {
+				got = append(got, archive.Name)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Fatalf("удаляются %v, ожидалось %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseArchiveName(t *testing.T) {
+	created := time.Date(2024, time.January, 31, 3, 0, 0, 0, time.Local)
+	name := archiveName("ha", created)
+	if name != "ha-20240131-030000.tar.gz" {
+		t.Fatalf("имя архива %q", name)
+	}
+	if parsed, ok := parseArchiveName("ha", name); !ok || !parsed.Equal(created) {
+		t.Fatalf("parseArchiveName(%q) = %v, %v", name, parsed, ok)
+	}
+	for _, other := range []string{"ha-backup-20240131-030000.tar.gz", "ha-20240131.tar.gz", "other-20240131-030000.tar.gz", "ha-20240131-030000.zip"} {
+		if _, ok := parseArchiveName("ha", other); ok {
+			t.Fatalf("parseArchiveName(%q) не должен распознаваться", other)
+		}
+	}
+}
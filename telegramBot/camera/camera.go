@@ -0,0 +1,199 @@
+package camera
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"telegramBot/config"
+	"telegramBot/homeassistant"
+	"telegramBot/logging"
+	"telegramBot/yandexapi"
+)
+
+var logger = logging.For("camera")
+
+const (
+	dateLayout = "2006-01-02"
+
+	// Не чаще одного снимка по событию с одной камеры
+	triggerCooldown = time.Minute
+)
+
+// Camera - камера Home Assistant под коротким именем
+type Camera struct {
+	Name     string
+	EntityID string
+}
+
+// Trigger - сущность, переход которой в "on"/"open" запускает снимок камеры
+type Trigger struct {
+	EntityID string
+	Camera   string
+}
+
+// Service делает снимки камер, архивирует их на Яндекс.Диск и удаляет устаревшие
+type Service struct {
+	ha       *homeassistant.Client
+	config   config.CameraConfig
+	cameras  []Camera
+	triggers []Trigger
+
+	mu          sync.Mutex
+	lastTrigger map[string]time.Time
+}
+
+func NewService(ha *homeassistant.Client, config config.CameraConfig) (*Service, error) {
+	cameras, err := parsePairs(config.Cameras, "camera")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Service{ha: ha, config: config, lastTrigger: make(map[string]time.Time)}
+	for _, pair := range cameras {
+		s.cameras = append(s.cameras, Camera{Name: pair[0], EntityID: pair[1]})
+	}
+
+	triggers, err := parsePairs(config.Triggers, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, pair := range triggers {
+		if _, ok := s.Camera(pair[1]); !ok {
+			return nil, fmt.Errorf("триггер %s ссылается на неизвестную камеру %s", pair[0], pair[1])
+		}
+		s.triggers = append(s.triggers, Trigger{EntityID: pair[0], Camera: pair[1]})
+	}
+	return s, nil
+}
+
+// parsePairs разбирает элементы "ключ=значение". Если domain задан, элемент без ключа
+// ("camera.front") получает ключ из части entity_id после точки.
+func parsePairs(items []string, domain string) ([][2]string, error) {
+	var pairs [][2]string
+	for _, item := range items {
+		key, value, ok := strings.Cut(item, "=")
+		if !ok {
+			if domain == "" || !strings.HasPrefix(item, domain+".") {
+				return nil, fmt.Errorf("некорректный элемент %q, ожидается имя=значение", item)
+			}
+			key, value = strings.TrimPrefix(item, domain+"."), item
+		}
+		pairs = append(pairs, [2]string{strings.TrimSpace(key), strings.TrimSpace(value)})
+	}
+	return pairs, nil
+}
+
+// Cameras возвращает список настроенных камер
+func (s *Service) Cameras() []Camera {
+	return s.cameras
+}
+
+// Camera ищет камеру по имени или entity_id
+func (s *Service) Camera(name string) (Camera, bool) {
+	for _, camera := range s.cameras {
+		if camera.Name == name || camera.EntityID == name {
+			return camera, true
+		}
+	}
+	return Camera{}, false
+}
+
+// Snapshot получает текущий кадр камеры
+func (s *Service) Snapshot(camera Camera) ([]byte, error) {
+	return s.ha.CameraSnapshot(camera.EntityID)
+}
+
+// Archive сохраняет кадр в /Cameras/<имя>/<дата>/<время>.jpg и возвращает путь
+func (s *Service) Archive(camera Camera, image []byte, taken time.Time) (string, error) {
+	dir := path.Join(s.config.RemoteDir, camera.Name, taken.Format(dateLayout))
+	if err := yandexapi.EnsureDirectory(dir); err != nil {
+		return "", err
+	}
+
+	name := taken.Format("150405") + ".jpg"
+	if err := yandexapi.UploadFile(dir, name, image); err != nil {
+		return "", err
+	}
+	return path.Join(dir, name), nil
+}
+
+// SnapshotAll делает и архивирует снимки всех камер (задача по расписанию)
+func (s *Service) SnapshotAll() {
+	for _, camera := range s.cameras {
+		image, err := s.Snapshot(camera)
+		if err != nil {
+			logger.Error("❌ Ошибка снимка", "camera", camera.Name, "error", err)
+			continue
+		}
+		if _, err := s.Archive(camera, image, time.Now()); err != nil {
+			logger.Error("❌ Ошибка архивации", "camera", camera.Name, "error", err)
+		}
+	}
+}
+
+// Triggered возвращает камеры, которые нужно снять при смене состояния сущности.
+// Повторные срабатывания чаще triggerCooldown игнорируются.
+func (s *Service) Triggered(change homeassistant.StateChange) []Camera {
+	if change.NewState == nil || (change.NewState.State != "on" && change.NewState.State != "open") {
+		return nil
+	}
+	if change.OldState != nil && change.OldState.State == change.NewState.State {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var cameras []Camera
+	now := time.Now()
+	for _, trigger := range s.triggers {
+		if trigger.EntityID != change.EntityID {
+			continue
+		}
+		if now.Sub(s.lastTrigger[trigger.Camera]) < triggerCooldown {
+			continue
+		}
+		s.lastTrigger[trigger.Camera] = now
+
+		camera, _ := s.Camera(trigger.Camera)
+		cameras = append(cameras, camera)
+	}
+	return cameras
+}
+
+// HasTriggers сообщает, нужны ли события state_changed
+func (s *Service) HasTriggers() bool {
+	return len(s.triggers) > 0
+}
+
+// Prune удаляет папки с датами старше срока хранения
+func (s *Service) Prune() {
+	if s.config.RetentionDays <= 0 {
+		return
+	}
+	cutoff := time.Now().AddDate(0, 0, -s.config.RetentionDays)
+
+	for _, camera := range s.cameras {
+		dir := path.Join(s.config.RemoteDir, camera.Name)
+		items, err := yandexapi.ListFiles(dir)
+		if err != nil {
+			logger.Warn("⚠️ Не удалось прочитать архив", "camera", camera.Name, "dir", dir, "error", err)
+			continue
+		}
+
+		for _, item := range items {
+			date, err := time.ParseInLocation(dateLayout, item.Name, time.Local)
+			if item.Type != "dir" || err != nil || !date.Before(cutoff) {
+				continue
+			}
+			if err := yandexapi.DeleteFile(path.Join(dir, item.Name), true); err != nil {
+				logger.Error("❌ Ошибка удаления", "camera", camera.Name, "name", item.Name, "error", err)
+				continue
+			}
+			logger.Info("🗑️ Удалены снимки", "camera", camera.Name, "date", item.Name)
+		}
+	}
+}
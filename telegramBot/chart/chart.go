@@ -0,0 +1,184 @@
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/plotutil"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+const (
+	width  = 24 * vg.Centimeter
+	height = 12 * vg.Centimeter
+)
+
+// Point - значение в момент времени
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series - линия на графике
+type Series struct {
+	Name   string
+	Unit   string
+	Points []Point
+}
+
+// Render рисует линейный график нескольких рядов и возвращает PNG.
+// Для каждого ряда отмечаются минимум и максимум.
+func Render(title string, series []Series) ([]byte, error) {
+	p := plot.New()
+	p.Title.Text = title
+	p.X.Tick.Marker = plot.TimeTicks{
+		Format: timeFormat(series),
+		Time:   func(t float64) time.Time { return time.Unix(int64(t), 0).Local() },
+	}
+	p.Y.Label.Text = unitsLabel(series)
+	p.Legend.Top = true
+	p.Add(plotter.NewGrid())
+
+	drawn := 0
+	for i, s := range series {
+		if len(s.Points) == 0 {
+			continue
+		}
+
+		xys := make(plotter.XYs, len(s.Points))
+		for j, point := range s.Points {
+			xys[j].X = float64(point.Time.Unix())
+			xys[j].Y = point.Value
+		}
+
+		line, err := plotter.NewLine(xys)
+		if err != nil {
+			return nil, err
+		}
+		line.Color = plotutil.Color(i)
+		line.Width = vg.Points(1.5)
+		p.Add(line)
+
+		label := s.Name
+		if s.Unit != "" {
+			label += ", " + s.Unit
+		}
+		p.Legend.Add(label, line)
+
+		extremes, err := extremesLabels(xys, s.Unit, plotutil.Color(i))
+		if err != nil {
+			return nil, err
+		}
+		p.Add(extremes...)
+		drawn++
+	}
+
+	if drawn == 0 {
+		return nil, fmt.Errorf("нет числовых данных за период")
+	}
+
+	// Запас сверху под легенду и подписи экстремумов
+	p.Y.Max += (p.Y.Max - p.Y.Min) * 0.15
+
+	writer, err := p.WriterTo(width, height, "png")
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	if _, err := writer.WriteTo(&buffer); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// extremesLabels отмечает точки минимума и максимума ряда и подписывает значения
+func extremesLabels(xys plotter.XYs, unit string, c color.Color) ([]plot.Plotter, error) {
+	minIndex, maxIndex := 0, 0
+	for i, xy := range xys {
+		if xy.Y < xys[minIndex].Y {
+			minIndex = i
+		}
+		if xy.Y > xys[maxIndex].Y {
+			maxIndex = i
+		}
+	}
+
+	points := plotter.XYs{xys[minIndex], xys[maxIndex]}
+	scatter, err := plotter.NewScatter(points)
+	if err != nil {
+		return nil, err
+	}
+	scatter.GlyphStyle.Color = c
+	scatter.GlyphStyle.Radius = vg.Points(3)
+	scatter.GlyphStyle.Shape = draw.CircleGlyph{}
+
+	labels, err := plotter.NewLabels(plotter.XYLabels{
+		XYs: points,
+		Labels: []string{
+			"min " + formatValue(xys[minIndex].Y, unit),
+			"max " + formatValue(xys[maxIndex].Y, unit),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range labels.TextStyle {
+		labels.TextStyle[i].Color = c
+		labels.TextStyle[i].XAlign = -0.5
+	}
+	labels.Offset = vg.Point{Y: vg.Points(4)}
+
+	return []plot.Plotter{scatter, labels}, nil
+}
+
+func formatValue(value float64, unit string) string {
+	text := fmt.Sprintf("%.1f", value)
+	if value == math.Trunc(value) {
+		text = fmt.Sprintf("%.0f", value)
+	}
+	if unit != "" {
+		text += " " + unit
+	}
+	return text
+}
+
+// unitsLabel подписывает ось Y единицами измерения всех рядов
+func unitsLabel(series []Series) string {
+	var units []string
+	for _, s := range series {
+		if s.Unit != "" && !slices.Contains(units, s.Unit) {
+			units = append(units, s.Unit)
+		}
+	}
+	return strings.Join(units, " / ")
+}
+
+// timeFormat выбирает формат подписей оси времени по длине периода
+func timeFormat(series []Series) string {
+	var first, last time.Time
+	for _, s := range series {
+		if len(s.Points) == 0 {
+			continue
+		}
+		if first.IsZero() || s.Points[0].Time.Before(first) {
+			first = s.Points[0].Time
+		}
+		if end := s.Points[len(s.Points)-1].Time; end.After(last) {
+			last = end
+		}
+	}
+
+	if last.Sub(first) <= 48*time.Hour {
+		return "15:04"
+	}
+	return "02.01"
+}
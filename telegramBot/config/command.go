@@ -0,0 +1,187 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/joho/godotenv"
+)
+
+const usage = `Использование: telegram-bot config check|keygen|encrypt|decrypt
+
+  check                       проверить конфигурацию и показать итоговые значения (секреты скрыты)
+  keygen                      создать ключ для SECRETS_KEY
+  encrypt <файл .env> [-o путь]  зашифровать секреты ключом SECRETS_KEY (по умолчанию в SECRETS_FILE)
+  decrypt [-o путь]           расшифровать SECRETS_FILE для правки (по умолчанию в stdout)
+`
+
+// Setting - итоговое значение параметра и его источник; значения секретов скрыты
+type Setting struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+// Settings возвращает итоговые значения всех параметров по алфавиту
+func (c *Config) Settings() []Setting {
+	settings := make([]Setting, 0, len(c.values))
+	for key, v := range c.values {
+		raw := v.raw
+		if secretKeys[key] && raw != "" {
+			raw = "********"
+		}
+		settings = append(settings, Setting{Key: key, Value: raw, Source: v.source})
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings
+}
+
+// Run выполняет подкоманду config и возвращает код завершения процесса
+func Run(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	flags := flag.NewFlagSet("config", flag.ContinueOnError)
+	output := flags.String("o", "", "куда записать результат")
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+
+	// Флаги допускаются и до, и после аргументов: config encrypt s.env -o out.enc
+	var positional []string
+	rest := args[1:]
+	for {
+		if err := flags.Parse(rest); err != nil {
+			return 2
+		}
+		if flags.NArg() == 0 {
+			break
+		}
+		positional = append(positional, flags.Arg(0))
+		rest = flags.Args()[1:]
+	}
+
+	var err error
+	switch args[0] {
+	case "check":
+		return check(os.Stdout)
+	case "keygen":
+		err = keygen(os.Stdout)
+	case "encrypt":
+		if len(positional) != 1 {
+			flags.Usage()
+			return 2
+		}
+		err = encrypt(positional[0], *output)
+	case "decrypt":
+		err = decrypt(*output)
+	default:
+		flags.Usage()
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// check печатает итоговую конфигурацию и все найденные проблемы
+func check(w io.Writer) int {
+	config, err := Load()
+	fmt.Fprintf(w, "Профиль: %s\nФайл конфигурации: %s\n\n", config.Profile, config.File)
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, setting := range config.Settings() {
+		fmt.Fprintf(table, "%s\t%s\t# %s\n", setting.Key, setting.Value, setting.Source)
+	}
+	table.Flush()
+
+	var validation *ValidationError
+	if errors.As(err, &validation) {
+		fmt.Fprintf(w, "\n❌ Ошибки конфигурации (%d):\n", len(validation.Problems))
+		for _, problem := range validation.Problems {
+			fmt.Fprintf(w, "  • %s\n", problem)
+		}
+		return 1
+	}
+	fmt.Fprintln(w, "\n✅ Конфигурация без ошибок")
+	return 0
+}
+
+func keygen(w io.Writer) error {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w, base64.StdEncoding.EncodeToString(key))
+	return err
+}
+
+// encrypt шифрует файл секретов в формате .env. Исходный файл после этого лучше удалить
+func encrypt(input, output string) error {
+	_ = godotenv.Load()
+	key, err := SecretsKey()
+	if err != nil {
+		return err
+	}
+	plaintext, err := os.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	if _, err := godotenv.UnmarshalBytes(plaintext); err != nil {
+		return fmt.Errorf("%s: %v", input, err)
+	}
+
+	data, err := EncryptSecrets(key, plaintext)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		output, _ = SecretsFilePath(filePath())
+	}
+	if err := writePrivate(output, data); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "🔐 Секреты зашифрованы в %s\n", output)
+	return nil
+}
+
+func decrypt(output string) error {
+	_ = godotenv.Load()
+	key, err := SecretsKey()
+	if err != nil {
+		return err
+	}
+	path, _ := SecretsFilePath(filePath())
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	plaintext, err := DecryptSecrets(key, data)
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(plaintext)
+		return err
+	}
+	return writePrivate(output, plaintext)
+}
+
+// writePrivate записывает файл с правами 0600. WriteFile не меняет права существующего
+// файла, а доступный всем файл секретов не пройдет проверку при запуске
+func writePrivate(path string, data []byte) error {
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0o600)
+}
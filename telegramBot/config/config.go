@@ -1,53 +1,369 @@
 package config
 
 import (
-	"os"
-	"strconv"
-
-	"github.com/joho/godotenv"
+	"time"
 )
 
 type Config struct {
-	TelegramToken      string
-	Debug              bool
-	MaxLengthAPIOutput int
-	YandexDiskToken    string
-	UrlYandexDisk      string
+	// Профиль (dev или prod) и файл конфигурации, который проверяется на изменения
+	Profile       string
+	File          string
+	WatchInterval time.Duration // 0 - не следить за файлом
+
+	// Итоговые значения параметров по именам переменных окружения и их источники -
+	// для перезагрузки и config check
+	values map[string]value
+	// Значения при последнем чтении источников. Reload сравнивает новое чтение с ними,
+	// а не с действующими values, чтобы не сообщать повторно об уже известных
+	// изменениях, которые ждут перезапуска
+	loaded map[string]value
+
+	TelegramToken   string
+	Debug           bool
+	LogFormat       string   // text или json
+	LogLevels       []string // уровни компонентов: "telegram=debug,yandex=warn"
+	LogBuffer       int      // сколько последних записей лога хранить в памяти для /logs
+	YandexDiskToken string   // статический токен; токен из /connectdisk важнее
+	UrlYandexDisk   string
+	YandexOAuth     YandexOAuthConfig
+
+	// Версионирование файлов на Яндекс.Диске
+	VersionsKeep   int           // сколько предыдущих версий хранить (0 - версионирование выключено)
+	VersionsMaxAge time.Duration // максимальный возраст версии (0 - без ограничения)
+	VersionedPaths []string      // директории с версионированием (пусто - все, если VersionsKeep > 0)
+
+	// Сохранение файлов по ссылкам
+	DownloadsDir string // папка на диске по умолчанию
+	OfferLinks   bool   // предлагать сохранение для ссылок в сообщениях
+
+	// Администраторы и чат для служебных уведомлений
+	AdminIDs      []int64
+	AdminChatID   int64
+	AdminThreadID int
+
+	// Как часто пересылать администраторам накопившиеся ошибки (0 - не пересылать)
+	ErrorReportInterval time.Duration
+
+	// Псевдонимы чатов для маршрутизации уведомлений: "family=-1001234567890:5"
+	ChatAliases []string
+
+	// Каталог для состояния бота (подписки, история и т.п.)
+	DataDir string
+
+	Backup  BackupConfig
+	MQTT    MQTTConfig
+	Zigbee  ZigbeeConfig
+	HA      HomeAssistantConfig
+	Gateway GatewayConfig
+	Camera  CameraConfig
+	Chart   ChartConfig
+	Energy  EnergyConfig
+	Host    HostConfig
+	Docker  DockerConfig
+	LAN     LANConfig
+	Metrics MetricsConfig
 }
 
-func LoadConfig() *Config {
-	_ = godotenv.Load()
+// YandexOAuthConfig - подключение Яндекс.Диска через OAuth по /connectdisk
+type YandexOAuthConfig struct {
+	URL           string // OAuth-сервер (для тестов можно подменить локальным)
+	ClientID      string // приложение на oauth.yandex.ru (пусто - /connectdisk выключен)
+	ClientSecret  string
+	RefreshBefore time.Duration // за сколько до истечения обновлять токен
+}
 
-	return &Config{
-		TelegramToken:      getEnv("TELEGRAM_BOT_TOKEN", ""),
-		Debug:              getEnvAsBool("DEBUG", false),
-		MaxLengthAPIOutput: getEnvAsInt("MAX_LENGTH_MESSEGE_API", 200),
-		YandexDiskToken:    getEnv("YANDEX_DISK_TOKEN", ""),
-		UrlYandexDisk:      getEnv("YANDEX_DISK_URL", "https://cloud-api.yandex.net/v1/disk"),
-	}
+// BackupConfig - резервное копирование конфигурации Home Assistant на Яндекс.Диск
+type BackupConfig struct {
+	Dirs        []string // каталоги для архивации: "имя=путь" или просто "путь"
+	Exclude     []string // glob-шаблоны исключаемых файлов
+	Schedule    string   // cron-расписание (пусто - выключено)
+	RemoteDir   string   // папка на Яндекс.Диске
+	Prefix      string   // префикс имени архива
+	KeepDaily   int
+	KeepWeekly  int
+	KeepMonthly int
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
+// MQTTConfig - подключение к брокеру Mosquitto
+type MQTTConfig struct {
+	Broker      string // tcp://host:1883 или ssl://host:8883 (пусто - MQTT выключен)
+	Username    string
+	Password    string
+	ClientID    string
+	TLSCA       string // путь к сертификату CA
+	TLSCert     string // клиентский сертификат
+	TLSKey      string // ключ клиентского сертификата
+	TLSInsecure bool   // не проверять сертификат брокера
 }
 
-func getEnvAsBool(key string, defaultValue bool) bool {
-	if value := os.Getenv(key); value != "" {
-		if boolValue, err := strconv.ParseBool(value); err == nil {
-			return boolValue
-		}
-	}
-	return defaultValue
+// ZigbeeConfig - устройства zigbee2mqtt
+type ZigbeeConfig struct {
+	BaseTopic string // base_topic из zigbee2mqtt.yaml
+
+	// Пороги оповещений. Оповещение снимается, когда значение превышает порог на величину гистерезиса.
+	BatteryLow            int
+	BatteryHysteresis     int
+	LinkQualityLow        int
+	LinkQualityHysteresis int
+	OfflineAfter          time.Duration // устройство считается недоступным, если last_seen старше
+	MonitorInterval       time.Duration
+
+	MapArchiveDir string // папка на Яндекс.Диске для копий карты сети (пусто - не сохранять)
 }
 
-func getEnvAsInt(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
-		}
+// HomeAssistantConfig - подключение к Home Assistant
+type HomeAssistantConfig struct {
+	URL       string
+	Token     string   // долгосрочный токен доступа (пусто - интеграция выключена)
+	Favorites []string // скрипты и сцены для кнопок: "Название=script.id"
+}
+
+// GatewayConfig - локальный HTTP API для уведомлений
+type GatewayConfig struct {
+	Listen        string
+	Token         string // bearer-токен (пусто - API выключен)
+	DefaultChat   string // чат, если в запросе не указан: псевдоним или chat_id[:thread_id]
+	ArchiveDir    string // папка на Яндекс.Диске для вложений (пусто - не архивировать)
+	ArchiveAll    bool   // архивировать все вложения, а не только с archive=true
+	MaxUploadSize int64
+}
+
+// CameraConfig - снимки камер Home Assistant
+type CameraConfig struct {
+	Cameras       []string // "имя=camera.entity_id" или просто "camera.entity_id"
+	Schedule      string   // cron-расписание снимков в архив (пусто - выключено)
+	Triggers      []string // "binary_sensor.front_door=имя_камеры": снимок при переходе в on/open
+	Chat          string   // куда отправлять снимки по событиям (пусто - только архив)
+	RemoteDir     string   // папка на Яндекс.Диске
+	RetentionDays int      // сколько дней хранить снимки (0 - бессрочно)
+}
+
+// ChartConfig - ежедневная отправка графиков
+type ChartConfig struct {
+	Entities []string // сущности на одном графике (пусто - выключено)
+	Period   time.Duration
+	Schedule string
+	Chat     string // псевдоним или chat_id[:thread_id]
+}
+
+// EnergyConfig - учет электроэнергии по умным розеткам
+type EnergyConfig struct {
+	Devices        []string // устройства zigbee2mqtt с energy/power ("*" - все)
+	HAEntities     []string // счетчики Home Assistant: "имя=sensor.energy[/sensor.power]"
+	SampleInterval time.Duration
+
+	// Тариф: если ночной тариф 0, весь расход считается по дневному
+	TariffDay   float64
+	TariffNight float64
+	NightStart  string // ЧЧ:ММ
+	NightEnd    string
+	Currency    string
+
+	ReportSchedule string // cron-расписание отчета за вчера (пусто - выключено)
+	ReportChat     string
+	ExportDir      string // папка на Яндекс.Диске для CSV
+	ExportSchedule string // cron-расписание выгрузки за прошлый месяц (пусто - выключено)
+}
+
+// HostConfig - мониторинг сервера (OrangePi)
+type HostConfig struct {
+	ProcPath  string   // /proc хоста (в контейнере можно смонтировать отдельно)
+	SysPath   string   // /sys хоста
+	DiskPaths []string // файловые системы для контроля места
+
+	MonitorInterval time.Duration // 0 - фоновый мониторинг выключен
+	TempHigh        float64       // °C
+	TempHysteresis  float64
+	DiskFreeLow     float64 // % свободного места
+	DiskHysteresis  float64
+	LoadHigh        float64       // load average (5 мин) на одно ядро
+	LoadDuration    time.Duration // сколько нагрузка должна держаться, чтобы поднять тревогу
+}
+
+// DockerConfig - управление контейнерами через Docker Engine API
+type DockerConfig struct {
+	Socket     string   // путь к docker.sock (пусто - выключено)
+	Containers []string // наблюдаемые контейнеры (пусто - все)
+
+	MonitorInterval   time.Duration
+	CrashLoopRestarts int           // столько перезапусков...
+	CrashLoopWindow   time.Duration // ...за это время считаются циклическими
+}
+
+// LANConfig - Wake-on-LAN и присутствие устройств в локальной сети.
+// Устройства описываются в DATA_DIR/lan_devices.json
+type LANConfig struct {
+	Broadcast    string        // адрес для magic packet по умолчанию
+	ProbeTimeout time.Duration // таймаут ICMP/TCP-проверки
+
+	PresenceInterval time.Duration // 0 - уведомления о приходе/уходе выключены
+	PresenceChat     string        // псевдоним или chat_id[:thread_id]
+	AwayAfter        time.Duration // сколько устройство должно отсутствовать, чтобы считаться ушедшим
+}
+
+// MetricsConfig - метрики Prometheus и проверки состояния (/metrics, /healthz, /readyz)
+type MetricsConfig struct {
+	Listen string // адрес HTTP-сервера (пусто - выключено)
+}
+
+// build собирает конфигурацию из значений загрузчика
+func build(l *loader) *Config {
+	return &Config{
+		Profile:       l.getEnv("PROFILE", "prod"),
+		WatchInterval: l.getEnvAsDuration("CONFIG_WATCH_INTERVAL", 10*time.Second),
+
+		TelegramToken:   l.getEnv("TELEGRAM_BOT_TOKEN", ""),
+		Debug:           l.getEnvAsBool("DEBUG", false),
+		LogFormat:       l.getEnv("LOG_FORMAT", "text"),
+		LogLevels:       l.getEnvAsSlice("LOG_LEVELS", nil),
+		LogBuffer:       l.getEnvAsInt("LOG_BUFFER", 1000),
+		YandexDiskToken: l.getEnv("YANDEX_DISK_TOKEN", ""),
+		UrlYandexDisk:   l.getEnv("YANDEX_DISK_URL", "https://cloud-api.yandex.net/v1/disk"),
+		YandexOAuth: YandexOAuthConfig{
+			URL:           l.getEnv("YANDEX_OAUTH_URL", "https://oauth.yandex.ru"),
+			ClientID:      l.getEnv("YANDEX_CLIENT_ID", ""),
+			ClientSecret:  l.getEnv("YANDEX_CLIENT_SECRET", ""),
+			RefreshBefore: l.getEnvAsDuration("YANDEX_TOKEN_REFRESH_BEFORE", 7*24*time.Hour),
+		},
+
+		VersionsKeep:   l.getEnvAsInt("YANDEX_VERSIONS_KEEP", 0),
+		VersionsMaxAge: l.getEnvAsDuration("YANDEX_VERSIONS_MAX_AGE", 0),
+		VersionedPaths: l.getEnvAsSlice("YANDEX_VERSIONED_PATHS", nil),
+
+		DownloadsDir: l.getEnv("YANDEX_DOWNLOADS_DIR", "/Downloads"),
+		OfferLinks:   l.getEnvAsBool("YANDEX_OFFER_LINKS", true),
+
+		AdminIDs:      l.getEnvAsInt64Slice("ADMIN_IDS", nil),
+		AdminChatID:   l.getEnvAsInt64("ADMIN_CHAT_ID", 0),
+		AdminThreadID: l.getEnvAsInt("ADMIN_THREAD_ID", 0),
+
+		ErrorReportInterval: l.getEnvAsDuration("ERROR_REPORT_INTERVAL", time.Minute),
+
+		ChatAliases: l.getEnvAsSlice("CHAT_ALIASES", nil),
+
+		DataDir: l.getEnv("DATA_DIR", "data"),
+
+		Backup: BackupConfig{
+			Dirs: l.getEnvAsSlice("BACKUP_DIRS", []string{
+				"homeassistant=/backup/homeassistant",
+				"mosquitto=/backup/mosquitto",
+				"zigbee2mqtt=/backup/zigbee2mqtt",
+			}),
+			Exclude:     l.getEnvAsSlice("BACKUP_EXCLUDE", []string{"*.log", "*.db-shm", "*.db-wal", "home-assistant_v2.db"}),
+			Schedule:    l.getEnv("BACKUP_SCHEDULE", "0 3 * * *"),
+			RemoteDir:   l.getEnv("BACKUP_REMOTE_DIR", "/Backups/HomeAssistant"),
+			Prefix:      l.getEnv("BACKUP_PREFIX", "homeassistant"),
+			KeepDaily:   l.getEnvAsInt("BACKUP_KEEP_DAILY", 7),
+			KeepWeekly:  l.getEnvAsInt("BACKUP_KEEP_WEEKLY", 4),
+			KeepMonthly: l.getEnvAsInt("BACKUP_KEEP_MONTHLY", 12),
+		},
+
+		MQTT: MQTTConfig{
+			Broker:      l.getEnv("MQTT_BROKER", ""),
+			Username:    l.getEnv("MQTT_USERNAME", ""),
+			Password:    l.getEnv("MQTT_PASSWORD", ""),
+			ClientID:    l.getEnv("MQTT_CLIENT_ID", "telegram-bot"),
+			TLSCA:       l.getEnv("MQTT_TLS_CA", ""),
+			TLSCert:     l.getEnv("MQTT_TLS_CERT", ""),
+			TLSKey:      l.getEnv("MQTT_TLS_KEY", ""),
+			TLSInsecure: l.getEnvAsBool("MQTT_TLS_INSECURE", false),
+		},
+
+		Zigbee: ZigbeeConfig{
+			BaseTopic: l.getEnv("ZIGBEE_BASE_TOPIC", "zigbee2mqtt"),
+
+			BatteryLow:            l.getEnvAsInt("ZIGBEE_BATTERY_LOW", 20),
+			BatteryHysteresis:     l.getEnvAsInt("ZIGBEE_BATTERY_HYSTERESIS", 5),
+			LinkQualityLow:        l.getEnvAsInt("ZIGBEE_LINKQUALITY_LOW", 20),
+			LinkQualityHysteresis: l.getEnvAsInt("ZIGBEE_LINKQUALITY_HYSTERESIS", 15),
+			OfflineAfter:          l.getEnvAsDuration("ZIGBEE_OFFLINE_AFTER", 25*time.Hour),
+			MonitorInterval:       l.getEnvAsDuration("ZIGBEE_MONITOR_INTERVAL", time.Minute),
+
+			MapArchiveDir: l.getEnv("ZIGBEE_MAP_ARCHIVE_DIR", ""),
+		},
+
+		HA: HomeAssistantConfig{
+			URL:       l.getEnv("HA_URL", "http://localhost:8123"),
+			Token:     l.getEnv("HA_TOKEN", ""),
+			Favorites: l.getEnvAsSlice("HA_FAVORITES", nil),
+		},
+
+		Gateway: GatewayConfig{
+			Listen:        l.getEnv("NOTIFY_LISTEN", "127.0.0.1:8088"),
+			Token:         l.getEnv("NOTIFY_TOKEN", ""),
+			DefaultChat:   l.getEnv("NOTIFY_DEFAULT_CHAT", "admin"),
+			ArchiveDir:    l.getEnv("NOTIFY_ARCHIVE_DIR", ""),
+			ArchiveAll:    l.getEnvAsBool("NOTIFY_ARCHIVE_ALL", false),
+			MaxUploadSize: l.getEnvAsInt64("NOTIFY_MAX_UPLOAD_SIZE", 50<<20),
+		},
+
+		Camera: CameraConfig{
+			Cameras:       l.getEnvAsSlice("CAMERAS", nil),
+			Schedule:      l.getEnv("CAMERA_SCHEDULE", ""),
+			Triggers:      l.getEnvAsSlice("CAMERA_TRIGGERS", nil),
+			Chat:          l.getEnv("CAMERA_CHAT", ""),
+			RemoteDir:     l.getEnv("CAMERA_REMOTE_DIR", "/Cameras"),
+			RetentionDays: l.getEnvAsInt("CAMERA_RETENTION_DAYS", 14),
+		},
+
+		Chart: ChartConfig{
+			Entities: l.getEnvAsSlice("CHART_DAILY_ENTITIES", nil),
+			Period:   l.getEnvAsDuration("CHART_DAILY_PERIOD", 24*time.Hour),
+			Schedule: l.getEnv("CHART_DAILY_SCHEDULE", "0 9 * * *"),
+			Chat:     l.getEnv("CHART_DAILY_CHAT", "admin"),
+		},
+
+		Energy: EnergyConfig{
+			Devices:        l.getEnvAsSlice("ENERGY_DEVICES", nil),
+			HAEntities:     l.getEnvAsSlice("ENERGY_HA_ENTITIES", nil),
+			SampleInterval: l.getEnvAsDuration("ENERGY_SAMPLE_INTERVAL", 5*time.Minute),
+
+			TariffDay:   l.getEnvAsFloat("ENERGY_TARIFF_DAY", 0),
+			TariffNight: l.getEnvAsFloat("ENERGY_TARIFF_NIGHT", 0),
+			NightStart:  l.getEnv("ENERGY_NIGHT_START", "23:00"),
+			NightEnd:    l.getEnv("ENERGY_NIGHT_END", "07:00"),
+			Currency:    l.getEnv("ENERGY_CURRENCY", "₽"),
+
+			ReportSchedule: l.getEnv("ENERGY_REPORT_SCHEDULE", "0 8 * * *"),
+			ReportChat:     l.getEnv("ENERGY_REPORT_CHAT", "admin"),
+			ExportDir:      l.getEnv("ENERGY_EXPORT_DIR", "/Energy"),
+			ExportSchedule: l.getEnv("ENERGY_EXPORT_SCHEDULE", "0 9 1 * *"),
+		},
+
+		Host: HostConfig{
+			ProcPath:  l.getEnv("HOST_PROC", "/proc"),
+			SysPath:   l.getEnv("HOST_SYS", "/sys"),
+			DiskPaths: l.getEnvAsSlice("HOST_DISK_PATHS", []string{"/"}),
+
+			MonitorInterval: l.getEnvAsDuration("HOST_MONITOR_INTERVAL", time.Minute),
+			TempHigh:        l.getEnvAsFloat("HOST_TEMP_HIGH", 75),
+			TempHysteresis:  l.getEnvAsFloat("HOST_TEMP_HYSTERESIS", 5),
+			DiskFreeLow:     l.getEnvAsFloat("HOST_DISK_FREE_LOW", 10),
+			DiskHysteresis:  l.getEnvAsFloat("HOST_DISK_HYSTERESIS", 5),
+			LoadHigh:        l.getEnvAsFloat("HOST_LOAD_HIGH", 1.5),
+			LoadDuration:    l.getEnvAsDuration("HOST_LOAD_DURATION", 10*time.Minute),
+		},
+
+		Docker: DockerConfig{
+			Socket:     l.getEnv("DOCKER_SOCKET", "/var/run/docker.sock"),
+			Containers: l.getEnvAsSlice("DOCKER_CONTAINERS", nil),
+
+			MonitorInterval:   l.getEnvAsDuration("DOCKER_MONITOR_INTERVAL", time.Minute),
+			CrashLoopRestarts: l.getEnvAsInt("DOCKER_CRASHLOOP_RESTARTS", 3),
+			CrashLoopWindow:   l.getEnvAsDuration("DOCKER_CRASHLOOP_WINDOW", 10*time.Minute),
+		},
+
+		LAN: LANConfig{
+			Broadcast:    l.getEnv("LAN_BROADCAST", "255.255.255.255:9"),
+			ProbeTimeout: l.getEnvAsDuration("LAN_PROBE_TIMEOUT", time.Second),
+
+			PresenceInterval: l.getEnvAsDuration("LAN_PRESENCE_INTERVAL", 0),
+			PresenceChat:     l.getEnv("LAN_PRESENCE_CHAT", "admin"),
+			AwayAfter:        l.getEnvAsDuration("LAN_AWAY_AFTER", 10*time.Minute),
+		},
+
+		Metrics: MetricsConfig{
+			Listen: l.getEnv("METRICS_LISTEN", "127.0.0.1:9102"),
+		},
 	}
-	return defaultValue
 }
@@ -0,0 +1,376 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Встроенные значения профилей: применяются поверх значений по умолчанию,
+// файл и переменные окружения их переопределяют
+var profiles = map[string]map[string]string{
+	"dev": {
+		"DEBUG":                 "true",
+		"LOG_FORMAT":            "text",
+		"ERROR_REPORT_INTERVAL": "0",
+	},
+	"prod": {},
+}
+
+// value - сырое значение параметра и откуда оно взято
+type value struct {
+	raw    string
+	source string
+}
+
+// loader собирает значения параметров по именам переменных окружения и копит
+// ошибки разбора, чтобы сообщить обо всех сразу
+type loader struct {
+	env      bool // читать ли переменные окружения и поставщиков секретов
+	values   map[string]value
+	fileKeys []string // параметры из файла - для поиска опечаток
+	used     map[string]bool
+	defaults map[string]string
+	problems []string
+
+	providers []SecretProvider
+	secrets   map[string]value // уже найденные секреты, чтобы спрашивать поставщиков один раз
+}
+
+// newLoader читает профиль, файл конфигурации и переменные окружения
+func newLoader(path string) *loader {
+	l := &loader{env: true, values: make(map[string]value), used: make(map[string]bool),
+		defaults: make(map[string]string), secrets: make(map[string]value)}
+	l.providers, l.problems = newSecretProviders(path)
+
+	fileValues, fileProfiles, fileProfile, err := readFile(path)
+	if err != nil {
+		l.problems = append(l.problems, err.Error())
+	}
+
+	profile := firstNonEmpty(os.Getenv("PROFILE"), fileProfile, "prod")
+	if _, ok := profiles[profile]; !ok {
+		l.problems = append(l.problems, fmt.Sprintf("PROFILE: неизвестный профиль %q (ожидается dev или prod)", profile))
+	}
+	l.values["PROFILE"] = value{raw: profile, source: "профиль"}
+
+	for key, raw := range profiles[profile] {
+		l.values[key] = value{raw: raw, source: "профиль " + profile}
+	}
+	for key, raw := range fileValues {
+		l.values[key] = value{raw: raw, source: path}
+		l.fileKeys = append(l.fileKeys, key)
+	}
+	for name, section := range fileProfiles {
+		if _, ok := profiles[name]; !ok {
+			l.problems = append(l.problems, fmt.Sprintf("%s: неизвестный профиль profiles.%s", path, name))
+			continue
+		}
+		if name != profile {
+			continue
+		}
+		for key, raw := range section {
+			l.values[key] = value{raw: raw, source: fmt.Sprintf("%s (profiles.%s)", path, name)}
+			l.fileKeys = append(l.fileKeys, key)
+		}
+	}
+	return l
+}
+
+// newMapLoader собирает конфигурацию из готовых итоговых значений
+func newMapLoader(values map[string]value) *loader {
+	return &loader{values: values, used: make(map[string]bool), defaults: make(map[string]string)}
+}
+
+// readFile разбирает YAML-файл конфигурации. Вложенные ключи склеиваются через "_"
+// и приводятся к верхнему регистру: backup: {schedule: ...} - это BACKUP_SCHEDULE.
+// Списки превращаются в значения через запятую, как в переменных окружения
+func readFile(path string) (values map[string]string, sections map[string]map[string]string, profile string, err error) {
+	if path == "" {
+		return nil, nil, "", nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil, "", nil
+	}
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("%s: %v", path, err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, nil, "", fmt.Errorf("%s: %v", path, err)
+	}
+	if len(root.Content) == 0 {
+		return nil, nil, "", nil
+	}
+	document := root.Content[0]
+	if document.Kind != yaml.MappingNode {
+		return nil, nil, "", fmt.Errorf("%s: ожидается словарь параметров", path)
+	}
+
+	values = make(map[string]string)
+	sections = make(map[string]map[string]string)
+	var problems []string
+	for i := 0; i+1 < len(document.Content); i += 2 {
+		key, node := document.Content[i].Value, document.Content[i+1]
+		switch strings.ToLower(key) {
+		case "profile":
+			profile = node.Value
+		case "profiles":
+			if node.Kind != yaml.MappingNode {
+				problems = append(problems, fmt.Sprintf("строка %d: profiles должен быть словарем", node.Line))
+				continue
+			}
+			for j := 0; j+1 < len(node.Content); j += 2 {
+				section := make(map[string]string)
+				problems = append(problems, flatten("", node.Content[j+1], section)...)
+				sections[node.Content[j].Value] = section
+			}
+		default:
+			problems = append(problems, flatten(key, node, values)...)
+		}
+	}
+	if len(problems) > 0 {
+		return values, sections, profile, fmt.Errorf("%s: %s", path, strings.Join(problems, "; "))
+	}
+	return values, sections, profile, nil
+}
+
+func flatten(prefix string, node *yaml.Node, values map[string]string) []string {
+	name := strings.ToUpper(prefix)
+	switch node.Kind {
+	case yaml.MappingNode:
+		var problems []string
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if prefix != "" {
+				key = prefix + "_" + key
+			}
+			problems = append(problems, flatten(key, node.Content[i+1], values)...)
+		}
+		return problems
+	case yaml.SequenceNode:
+		items := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return []string{fmt.Sprintf("строка %d: %s: элементы списка должны быть строками", item.Line, name)}
+			}
+			items = append(items, item.Value)
+		}
+		values[name] = strings.Join(items, ",")
+	case yaml.ScalarNode:
+		values[name] = node.Value
+	default:
+		return []string{fmt.Sprintf("строка %d: %s: неподдерживаемое значение", node.Line, name)}
+	}
+	return nil
+}
+
+// lookup возвращает значение параметра: переменная окружения важнее файла и профиля.
+// Секреты сначала ищутся у поставщиков секретов в порядке SECRET_PROVIDERS
+func (l *loader) lookup(key string) (value, bool) {
+	l.used[key] = true
+	if l.env && secretKeys[key] {
+		if v, ok := l.lookupSecret(key); ok {
+			return v, true
+		}
+	} else if raw := os.Getenv(key); l.env && raw != "" {
+		return value{raw: raw, source: "окружение"}, true
+	}
+	v, ok := l.values[key]
+	if ok && v.raw == "" {
+		return v, false
+	}
+	return v, ok
+}
+
+func (l *loader) lookupSecret(key string) (value, bool) {
+	if v, ok := l.secrets[key]; ok {
+		return v, v.raw != ""
+	}
+
+	var v value
+	for _, provider := range l.providers {
+		secret, source, err := provider.Lookup(key)
+		if err != nil {
+			l.problems = append(l.problems, err.Error())
+			break
+		}
+		if source != "" {
+			v = value{raw: secret, source: source}
+			break
+		}
+	}
+	l.secrets[key] = v
+	return v, v.raw != ""
+}
+
+// fallback запоминает значение по умолчанию для config check
+func (l *loader) fallback(key, raw string) {
+	l.defaults[key] = raw
+}
+
+func (l *loader) invalid(key string, v value, expected string) {
+	l.problems = append(l.problems, fmt.Sprintf("%s: ожидается %s, получено %q (%s)", key, expected, v.raw, v.source))
+}
+
+// unknownKeys сообщает о параметрах из файла, которые никто не читает - обычно это опечатки
+func (l *loader) unknownKeys() {
+	var unknown []string
+	for _, key := range l.fileKeys {
+		if !l.used[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		l.problems = append(l.problems, fmt.Sprintf("%s: неизвестный параметр (%s)", key, l.values[key].source))
+	}
+}
+
+// effective - итоговые значения всех прочитанных параметров вместе со значениями по умолчанию
+func (l *loader) effective() map[string]value {
+	result := make(map[string]value, len(l.used))
+	for key := range l.used {
+		if v, ok := l.lookup(key); ok {
+			result[key] = v
+		} else if raw, ok := l.defaults[key]; ok {
+			result[key] = value{raw: raw, source: "по умолчанию"}
+		}
+	}
+	return result
+}
+
+func (l *loader) getEnv(key, defaultValue string) string {
+	if v, ok := l.lookup(key); ok {
+		return v.raw
+	}
+	l.fallback(key, defaultValue)
+	return defaultValue
+}
+
+func (l *loader) getEnvAsBool(key string, defaultValue bool) bool {
+	v, ok := l.lookup(key)
+	if !ok {
+		l.fallback(key, strconv.FormatBool(defaultValue))
+		return defaultValue
+	}
+	boolValue, err := strconv.ParseBool(v.raw)
+	if err != nil {
+		l.invalid(key, v, "true или false")
+		return defaultValue
+	}
+	return boolValue
+}
+
+func (l *loader) getEnvAsInt(key string, defaultValue int) int {
+	v, ok := l.lookup(key)
+	if !ok {
+		l.fallback(key, strconv.Itoa(defaultValue))
+		return defaultValue
+	}
+	intValue, err := strconv.Atoi(v.raw)
+	if err != nil {
+		l.invalid(key, v, "целое число")
+		return defaultValue
+	}
+	return intValue
+}
+
+func (l *loader) getEnvAsInt64(key string, defaultValue int64) int64 {
+	v, ok := l.lookup(key)
+	if !ok {
+		l.fallback(key, strconv.FormatInt(defaultValue, 10))
+		return defaultValue
+	}
+	intValue, err := strconv.ParseInt(v.raw, 10, 64)
+	if err != nil {
+		l.invalid(key, v, "целое число")
+		return defaultValue
+	}
+	return intValue
+}
+
+func (l *loader) getEnvAsFloat(key string, defaultValue float64) float64 {
+	v, ok := l.lookup(key)
+	if !ok {
+		l.fallback(key, strconv.FormatFloat(defaultValue, 'f', -1, 64))
+		return defaultValue
+	}
+	floatValue, err := strconv.ParseFloat(v.raw, 64)
+	if err != nil {
+		l.invalid(key, v, "число")
+		return defaultValue
+	}
+	return floatValue
+}
+
+func (l *loader) getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	v, ok := l.lookup(key)
+	if !ok {
+		l.fallback(key, defaultValue.String())
+		return defaultValue
+	}
+	durationValue, err := time.ParseDuration(v.raw)
+	if err != nil {
+		l.invalid(key, v, "длительность (30s, 5m, 24h)")
+		return defaultValue
+	}
+	return durationValue
+}
+
+// getEnvAsSlice разбирает список значений, разделенных запятыми
+func (l *loader) getEnvAsSlice(key string, defaultValue []string) []string {
+	v, ok := l.lookup(key)
+	if !ok {
+		l.fallback(key, strings.Join(defaultValue, ","))
+		return defaultValue
+	}
+
+	var result []string
+	for _, item := range strings.Split(v.raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// getEnvAsInt64Slice разбирает список чисел (например, ID пользователей), разделенных запятыми
+func (l *loader) getEnvAsInt64Slice(key string, defaultValue []int64) []int64 {
+	items := l.getEnvAsSlice(key, nil)
+	if len(items) == 0 {
+		raw := make([]string, len(defaultValue))
+		for i, item := range defaultValue {
+			raw[i] = strconv.FormatInt(item, 10)
+		}
+		l.fallback(key, strings.Join(raw, ","))
+		return defaultValue
+	}
+
+	var result []int64
+	for _, item := range items {
+		intValue, err := strconv.ParseInt(item, 10, 64)
+		if err != nil {
+			l.problems = append(l.problems, fmt.Sprintf("%s: %q - не число", key, item))
+			continue
+		}
+		result = append(result, intValue)
+	}
+	return result
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
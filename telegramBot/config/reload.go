@@ -0,0 +1,151 @@
+package config
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/joho/godotenv"
+)
+
+// Параметры, которые применяются без перезапуска: маршруты уведомлений, списки
+// доступа, пороги оповещений и уровни логов. Остальные изменения требуют перезапуска
+var liveKeys = map[string]bool{
+	"DEBUG":      true,
+	"LOG_FORMAT": true,
+	"LOG_LEVELS": true,
+	"LOG_BUFFER": true,
+
+	"ADMIN_IDS":       true,
+	"ADMIN_CHAT_ID":   true,
+	"ADMIN_THREAD_ID": true,
+	"CHAT_ALIASES":    true,
+
+	"YANDEX_DOWNLOADS_DIR": true,
+	"YANDEX_OFFER_LINKS":   true,
+
+	"ZIGBEE_BATTERY_LOW":            true,
+	"ZIGBEE_BATTERY_HYSTERESIS":     true,
+	"ZIGBEE_LINKQUALITY_LOW":        true,
+	"ZIGBEE_LINKQUALITY_HYSTERESIS": true,
+	"ZIGBEE_OFFLINE_AFTER":          true,
+
+	"HOST_DISK_PATHS":      true,
+	"HOST_TEMP_HIGH":       true,
+	"HOST_TEMP_HYSTERESIS": true,
+	"HOST_DISK_FREE_LOW":   true,
+	"HOST_DISK_HYSTERESIS": true,
+	"HOST_LOAD_HIGH":       true,
+	"HOST_LOAD_DURATION":   true,
+
+	"DOCKER_CONTAINERS":         true,
+	"DOCKER_CRASHLOOP_RESTARTS": true,
+	"DOCKER_CRASHLOOP_WINDOW":   true,
+}
+
+// Загрузка читает переменные процесса, поэтому одновременно идет только одна
+var loadMu sync.Mutex
+
+// Load читает конфигурацию: значения по умолчанию, профиль (dev или prod), файл
+// CONFIG_FILE (по умолчанию config.yaml, если он есть) и переменные окружения - каждый
+// следующий источник важнее предыдущего. Ошибка *ValidationError перечисляет все проблемы
+func Load() (*Config, error) {
+	loadMu.Lock()
+	defer loadMu.Unlock()
+
+	_ = godotenv.Load()
+	path := filePath()
+
+	l := newLoader(path)
+	config := build(l)
+	config.File = path
+	l.unknownKeys()
+	config.values = l.effective()
+	config.loaded = config.values
+
+	problems := append(l.problems, config.validate()...)
+	problems = append(problems, config.secretFileProblems(l)...)
+	if len(problems) > 0 {
+		return config, &ValidationError{Problems: problems}
+	}
+	return config, nil
+}
+
+// filePath - путь к файлу конфигурации из CONFIG_FILE
+func filePath() string {
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		return path
+	}
+	return "config.yaml"
+}
+
+// Change - изменившийся параметр. Live - применяется без перезапуска
+type Change struct {
+	Key  string
+	Live bool
+}
+
+// Reload перечитывает конфигурацию и возвращает новую, в которой изменены только
+// параметры, применяемые на лету, и список изменений с прошлого чтения. Если новая
+// конфигурация с ошибками, текущая остается в силе
+func (c *Config) Reload() (*Config, []Change, error) {
+	fresh, err := Load()
+	if err != nil {
+		return nil, nil, err
+	}
+	previous := c.loaded
+	if previous == nil {
+		previous = c.values
+	}
+
+	merged := make(map[string]value, len(c.values))
+	for key, v := range c.values {
+		merged[key] = v
+	}
+
+	var changes []Change
+	for _, key := range changedKeys(previous, fresh.values) {
+		live := liveKeys[key]
+		changes = append(changes, Change{Key: key, Live: live})
+		if !live {
+			continue
+		}
+		if v, ok := fresh.values[key]; ok {
+			merged[key] = v
+		} else {
+			delete(merged, key)
+		}
+	}
+
+	next := build(newMapLoader(merged))
+	next.File = c.File
+	next.values = merged
+	next.loaded = fresh.values
+	return next, changes, nil
+}
+
+func changedKeys(old, fresh map[string]value) []string {
+	var keys []string
+	for key, v := range fresh {
+		if previous, ok := old[key]; !ok || previous.raw != v.raw {
+			keys = append(keys, key)
+		}
+	}
+	for key := range old {
+		if _, ok := fresh[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FormatChanges - список параметров через запятую
+func FormatChanges(changes []Change) string {
+	keys := make([]string, len(changes))
+	for i, change := range changes {
+		keys[i] = change.Key
+	}
+	return strings.Join(keys, ", ")
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReloadReportsRestartChangesOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("TELEGRAM_BOT_TOKEN", "1:test")
+	t.Setenv("SECRET_PROVIDERS", "env")
+
+	write("backup:\n  schedule: 0 3 * * *\nlog:\n  format: text\n")
+	current, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reload := func(want ...Change) {
+		t.Helper()
+		next, changes, err := current.Reload()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(changes) != len(want) {
+			t.Fatalf("изменения %v, ожидалось %v", changes, want)
+		}
+		for i := range want {
+			if changes[i] != want[i] {
+				t.Fatalf("изменения %v, ожидалось %v", changes, want)
+			}
+		}
+		current = next
+	}
+
+	write("backup:\n  schedule: 0 4 * * *\nlog:\n  format: text\n")
+	reload(Change{Key: "BACKUP_SCHEDULE"})
+	if current.Backup.Schedule != "0 3 * * *" {
+		t.Fatalf("параметр с перезапуском применен на лету: %q", current.Backup.Schedule)
+	}
+
+	// Повторное чтение без правок и правка другого параметра не повторяют BACKUP_SCHEDULE
+	reload()
+	write("backup:\n  schedule: 0 4 * * *\nlog:\n  format: json\n")
+	reload(Change{Key: "LOG_FORMAT", Live: true})
+	if current.LogFormat != "json" || current.Backup.Schedule != "0 3 * * *" {
+		t.Fatalf("LOG_FORMAT=%q, BACKUP_SCHEDULE=%q", current.LogFormat, current.Backup.Schedule)
+	}
+}
@@ -0,0 +1,266 @@
+package config
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// Параметры с секретами: берутся у поставщиков секретов и не показываются в config check
+var secretKeys = map[string]bool{
+	"TELEGRAM_BOT_TOKEN":   true,
+	"YANDEX_DISK_TOKEN":    true,
+	"YANDEX_CLIENT_SECRET": true,
+	"MQTT_PASSWORD":        true,
+	"HA_TOKEN":             true,
+	"NOTIFY_TOKEN":         true,
+}
+
+// Заголовок зашифрованного файла секретов
+const secretsHeader = "telegram-bot-secrets:v1\n"
+
+// SecretProvider - источник секретов. Lookup возвращает значение и описание источника;
+// пустой источник - у поставщика такого секрета нет
+type SecretProvider interface {
+	Name() string
+	Lookup(key string) (secret, source string, err error)
+}
+
+// Поставщики по именам для SECRET_PROVIDERS; порядок в списке - порядок опроса
+var secretProviderFactories = map[string]func(configPath string) (SecretProvider, error){
+	"env":       func(string) (SecretProvider, error) { return envProvider{}, nil },
+	"file":      func(string) (SecretProvider, error) { return fileProvider{}, nil },
+	"encrypted": newEncryptedProvider,
+}
+
+// newSecretProviders создает поставщиков из SECRET_PROVIDERS (по умолчанию env,file,encrypted)
+func newSecretProviders(configPath string) ([]SecretProvider, []string) {
+	names := "env,file,encrypted"
+	if raw := os.Getenv("SECRET_PROVIDERS"); raw != "" {
+		names = raw
+	}
+
+	var providers []SecretProvider
+	var problems []string
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		factory, ok := secretProviderFactories[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("SECRET_PROVIDERS: неизвестный поставщик %q (ожидается env, file или encrypted)", name))
+			continue
+		}
+		provider, err := factory(configPath)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		if provider != nil {
+			providers = append(providers, provider)
+		}
+	}
+	return providers, problems
+}
+
+// envProvider - секрет в самой переменной окружения (в том числе из .env)
+type envProvider struct{}
+
+func (envProvider) Name() string { return "env" }
+
+func (envProvider) Lookup(key string) (string, string, error) {
+	if secret := os.Getenv(key); secret != "" {
+		return secret, "окружение", nil
+	}
+	return "", "", nil
+}
+
+// fileProvider - секрет в файле, путь к которому в KEY_FILE. Так подключаются Docker secrets:
+// TELEGRAM_BOT_TOKEN_FILE=/run/secrets/telegram_bot_token
+type fileProvider struct{}
+
+func (fileProvider) Name() string { return "file" }
+
+func (fileProvider) Lookup(key string) (string, string, error) {
+	path := os.Getenv(key + "_FILE")
+	if path == "" {
+		return "", "", nil
+	}
+	if err := checkSecretFile(path); err != nil {
+		return "", "", fmt.Errorf("%s_FILE: %v", key, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("%s_FILE: %v", key, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), "файл " + path, nil
+}
+
+// encryptedProvider - локальный файл секретов в формате .env, зашифрованный AES-256-GCM.
+// Ключ берется из SECRETS_KEY (32 байта в base64), файл - из SECRETS_FILE
+// (по умолчанию secrets.enc рядом с файлом конфигурации)
+type encryptedProvider struct {
+	path    string
+	secrets map[string]string
+}
+
+func newEncryptedProvider(configPath string) (SecretProvider, error) {
+	path, explicit := SecretsFilePath(configPath)
+	if _, err := os.Stat(path); os.IsNotExist(err) && !explicit {
+		return nil, nil
+	}
+	if err := checkSecretFile(path); err != nil {
+		return nil, fmt.Errorf("SECRETS_FILE: %v", err)
+	}
+
+	key, err := SecretsKey()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("SECRETS_FILE: %v", err)
+	}
+	plaintext, err := DecryptSecrets(key, data)
+	if err != nil {
+		return nil, fmt.Errorf("SECRETS_FILE: %s: %v", path, err)
+	}
+	secrets, err := godotenv.UnmarshalBytes(plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("SECRETS_FILE: %s: %v", path, err)
+	}
+	return &encryptedProvider{path: path, secrets: secrets}, nil
+}
+
+func (p *encryptedProvider) Name() string { return "encrypted" }
+
+func (p *encryptedProvider) Lookup(key string) (string, string, error) {
+	if secret := p.secrets[key]; secret != "" {
+		return secret, "зашифрованный файл " + p.path, nil
+	}
+	return "", "", nil
+}
+
+// SecretsFilePath - путь к зашифрованному файлу секретов и задан ли он явно
+func SecretsFilePath(configPath string) (path string, explicit bool) {
+	if path := os.Getenv("SECRETS_FILE"); path != "" {
+		return path, true
+	}
+	return filepath.Join(filepath.Dir(configPath), "secrets.enc"), false
+}
+
+// SecretsKey читает ключ шифрования секретов из SECRETS_KEY
+func SecretsKey() ([]byte, error) {
+	raw := os.Getenv("SECRETS_KEY")
+	if raw == "" {
+		return nil, errors.New("SECRETS_KEY: не задан ключ для зашифрованного файла секретов")
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(raw))
+	if err != nil || len(key) != 32 {
+		return nil, errors.New("SECRETS_KEY: ожидается 32 байта в base64 (telegram-bot config keygen)")
+	}
+	return key, nil
+}
+
+// EncryptSecrets шифрует данные AES-256-GCM: заголовок и base64(nonce + шифротекст)
+func EncryptSecrets(key, plaintext []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(secretsHeader))
+	return []byte(secretsHeader + base64.StdEncoding.EncodeToString(sealed) + "\n"), nil
+}
+
+// DecryptSecrets расшифровывает данные, зашифрованные EncryptSecrets
+func DecryptSecrets(key, data []byte) ([]byte, error) {
+	encoded, ok := bytes.CutPrefix(data, []byte(secretsHeader))
+	if !ok {
+		return nil, errors.New("неизвестный формат файла секретов")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(encoded)))
+	if err != nil {
+		return nil, fmt.Errorf("поврежден файл секретов: %v", err)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("поврежден файл секретов")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(secretsHeader))
+	if err != nil {
+		return nil, errors.New("не удалось расшифровать: неверный SECRETS_KEY или файл поврежден")
+	}
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// checkSecretFile отказывает, если файл с секретами могут прочитать все пользователи
+func checkSecretFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Mode().Perm()&0o004 != 0 {
+		return fmt.Errorf("%s доступен на чтение всем пользователям (%v): выполните chmod 600 %s или задайте mode: 0400 для Docker secret",
+			path, info.Mode().Perm(), path)
+	}
+	return nil
+}
+
+// secretFileProblems проверяет права файлов, в которых могут лежать секреты:
+// .env, файл конфигурации с секретами и ключ MQTT
+func (c *Config) secretFileProblems(l *loader) []string {
+	var paths []string
+	if _, err := os.Stat(".env"); err == nil {
+		paths = append(paths, ".env")
+	}
+	for _, key := range l.fileKeys {
+		if secretKeys[key] {
+			paths = append(paths, c.File)
+			break
+		}
+	}
+	if c.MQTT.TLSKey != "" {
+		paths = append(paths, c.MQTT.TLSKey)
+	}
+
+	var problems []string
+	for _, path := range paths {
+		if err := checkSecretFile(path); err != nil && !os.IsNotExist(err) {
+			problems = append(problems, err.Error())
+		}
+	}
+	return problems
+}
+
+// Secrets - значения всех секретов, чтобы их можно было вычистить из логов
+func (c *Config) Secrets() []string {
+	var secrets []string
+	for key := range secretKeys {
+		if secret := c.values[key].raw; secret != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testKey(fill byte) []byte {
+	return bytes.Repeat([]byte{fill}, 32)
+}
+
+func TestEncryptDecryptSecrets(t *testing.T) {
+	key := testKey(1)
+	plaintext := []byte("TELEGRAM_BOT_TOKEN=123456:secret\n")
+
+	data, err := EncryptSecrets(key, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("123456:secret")) {
+		t.Fatal("зашифрованные данные содержат открытый текст")
+	}
+
+	tampered := bytes.Clone(data)
+	tampered[len(secretsHeader)+10] ^= 'A' ^ 'B'
+
+	tests := []struct {
+		name    string
+		key     []byte
+		data    []byte
+		wantErr bool
+	}{
+		{"правильный ключ", key, data, false},
+		{"неверный ключ", testKey(2), data, true},
+		{"измененные данные", key, tampered, true},
+		{"без заголовка", key, bytes.TrimPrefix(data, []byte(secretsHeader)), true},
+		{"чужой заголовок", key, append([]byte("other:v1\n"), bytes.TrimPrefix(data, []byte(secretsHeader))...), true},
+		{"обрезанные данные", key, []byte(secretsHeader + "AAAA"), true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := DecryptSecrets(test.key, test.data)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("ожидалась ошибка, получено %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, plaintext) {
+				t.Fatalf("получено %q, ожидалось %q", got, plaintext)
+			}
+		})
+	}
+}
+
+func TestEncryptSecretsUsesFreshNonce(t *testing.T) {
+	first, _ := EncryptSecrets(testKey(1), []byte("A=1"))
+	second, _ := EncryptSecrets(testKey(1), []byte("A=1"))
+	if bytes.Equal(first, second) {
+		t.Fatal("одинаковые данные зашифрованы одинаково: nonce не меняется")
+	}
+}
+
+func writeSecret(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSecretProviderPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+
+	key := testKey(3)
+	encrypted, err := EncryptSecrets(key, []byte("HA_TOKEN=from-encrypted\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeSecret(t, filepath.Join(dir, "secrets.enc"), string(encrypted), 0o600)
+	writeSecret(t, filepath.Join(dir, "ha_token"), "from-file\n", 0o600)
+
+	tests := []struct {
+		name       string
+		providers  string
+		env        bool
+		file       bool
+		want       string
+		wantSource string
+	}{
+		{"env важнее файла", "", true, true, "from-env", "окружение"},
+		{"файл важнее зашифрованного", "", false, true, "from-file", "файл"},
+		{"зашифрованный файл", "", false, false, "from-encrypted", "зашифрованный файл"},
+		{"порядок из SECRET_PROVIDERS", "encrypted,file,env", true, true, "from-encrypted", "зашифрованный файл"},
+		{"env выключен", "file", true, true, "from-file", "файл"},
+		{"только env без значения", "env", false, true, "", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("SECRETS_KEY", base64.StdEncoding.EncodeToString(key))
+			t.Setenv("SECRET_PROVIDERS", test.providers)
+			t.Setenv("HA_TOKEN", "")
+			t.Setenv("HA_TOKEN_FILE", "")
+			if test.env {
+				t.Setenv("HA_TOKEN", "from-env")
+			}
+			if test.file {
+				t.Setenv("HA_TOKEN_FILE", filepath.Join(dir, "ha_token"))
+			}
+
+			l := newLoader(configPath)
+			if len(l.problems) > 0 {
+				t.Fatalf("неожиданные ошибки: %v", l.problems)
+			}
+			v, ok := l.lookup("HA_TOKEN")
+			if ok != (test.want != "") || v.raw != test.want {
+				t.Fatalf("получено %q (%v), ожидалось %q", v.raw, ok, test.want)
+			}
+			if !strings.HasPrefix(v.source, test.wantSource) {
+				t.Fatalf("источник %q, ожидался %q", v.source, test.wantSource)
+			}
+		})
+	}
+}
+
+func TestWorldReadableSecretFileRejected(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token")
+	writeSecret(t, path, "secret", 0o644)
+
+	if err := checkSecretFile(path); err == nil {
+		t.Fatal("файл 0644 должен отклоняться")
+	}
+
+	t.Setenv("SECRET_PROVIDERS", "file")
+	t.Setenv("HA_TOKEN_FILE", path)
+	l := newLoader(filepath.Join(dir, "config.yaml"))
+	if _, ok := l.lookup("HA_TOKEN"); ok {
+		t.Fatal("секрет из файла 0644 не должен читаться")
+	}
+	if len(l.problems) != 1 || !strings.Contains(l.problems[0], "доступен на чтение всем") {
+		t.Fatalf("ожидалась ошибка прав доступа, получено %v", l.problems)
+	}
+
+	if err := os.Chmod(path, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSecretFile(path); err != nil {
+		t.Fatalf("файл 0600 должен приниматься: %v", err)
+	}
+}
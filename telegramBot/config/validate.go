@@ -0,0 +1,190 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ValidationError перечисляет все проблемы конфигурации, а не только первую
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("ошибки конфигурации (%d):\n  • %s", len(e.Problems), strings.Join(e.Problems, "\n  • "))
+}
+
+// validator копит найденные проблемы
+type validator struct {
+	problems []string
+}
+
+func (v *validator) add(format string, args ...any) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) url(key string, value string, schemes ...string) {
+	if value == "" {
+		return
+	}
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" {
+		v.add("%s: некорректный адрес %q", key, value)
+		return
+	}
+	for _, scheme := range schemes {
+		if parsed.Scheme == scheme {
+			return
+		}
+	}
+	v.add("%s: схема %q не поддерживается (ожидается %s)", key, parsed.Scheme, strings.Join(schemes, ", "))
+}
+
+func (v *validator) schedule(key string, spec string) {
+	if spec == "" {
+		return
+	}
+	if _, err := cron.ParseStandard(spec); err != nil {
+		v.add("%s: некорректное cron-расписание %q: %v", key, spec, err)
+	}
+}
+
+func (v *validator) clock(key string, value string) {
+	if _, err := time.Parse("15:04", value); err != nil {
+		v.add("%s: ожидается время ЧЧ:ММ, получено %q", key, value)
+	}
+}
+
+func (v *validator) percent(key string, value float64) {
+	if value < 0 || value > 100 {
+		v.add("%s: ожидается процент от 0 до 100, получено %v", key, value)
+	}
+}
+
+func (v *validator) nonNegative(key string, value float64) {
+	if value < 0 {
+		v.add("%s: значение не может быть отрицательным (%v)", key, value)
+	}
+}
+
+func (v *validator) positive(key string, value time.Duration) {
+	if value <= 0 {
+		v.add("%s: интервал должен быть больше нуля", key)
+	}
+}
+
+// validate проверяет смысл значений: форматы, диапазоны, расписания и адреса
+func (c *Config) validate() []string {
+	v := &validator{}
+
+	if c.TelegramToken == "" {
+		v.add("TELEGRAM_BOT_TOKEN: не задан")
+	}
+	if c.LogFormat != "text" && c.LogFormat != "json" {
+		v.add("LOG_FORMAT: ожидается text или json, получено %q", c.LogFormat)
+	}
+	for _, item := range c.LogLevels {
+		component, level, ok := strings.Cut(item, "=")
+		var parsed slog.Level
+		if !ok || strings.TrimSpace(component) == "" || parsed.UnmarshalText([]byte(strings.TrimSpace(level))) != nil {
+			v.add("LOG_LEVELS: ожидается компонент=уровень (debug, info, warn, error), получено %q", item)
+		}
+	}
+	if c.LogBuffer <= 0 {
+		v.add("LOG_BUFFER: должен быть больше нуля")
+	}
+	v.nonNegative("CONFIG_WATCH_INTERVAL", float64(c.WatchInterval))
+	v.nonNegative("ERROR_REPORT_INTERVAL", float64(c.ErrorReportInterval))
+
+	v.url("YANDEX_DISK_URL", c.UrlYandexDisk, "https")
+	v.url("YANDEX_OAUTH_URL", c.YandexOAuth.URL, "https", "http")
+	if c.YandexOAuth.ClientID != "" && c.YandexOAuth.ClientSecret == "" {
+		v.add("YANDEX_CLIENT_SECRET: обязателен вместе с YANDEX_CLIENT_ID")
+	}
+	v.positive("YANDEX_TOKEN_REFRESH_BEFORE", c.YandexOAuth.RefreshBefore)
+	v.nonNegative("YANDEX_VERSIONS_KEEP", float64(c.VersionsKeep))
+
+	for _, alias := range c.ChatAliases {
+		name, target, ok := strings.Cut(alias, "=")
+		if !ok || strings.TrimSpace(name) == "" || !validChatTarget(strings.TrimSpace(target)) {
+			v.add("CHAT_ALIASES: ожидается имя=chat_id[:thread_id], получено %q", alias)
+		}
+	}
+
+	v.schedule("BACKUP_SCHEDULE", c.Backup.Schedule)
+	v.nonNegative("BACKUP_KEEP_DAILY", float64(c.Backup.KeepDaily))
+	v.nonNegative("BACKUP_KEEP_WEEKLY", float64(c.Backup.KeepWeekly))
+	v.nonNegative("BACKUP_KEEP_MONTHLY", float64(c.Backup.KeepMonthly))
+
+	v.url("MQTT_BROKER", c.MQTT.Broker, "tcp", "ssl", "tls", "mqtt", "mqtts", "ws", "wss")
+	if (c.MQTT.TLSCert == "") != (c.MQTT.TLSKey == "") {
+		v.add("MQTT_TLS_CERT и MQTT_TLS_KEY задаются вместе")
+	}
+
+	v.percent("ZIGBEE_BATTERY_LOW", float64(c.Zigbee.BatteryLow))
+	v.nonNegative("ZIGBEE_BATTERY_HYSTERESIS", float64(c.Zigbee.BatteryHysteresis))
+	v.nonNegative("ZIGBEE_LINKQUALITY_LOW", float64(c.Zigbee.LinkQualityLow))
+	v.nonNegative("ZIGBEE_LINKQUALITY_HYSTERESIS", float64(c.Zigbee.LinkQualityHysteresis))
+	v.positive("ZIGBEE_MONITOR_INTERVAL", c.Zigbee.MonitorInterval)
+
+	v.url("HA_URL", c.HA.URL, "http", "https")
+	for _, favorite := range c.HA.Favorites {
+		_, entity, ok := strings.Cut(favorite, "=")
+		if !ok {
+			entity = favorite
+		}
+		if !strings.Contains(entity, ".") {
+			v.add("HA_FAVORITES: ожидается Название=script.id или scene.id, получено %q", favorite)
+		}
+	}
+
+	v.schedule("CAMERA_SCHEDULE", c.Camera.Schedule)
+	v.nonNegative("CAMERA_RETENTION_DAYS", float64(c.Camera.RetentionDays))
+
+	v.schedule("CHART_DAILY_SCHEDULE", c.Chart.Schedule)
+	v.positive("CHART_DAILY_PERIOD", c.Chart.Period)
+
+	v.positive("ENERGY_SAMPLE_INTERVAL", c.Energy.SampleInterval)
+	v.nonNegative("ENERGY_TARIFF_DAY", c.Energy.TariffDay)
+	v.nonNegative("ENERGY_TARIFF_NIGHT", c.Energy.TariffNight)
+	v.clock("ENERGY_NIGHT_START", c.Energy.NightStart)
+	v.clock("ENERGY_NIGHT_END", c.Energy.NightEnd)
+	v.schedule("ENERGY_REPORT_SCHEDULE", c.Energy.ReportSchedule)
+	v.schedule("ENERGY_EXPORT_SCHEDULE", c.Energy.ExportSchedule)
+
+	v.nonNegative("HOST_MONITOR_INTERVAL", float64(c.Host.MonitorInterval))
+	v.nonNegative("HOST_TEMP_HYSTERESIS", c.Host.TempHysteresis)
+	v.percent("HOST_DISK_FREE_LOW", c.Host.DiskFreeLow)
+	v.nonNegative("HOST_DISK_HYSTERESIS", c.Host.DiskHysteresis)
+	v.nonNegative("HOST_LOAD_HIGH", c.Host.LoadHigh)
+
+	v.positive("DOCKER_MONITOR_INTERVAL", c.Docker.MonitorInterval)
+	if c.Docker.CrashLoopRestarts <= 0 {
+		v.add("DOCKER_CRASHLOOP_RESTARTS: должен быть больше нуля")
+	}
+
+	v.positive("LAN_PROBE_TIMEOUT", c.LAN.ProbeTimeout)
+	v.nonNegative("LAN_PRESENCE_INTERVAL", float64(c.LAN.PresenceInterval))
+
+	return v.problems
+}
+
+// validChatTarget проверяет адрес вида chat_id или chat_id:thread_id
+func validChatTarget(target string) bool {
+	chatPart, threadPart, hasThread := strings.Cut(target, ":")
+	if _, err := strconv.ParseInt(chatPart, 10, 64); err != nil {
+		return false
+	}
+	if hasThread {
+		if _, err := strconv.Atoi(threadPart); err != nil {
+			return false
+		}
+	}
+	return true
+}
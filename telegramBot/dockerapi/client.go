@@ -0,0 +1,206 @@
+package dockerapi
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Версия API, поддерживаемая Docker Engine 20.10+
+const apiVersion = "v1.41"
+
+// Container - элемент списка /containers/json
+type Container struct {
+	ID      string   `json:"Id"`
+	Names   []string `json:"Names"`
+	Image   string   `json:"Image"`
+	State   string   `json:"State"`  // running, exited, restarting...
+	Status  string   `json:"Status"` // "Up 2 hours (healthy)"
+	Created int64    `json:"Created"`
+
+	Labels map[string]string `json:"Labels"`
+}
+
+// Name возвращает имя контейнера без ведущего "/"
+func (c Container) Name() string {
+	if len(c.Names) == 0 {
+		return c.ID[:min(12, len(c.ID))]
+	}
+	return strings.TrimPrefix(c.Names[0], "/")
+}
+
+// Details - результат /containers/{id}/json
+type Details struct {
+	ID           string `json:"Id"`
+	Name         string `json:"Name"`
+	RestartCount int    `json:"RestartCount"`
+	State        struct {
+		Status     string    `json:"Status"`
+		Running    bool      `json:"Running"`
+		Restarting bool      `json:"Restarting"`
+		ExitCode   int       `json:"ExitCode"`
+		StartedAt  time.Time `json:"StartedAt"`
+		FinishedAt time.Time `json:"FinishedAt"`
+		Health     *struct {
+			Status        string `json:"Status"` // starting, healthy, unhealthy
+			FailingStreak int    `json:"FailingStreak"`
+			Log           []struct {
+				ExitCode int    `json:"ExitCode"`
+				Output   string `json:"Output"`
+			} `json:"Log"`
+		} `json:"Health"`
+	} `json:"State"`
+	Config struct {
+		Tty bool `json:"Tty"`
+	} `json:"Config"`
+}
+
+// HealthStatus возвращает статус healthcheck или пустую строку, если он не настроен
+func (d Details) HealthStatus() string {
+	if d.State.Health == nil {
+		return ""
+	}
+	return d.State.Health.Status
+}
+
+// APIError - ответ Docker Engine с кодом ошибки
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Docker API %d: %s", e.StatusCode, e.Message)
+}
+
+// Client - Docker Engine API через unix-сокет
+type Client struct {
+	http *http.Client
+}
+
+func New(socketPath string) *Client {
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socketPath)
+		},
+	}
+	return &Client{http: &http.Client{Transport: transport, Timeout: 60 * time.Second}}
+}
+
+// Containers возвращает все контейнеры, включая остановленные
+func (c *Client) Containers() ([]Container, error) {
+	var containers []Container
+	if err := c.request("GET", "/containers/json?all=1", &containers); err != nil {
+		return nil, err
+	}
+	return containers, nil
+}
+
+// Inspect возвращает подробности о контейнере по имени или ID
+func (c *Client) Inspect(name string) (*Details, error) {
+	var details Details
+	if err := c.request("GET", "/containers/"+url.PathEscape(name)+"/json", &details); err != nil {
+		return nil, err
+	}
+	return &details, nil
+}
+
+// Restart перезапускает контейнер, давая ему timeout на корректную остановку
+func (c *Client) Restart(name string, timeout time.Duration) error {
+	path := fmt.Sprintf("/containers/%s/restart?t=%d", url.PathEscape(name), int(timeout.Seconds()))
+	return c.request("POST", path, nil)
+}
+
+// Logs возвращает последние lines строк stdout и stderr контейнера
+func (c *Client) Logs(name string, lines int) (string, error) {
+	details, err := c.Inspect(name)
+	if err != nil {
+		return "", err
+	}
+
+	path := fmt.Sprintf("/containers/%s/logs?stdout=1&stderr=1&tail=%s", url.PathEscape(name), strconv.Itoa(lines))
+	body, err := c.do("GET", path)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	// С TTY лог отдается как есть, без TTY - мультиплексированным потоком
+	if details.Config.Tty {
+		data, err := io.ReadAll(body)
+		return string(data), err
+	}
+	return demultiplex(body)
+}
+
+// demultiplex разбирает поток логов: каждый кадр - 8 байт заголовка
+// (тип потока, 3 байта нулей, длина big-endian) и данные
+func demultiplex(reader io.Reader) (string, error) {
+	var builder strings.Builder
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if err == io.EOF {
+				return builder.String(), nil
+			}
+			return builder.String(), err
+		}
+
+		size := binary.BigEndian.Uint32(header[4:])
+		if _, err := io.CopyN(&builder, reader, int64(size)); err != nil {
+			return builder.String(), err
+		}
+	}
+}
+
+func (c *Client) request(method string, path string, out any) error {
+	body, err := c.do(method, path)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if out == nil {
+		io.Copy(io.Discard, body)
+		return nil
+	}
+	if err := json.NewDecoder(body).Decode(out); err != nil {
+		return fmt.Errorf("ошибка разбора ответа Docker: %v", err)
+	}
+	return nil
+}
+
+// do выполняет запрос и возвращает тело успешного ответа
+func (c *Client) do(method string, path string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(method, "http://docker/"+apiVersion+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса к Docker: %v", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var apiError struct {
+			Message string `json:"message"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(data, &apiError) != nil || apiError.Message == "" {
+			apiError.Message = strings.TrimSpace(string(data))
+		}
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: apiError.Message}
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,145 @@
+package dockerapi
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"telegramBot/alerting"
+	"telegramBot/config"
+	"telegramBot/logging"
+)
+
+var logger = logging.For("docker")
+
+// Monitor следит за циклическими перезапусками и неуспешными healthcheck контейнеров
+type Monitor struct {
+	client *Client
+	config atomic.Pointer[config.DockerConfig] // пороги меняются через Reconfigure
+	alerts *alerting.Tracker
+
+	mu       sync.Mutex
+	restarts map[string][]restartSample // история RestartCount по контейнерам
+}
+
+type restartSample struct {
+	time  time.Time
+	count int
+}
+
+func NewMonitor(client *Client, config config.DockerConfig, notify func(text string)) *Monitor {
+	m := &Monitor{
+		client:   client,
+		alerts:   alerting.NewTracker(notify),
+		restarts: make(map[string][]restartSample),
+	}
+	m.config.Store(&config)
+	return m
+}
+
+// Reconfigure применяет новые пороги и список контейнеров без перезапуска. Интервал проверок не меняется
+func (m *Monitor) Reconfigure(config config.DockerConfig) {
+	m.config.Store(&config)
+}
+
+// Start запускает проверки в фоне
+func (m *Monitor) Start() {
+	config := m.config.Load()
+	logger.Info("🩺 Мониторинг контейнеров", "interval", config.MonitorInterval)
+
+	go func() {
+		ticker := time.NewTicker(config.MonitorInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			m.check()
+		}
+	}()
+}
+
+// Watched проверяет, входит ли контейнер в список наблюдаемых (пустой список - все)
+func (m *Monitor) Watched(name string) bool {
+	config := m.config.Load()
+	return len(config.Containers) == 0 || slices.Contains(config.Containers, name)
+}
+
+func (m *Monitor) check() {
+	containers, err := m.client.Containers()
+	if err != nil {
+		m.alerts.Raise("docker", fmt.Sprintf("Docker недоступен: %s", html.EscapeString(err.Error())))
+		return
+	}
+	m.alerts.Clear("docker", "Docker снова доступен")
+
+	for _, container := range containers {
+		name := container.Name()
+		if !m.Watched(name) {
+			continue
+		}
+
+		details, err := m.client.Inspect(container.ID)
+		if err != nil {
+			logger.Warn("⚠️ Ошибка чтения контейнера", "container", name, "error", err)
+			continue
+		}
+		m.checkRestarts(name, details)
+		m.checkHealth(name, details)
+	}
+}
+
+// checkRestarts поднимает тревогу, если за окно CrashLoopWindow было не меньше CrashLoopRestarts перезапусков
+func (m *Monitor) checkRestarts(name string, details *Details) {
+	config := m.config.Load()
+	now := time.Now()
+	escaped := html.EscapeString(name)
+
+	m.mu.Lock()
+	samples := append(m.restarts[name], restartSample{time: now, count: details.RestartCount})
+	for len(samples) > 1 && now.Sub(samples[0].time) > config.CrashLoopWindow {
+		samples = samples[1:]
+	}
+	m.restarts[name] = samples
+	restarts := details.RestartCount - samples[0].count
+	m.mu.Unlock()
+
+	switch {
+	case restarts >= config.CrashLoopRestarts || details.State.Restarting && restarts > 0:
+		m.alerts.Raise("crashloop:"+name, fmt.Sprintf("Контейнер <b>%s</b> перезапускается по кругу: %d перезапусков за %v, код выхода %d",
+			escaped, restarts, config.CrashLoopWindow, details.State.ExitCode))
+	case restarts == 0 && details.State.Running:
+		m.alerts.Clear("crashloop:"+name, fmt.Sprintf("Контейнер <b>%s</b> работает стабильно", escaped))
+	}
+}
+
+func (m *Monitor) checkHealth(name string, details *Details) {
+	escaped := html.EscapeString(name)
+
+	switch details.HealthStatus() {
+	case "unhealthy":
+		message := fmt.Sprintf("Контейнер <b>%s</b> unhealthy (неудачных проверок подряд: %d)", escaped, details.State.Health.FailingStreak)
+		if logs := details.State.Health.Log; len(logs) > 0 {
+			if output := strings.TrimSpace(logs[len(logs)-1].Output); output != "" {
+				message += fmt.Sprintf("\n<pre>%s</pre>", html.EscapeString(truncate(output, 500)))
+			}
+		}
+		m.alerts.Raise("unhealthy:"+name, message)
+	case "healthy":
+		m.alerts.Clear("unhealthy:"+name, fmt.Sprintf("Контейнер <b>%s</b> снова healthy", escaped))
+	}
+}
+
+// Active возвращает активные оповещения для /containers
+func (m *Monitor) Active() []alerting.Alert {
+	return m.alerts.Active()
+}
+
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "…"
+}
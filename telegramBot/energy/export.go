@@ -0,0 +1,32 @@
+package energy
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+)
+
+// ExportCSV формирует CSV с расходом по дням и устройствам
+func ExportCSV(daily []DailyUsage) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	writer.Write([]string{"date", "device", "day_kwh", "night_kwh", "total_kwh", "cost"})
+	for _, item := range daily {
+		writer.Write([]string{
+			item.Date.Format("2006-01-02"),
+			item.Device,
+			formatFloat(item.DayKWh),
+			formatFloat(item.NightKWh),
+			formatFloat(item.Total()),
+			strconv.FormatFloat(item.Cost, 'f', 2, 64),
+		})
+	}
+	writer.Flush()
+
+	return buffer.Bytes(), writer.Error()
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 3, 64)
+}
@@ -0,0 +1,170 @@
+package energy
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"telegramBot/homeassistant"
+	"telegramBot/logging"
+	"telegramBot/zigbee"
+)
+
+var logger = logging.For("energy")
+
+// Неизменившиеся показания записываем не чаще раза в час, чтобы не раздувать файлы
+const unchangedStoreInterval = time.Hour
+
+// Source возвращает текущие показания счетчиков
+type Source func() []Reading
+
+// Meter периодически опрашивает источники и сохраняет показания
+type Meter struct {
+	store    *Store
+	sources  []Source
+	interval time.Duration
+
+	mu   sync.Mutex
+	last map[string]Reading // последнее сохраненное показание устройства
+}
+
+func NewMeter(store *Store, interval time.Duration, sources ...Source) *Meter {
+	return &Meter{store: store, sources: sources, interval: interval, last: make(map[string]Reading)}
+}
+
+// Start запускает опрос в фоне
+func (m *Meter) Start() {
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			m.Sample()
+		}
+	}()
+}
+
+// Sample опрашивает источники и сохраняет изменившиеся показания
+func (m *Meter) Sample() {
+	var readings []Reading
+	for _, source := range m.sources {
+		readings = append(readings, source()...)
+	}
+
+	m.mu.Lock()
+	var changed []Reading
+	for _, reading := range readings {
+		last, ok := m.last[reading.Device]
+		if ok && last.Energy == reading.Energy && reading.Time.Sub(last.Time) < unchangedStoreInterval {
+			continue
+		}
+		m.last[reading.Device] = reading
+		changed = append(changed, reading)
+	}
+	m.mu.Unlock()
+
+	if len(changed) == 0 {
+		return
+	}
+	if err := m.store.Append(changed); err != nil {
+		logger.Error("❌ Ошибка сохранения показаний", "error", err)
+	}
+}
+
+// Current возвращает последние показания устройств
+func (m *Meter) Current() []Reading {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	readings := make([]Reading, 0, len(m.last))
+	for _, reading := range m.last {
+		readings = append(readings, reading)
+	}
+	return readings
+}
+
+// ZigbeeSource читает energy и power из состояний устройств zigbee2mqtt.
+// devices == ["*"] - все устройства, сообщающие energy.
+func ZigbeeSource(bridge *zigbee.Bridge, devices []string) Source {
+	all := slices.Contains(devices, "*")
+
+	return func() []Reading {
+		var readings []Reading
+		for _, device := range bridge.Devices() {
+			if !all && !slices.Contains(devices, device.FriendlyName) {
+				continue
+			}
+			state, ok := bridge.State(device.FriendlyName)
+			if !ok {
+				continue
+			}
+			energy, ok := state.Number("energy")
+			if !ok {
+				continue
+			}
+			power, _ := state.Number("power")
+			readings = append(readings, Reading{Time: time.Now(), Device: device.FriendlyName, Energy: energy, Power: power})
+		}
+		return readings
+	}
+}
+
+// HAEntity - счетчик Home Assistant: "имя=sensor.energy" или "имя=sensor.energy/sensor.power"
+type HAEntity struct {
+	Name   string
+	Energy string
+	Power  string
+}
+
+// ParseHAEntities проверяет и разбирает список счетчиков Home Assistant
+func ParseHAEntities(items []string) ([]HAEntity, error) {
+	var entities []HAEntity
+	for _, item := range items {
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("некорректный счетчик %q, ожидается имя=sensor.energy[/sensor.power]", item)
+		}
+		energy, power, _ := strings.Cut(value, "/")
+		entities = append(entities, HAEntity{Name: strings.TrimSpace(name), Energy: strings.TrimSpace(energy), Power: strings.TrimSpace(power)})
+	}
+	return entities, nil
+}
+
+// HASource читает показания счетчиков из Home Assistant
+func HASource(client *homeassistant.Client, entities []HAEntity) Source {
+	return func() []Reading {
+		var readings []Reading
+		for _, entity := range entities {
+			energy, err := haNumber(client, entity.Energy)
+			if err != nil {
+				logger.Warn("⚠️ Ошибка чтения счетчика", "entity_id", entity.Energy, "error", err)
+				continue
+			}
+
+			reading := Reading{Time: time.Now(), Device: entity.Name, Energy: energy}
+			if entity.Power != "" {
+				reading.Power, _ = haNumber(client, entity.Power)
+			}
+			readings = append(readings, reading)
+		}
+		return readings
+	}
+}
+
+// haNumber возвращает числовое состояние сущности, пересчитывая Вт·ч в кВт·ч
+func haNumber(client *homeassistant.Client, entityID string) (float64, error) {
+	state, err := client.State(entityID)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseFloat(state.State, 64)
+	if err != nil {
+		return 0, fmt.Errorf("нечисловое состояние %q", state.State)
+	}
+	if state.Unit() == "Wh" {
+		value /= 1000
+	}
+	return value, nil
+}
@@ -0,0 +1,140 @@
+package energy
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Reading - показание счетчика устройства
+type Reading struct {
+	Time   time.Time
+	Device string
+	Energy float64 // накопленная энергия, кВт·ч
+	Power  float64 // текущая мощность, Вт
+}
+
+// Store хранит показания в CSV-файлах по месяцам: readings-2024-01.csv
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir}, nil
+}
+
+func (s *Store) fileName(month time.Time) string {
+	return filepath.Join(s.dir, "readings-"+month.Format("2006-01")+".csv")
+}
+
+// Append дописывает показания в файл текущего месяца
+func (s *Store) Append(readings []Reading) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byMonth := make(map[string][]Reading)
+	for _, reading := range readings {
+		name := s.fileName(reading.Time)
+		byMonth[name] = append(byMonth[name], reading)
+	}
+
+	for name, items := range byMonth {
+		file, err := os.OpenFile(name, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+
+		writer := csv.NewWriter(file)
+		for _, reading := range items {
+			writer.Write([]string{
+				reading.Time.Format(time.RFC3339),
+				reading.Device,
+				strconv.FormatFloat(reading.Energy, 'f', -1, 64),
+				strconv.FormatFloat(reading.Power, 'f', -1, 64),
+			})
+		}
+		writer.Flush()
+
+		if err := writer.Error(); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load возвращает показания за период [from, to), отсортированные по времени
+func (s *Store) Load(from time.Time, to time.Time) ([]Reading, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var readings []Reading
+	for month := MonthStart(from); month.Before(to); month = month.AddDate(0, 1, 0) {
+		items, err := s.loadFile(s.fileName(month))
+		if err != nil {
+			return nil, err
+		}
+		for _, reading := range items {
+			if !reading.Time.Before(from) && reading.Time.Before(to) {
+				readings = append(readings, reading)
+			}
+		}
+	}
+
+	sort.SliceStable(readings, func(i, j int) bool { return readings[i].Time.Before(readings[j].Time) })
+	return readings, nil
+}
+
+func (s *Store) loadFile(name string) ([]Reading, error) {
+	file, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 4
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения %s: %w", name, err)
+	}
+
+	readings := make([]Reading, 0, len(records))
+	for _, record := range records {
+		t, err := time.Parse(time.RFC3339, record[0])
+		if err != nil {
+			continue
+		}
+		energy, _ := strconv.ParseFloat(record[2], 64)
+		power, _ := strconv.ParseFloat(record[3], 64)
+		readings = append(readings, Reading{Time: t.Local(), Device: record[1], Energy: energy, Power: power})
+	}
+	return readings, nil
+}
+
+// MonthStart - начало месяца, в который попадает t
+func MonthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// DayStart - полночь дня, в который попадает t
+func DayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
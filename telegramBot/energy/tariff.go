@@ -0,0 +1,71 @@
+package energy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tariff - стоимость кВт·ч с учетом ночной зоны.
+// Если ночной тариф не задан, весь расход считается по дневному.
+type Tariff struct {
+	Day        float64
+	Night      float64
+	NightStart int // минуты от полуночи
+	NightEnd   int
+	Currency   string
+}
+
+// ParseClock разбирает время суток "23:00" в минуты от полуночи
+func ParseClock(value string) (int, error) {
+	hours, minutes, ok := strings.Cut(value, ":")
+	h, errH := strconv.Atoi(hours)
+	m, errM := strconv.Atoi(minutes)
+	if !ok || errH != nil || errM != nil || h < 0 || h > 23 || m < 0 || m > 59 {
+		return 0, fmt.Errorf("некорректное время %q, ожидается ЧЧ:ММ", value)
+	}
+	return h*60 + m, nil
+}
+
+// Zoned сообщает, используется ли двухзонный тариф
+func (t Tariff) Zoned() bool {
+	return t.Night > 0 && t.NightStart != t.NightEnd
+}
+
+// IsNight проверяет, попадает ли момент в ночную зону (зона может переходить через полночь)
+func (t Tariff) IsNight(moment time.Time) bool {
+	if !t.Zoned() {
+		return false
+	}
+	minute := moment.Hour()*60 + moment.Minute()
+	if t.NightStart < t.NightEnd {
+		return minute >= t.NightStart && minute < t.NightEnd
+	}
+	return minute >= t.NightStart || minute < t.NightEnd
+}
+
+// nextChange возвращает ближайшую после moment смену зоны или нулевое время для однозонного тарифа
+func (t Tariff) nextChange(moment time.Time) time.Time {
+	if !t.Zoned() {
+		return time.Time{}
+	}
+	var next time.Time
+	for _, day := range []int{0, 1} {
+		for _, minute := range []int{t.NightStart, t.NightEnd} {
+			at := time.Date(moment.Year(), moment.Month(), moment.Day()+day, 0, minute, 0, 0, moment.Location())
+			if at.After(moment) && (next.IsZero() || at.Before(next)) {
+				next = at
+			}
+		}
+	}
+	return next
+}
+
+// Cost считает стоимость расхода по зонам
+func (t Tariff) Cost(dayKWh float64, nightKWh float64) float64 {
+	if !t.Zoned() {
+		return (dayKWh + nightKWh) * t.Day
+	}
+	return dayKWh*t.Day + nightKWh*t.Night
+}
@@ -0,0 +1,145 @@
+package energy
+
+import (
+	"sort"
+	"time"
+)
+
+// Usage - расход устройства за период
+type Usage struct {
+	Device   string
+	DayKWh   float64
+	NightKWh float64
+	Cost     float64
+	Power    float64 // последняя известная мощность, Вт
+}
+
+// Total возвращает суммарный расход
+func (u Usage) Total() float64 {
+	return u.DayKWh + u.NightKWh
+}
+
+// DailyUsage - расход устройства за конкретный день
+type DailyUsage struct {
+	Date time.Time
+	Usage
+}
+
+// consumption - приращение счетчика между двумя соседними показаниями
+type consumption struct {
+	from   time.Time // время предыдущего показания
+	to     time.Time // время показания с приращением
+	device string
+	kwh    float64
+}
+
+// consumptions превращает показания накопленной энергии в приращения.
+// Приращение относится к промежутку между показаниями. Сброс счетчика
+// (значение уменьшилось) считается расходом с нуля.
+func consumptions(readings []Reading) []consumption {
+	last := make(map[string]Reading)
+	var result []consumption
+	for _, reading := range readings {
+		previous, ok := last[reading.Device]
+		last[reading.Device] = reading
+		if !ok {
+			continue
+		}
+
+		delta := reading.Energy - previous.Energy
+		if delta < 0 {
+			delta = reading.Energy
+		}
+		if delta > 0 {
+			result = append(result, consumption{from: previous.Time, to: reading.Time, device: reading.Device, kwh: delta})
+		}
+	}
+	return result
+}
+
+// Summarize считает расход по устройствам за период [from, to).
+// Приращение между показаниями делится пропорционально времени: на части внутри
+// и вне периода и на дневную и ночную зоны.
+// readings должны начинаться раньше from, чтобы было от чего считать первое приращение.
+func Summarize(readings []Reading, from time.Time, to time.Time, tariff Tariff) []Usage {
+	usage := make(map[string]*Usage)
+	get := func(device string) *Usage {
+		if _, ok := usage[device]; !ok {
+			usage[device] = &Usage{Device: device}
+		}
+		return usage[device]
+	}
+
+	book := func(device string, moment time.Time, kwh float64) {
+		if tariff.IsNight(moment) {
+			get(device).NightKWh += kwh
+		} else {
+			get(device).DayKWh += kwh
+		}
+	}
+
+	for _, item := range consumptions(readings) {
+		span := item.to.Sub(item.from)
+		if span <= 0 {
+			// Показания с одинаковым временем - делить нечего
+			if !item.to.Before(from) && item.to.Before(to) {
+				book(item.device, item.to, item.kwh)
+			}
+			continue
+		}
+
+		start, end := item.from, item.to
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		for start.Before(end) {
+			next := end
+			if change := tariff.nextChange(start); !change.IsZero() && change.Before(end) {
+				next = change
+			}
+			book(item.device, start, item.kwh*float64(next.Sub(start))/float64(span))
+			start = next
+		}
+	}
+	for _, reading := range readings {
+		if reading.Time.Before(to) {
+			get(reading.Device).Power = reading.Power
+		}
+	}
+
+	result := make([]Usage, 0, len(usage))
+	for _, item := range usage {
+		item.Cost = tariff.Cost(item.DayKWh, item.NightKWh)
+		result = append(result, *item)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Total() > result[j].Total() })
+	return result
+}
+
+// Daily разбивает расход за период по дням
+func Daily(readings []Reading, from time.Time, to time.Time, tariff Tariff) []DailyUsage {
+	var result []DailyUsage
+	for day := DayStart(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, usage := range Summarize(readings, day, day.AddDate(0, 0, 1), tariff) {
+			if usage.Total() > 0 {
+				result = append(result, DailyUsage{Date: day, Usage: usage})
+			}
+		}
+	}
+	return result
+}
+
+// Sum складывает расход нескольких устройств
+func Sum(items []Usage) Usage {
+	total := Usage{Device: "total"}
+	for _, item := range items {
+		total.DayKWh += item.DayKWh
+		total.NightKWh += item.NightKWh
+		total.Cost += item.Cost
+		total.Power += item.Power
+	}
+	return total
+}
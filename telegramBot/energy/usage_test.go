@@ -0,0 +1,133 @@
+package energy
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func at(day int, hour int, minute int) time.Time {
+	return time.Date(2024, time.March, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestSummarizeTariffSplit(t *testing.T) {
+	zoned := Tariff{Day: 6, Night: 3, NightStart: 23 * 60, NightEnd: 7 * 60}
+	daytime := Tariff{Day: 6, Night: 3, NightStart: 1 * 60, NightEnd: 5 * 60}
+
+	tests := []struct {
+		name      string
+		tariff    Tariff
+		readings  []Reading
+		from, to  time.Time
+		wantDay   float64
+		wantNight float64
+	}{
+		{
+			name:     "внутри дневной зоны",
+			tariff:   zoned,
+			readings: []Reading{{Time: at(10, 10, 0), Energy: 1}, {Time: at(10, 12, 0), Energy: 4}},
+			from:     at(10, 0, 0), to: at(11, 0, 0),
+			wantDay: 3,
+		},
+		{
+			name:     "переход в ночную зону",
+			tariff:   zoned,
+			readings: []Reading{{Time: at(10, 22, 0), Energy: 0}, {Time: at(10, 23, 30), Energy: 3}},
+			from:     at(10, 0, 0), to: at(11, 0, 0),
+			wantDay: 2, wantNight: 1,
+		},
+		{
+			name:     "переход в дневную зону",
+			tariff:   zoned,
+			readings: []Reading{{Time: at(10, 6, 0), Energy: 10}, {Time: at(10, 8, 0), Energy: 12}},
+			from:     at(10, 0, 0), to: at(11, 0, 0),
+			wantDay: 1, wantNight: 1,
+		},
+		{
+			name:     "промежуток через границу периода",
+			tariff:   zoned,
+			readings: []Reading{{Time: at(9, 22, 0), Energy: 0}, {Time: at(10, 2, 0), Energy: 4}},
+			from:     at(10, 0, 0), to: at(11, 0, 0),
+			wantNight: 2,
+		},
+		{
+			name:     "промежуток через конец периода",
+			tariff:   zoned,
+			readings: []Reading{{Time: at(10, 22, 0), Energy: 0}, {Time: at(11, 2, 0), Energy: 4}},
+			from:     at(10, 0, 0), to: at(11, 0, 0),
+			wantDay: 1, wantNight: 1,
+		},
+		{
+			name:     "несколько суток без показаний",
+			tariff:   zoned,
+			readings: []Reading{{Time: at(10, 0, 0), Energy: 0}, {Time: at(12, 0, 0), Energy: 48}},
+			from:     at(10, 0, 0), to: at(12, 0, 0),
+			wantDay: 32, wantNight: 16,
+		},
+		{
+			name:     "ночная зона внутри суток",
+			tariff:   daytime,
+			readings: []Reading{{Time: at(10, 0, 0), Energy: 0}, {Time: at(10, 6, 0), Energy: 6}},
+			from:     at(10, 0, 0), to: at(11, 0, 0),
+			wantDay: 2, wantNight: 4,
+		},
+		{
+			name:     "однозонный тариф",
+			tariff:   Tariff{Day: 6},
+			readings: []Reading{{Time: at(10, 22, 0), Energy: 0}, {Time: at(11, 2, 0), Energy: 4}},
+			from:     at(10, 0, 0), to: at(11, 0, 0),
+			wantDay: 2,
+		},
+		{
+			name:     "сброс счетчика",
+			tariff:   zoned,
+			readings: []Reading{{Time: at(10, 10, 0), Energy: 100}, {Time: at(10, 11, 0), Energy: 2}},
+			from:     at(10, 0, 0), to: at(11, 0, 0),
+			wantDay: 2,
+		},
+		{
+			name:     "одинаковое время показаний",
+			tariff:   zoned,
+			readings: []Reading{{Time: at(10, 23, 0), Energy: 1}, {Time: at(10, 23, 0), Energy: 2}},
+			from:     at(10, 0, 0), to: at(11, 0, 0),
+			wantNight: 1,
+		},
+		{
+			name:     "вне периода",
+			tariff:   zoned,
+			readings: []Reading{{Time: at(9, 10, 0), Energy: 0}, {Time: at(9, 12, 0), Energy: 5}},
+			from:     at(10, 0, 0), to: at(11, 0, 0),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i := range test.readings {
+				test.readings[i].Device = "plug"
+			}
+			total := Sum(Summarize(test.readings, test.from, test.to, test.tariff))
+
+			if math.Abs(total.DayKWh-test.wantDay) > 1e-9 || math.Abs(total.NightKWh-test.wantNight) > 1e-9 {
+				t.Fatalf("день %.3f, ночь %.3f; ожидалось день %.3f, ночь %.3f",
+					total.DayKWh, total.NightKWh, test.wantDay, test.wantNight)
+			}
+			if want := test.tariff.Cost(test.wantDay, test.wantNight); math.Abs(total.Cost-want) > 1e-9 {
+				t.Fatalf("стоимость %.3f, ожидалось %.3f", total.Cost, want)
+			}
+		})
+	}
+}
+
+func TestDailySplitsAcrossMidnight(t *testing.T) {
+	readings := []Reading{
+		{Time: at(10, 20, 0), Device: "plug", Energy: 0},
+		{Time: at(11, 4, 0), Device: "plug", Energy: 8},
+	}
+	daily := Daily(readings, at(10, 0, 0), at(12, 0, 0), Tariff{Day: 6})
+	if len(daily) != 2 {
+		t.Fatalf("дней: %d, ожидалось 2", len(daily))
+	}
+	if !daily[0].Date.Equal(at(10, 0, 0)) || daily[0].Total() != 4 || daily[1].Total() != 4 {
+		t.Fatalf("расход по дням: %+v", daily)
+	}
+}
@@ -0,0 +1,250 @@
+package gateway
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"telegramBot/config"
+	"telegramBot/logging"
+	"telegramBot/yandexapi"
+)
+
+var logger = logging.For("gateway")
+
+// Sender - путь отправки сообщений бота (с ограничением частоты запросов к Telegram)
+type Sender interface {
+	ResolveChat(target string) (chatID int64, threadID int, err error)
+	SendMessage(chatID int64, threadID int, text string) error
+	SendPhoto(chatID int64, threadID int, photo []byte, fileName string, caption string) error
+	SendDocument(chatID int64, threadID int, data []byte, fileName string, caption string) error
+}
+
+// Server - локальный HTTP API для отправки уведомлений из Home Assistant и скриптов:
+//
+//	POST /notify  {"chat": "family", "text": "...", "html": false}
+//	POST /photo   multipart: chat, caption, file
+//	POST /file    multipart: chat, caption, file
+//
+// Все запросы требуют заголовок Authorization: Bearer <NOTIFY_TOKEN>.
+type Server struct {
+	config config.GatewayConfig
+	sender Sender
+	server *http.Server
+}
+
+func NewServer(config config.GatewayConfig, sender Sender) *Server {
+	s := &Server{config: config, sender: sender}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /notify", s.authorized(s.handleNotify))
+	mux.HandleFunc("POST /photo", s.authorized(s.handleAttachment(true)))
+	mux.HandleFunc("POST /file", s.authorized(s.handleAttachment(false)))
+
+	s.server = &http.Server{
+		Addr:              config.Listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// Start запускает HTTP-сервер в фоне
+func (s *Server) Start() {
+	go func() {
+		logger.Info("🌐 HTTP API уведомлений слушает", "listen", s.config.Listen)
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("❌ HTTP API уведомлений остановлен", "error", err)
+		}
+	}()
+}
+
+// authorized проверяет bearer-токен
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.config.Token)) != 1 {
+			logger.Warn("⛔ Отказ в доступе", "method", r.Method, "path", r.URL.Path, "remote", r.RemoteAddr)
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		next(w, r)
+	}
+}
+
+type notifyRequest struct {
+	Chat string `json:"chat"`
+	Text string `json:"text"`
+	HTML bool   `json:"html"`
+}
+
+func (s *Server) handleNotify(w http.ResponseWriter, r *http.Request) {
+	var request notifyRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+			return
+		}
+	} else {
+		request.Chat = r.FormValue("chat")
+		request.Text = r.FormValue("text")
+		request.HTML, _ = strconv.ParseBool(r.FormValue("html"))
+	}
+
+	if strings.TrimSpace(request.Text) == "" {
+		writeError(w, http.StatusBadRequest, "text is required")
+		return
+	}
+
+	chatID, threadID, err := s.resolveChat(request.Chat)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	text := request.Text
+	if !request.HTML {
+		text = html.EscapeString(text)
+	}
+
+	logger.Info("🌐 Уведомление", "chat", s.chatName(request.Chat), "remote", r.RemoteAddr)
+	if err := s.sender.SendMessage(chatID, threadID, text); err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeOK(w, nil)
+}
+
+// handleAttachment принимает файл через multipart/form-data и отправляет его фото или документом
+func (s *Server) handleAttachment(photo bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, s.config.MaxUploadSize)
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid multipart form: "+err.Error())
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		file, header, err := r.FormFile("file")
+		if err != nil && photo {
+			file, header, err = r.FormFile("photo")
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "file is required")
+			return
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(file)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		chatID, threadID, err := s.resolveChat(r.FormValue("chat"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		caption := r.FormValue("caption")
+		if isHTML, _ := strconv.ParseBool(r.FormValue("html")); !isHTML {
+			caption = html.EscapeString(caption)
+		}
+
+		fileName := path.Base(header.Filename)
+		logger.Info("🌐 Файл", "file", fileName, "size", yandexapi.FormatBytes(int64(len(data))), "chat", s.chatName(r.FormValue("chat")), "remote", r.RemoteAddr)
+
+		response := map[string]any{}
+		if s.shouldArchive(r) {
+			remotePath, err := s.archive(fileName, data)
+			if err != nil {
+				logger.Error("❌ Ошибка архивации", "file", fileName, "error", err)
+				response["archive_error"] = err.Error()
+			} else {
+				response["archived"] = remotePath
+			}
+		}
+
+		if photo {
+			err = s.sender.SendPhoto(chatID, threadID, data, fileName, caption)
+		} else {
+			err = s.sender.SendDocument(chatID, threadID, data, fileName, caption)
+		}
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		writeOK(w, response)
+	}
+}
+
+// shouldArchive: вложения архивируются, если задан NOTIFY_ARCHIVE_DIR, либо по запросу archive=true
+func (s *Server) shouldArchive(r *http.Request) bool {
+	if s.config.ArchiveDir == "" {
+		return false
+	}
+	if value := r.FormValue("archive"); value != "" {
+		archive, _ := strconv.ParseBool(value)
+		return archive
+	}
+	return s.config.ArchiveAll
+}
+
+// archive сохраняет вложение на Яндекс.Диск в папку по дате
+func (s *Server) archive(fileName string, data []byte) (string, error) {
+	dir := path.Join(s.config.ArchiveDir, time.Now().Format("2006-01-02"))
+	if err := yandexapi.EnsureDirectory(dir); err != nil {
+		return "", err
+	}
+
+	name := time.Now().Format("150405") + "_" + fileName
+	if err := yandexapi.UploadFile(dir, name, data); err != nil {
+		return "", err
+	}
+	return path.Join(dir, name), nil
+}
+
+func (s *Server) resolveChat(target string) (int64, int, error) {
+	if target == "" {
+		target = s.config.DefaultChat
+	}
+	chatID, threadID, err := s.sender.ResolveChat(target)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unknown chat %q: %v", target, err)
+	}
+	return chatID, threadID, nil
+}
+
+func (s *Server) chatName(target string) string {
+	if target == "" {
+		return s.config.DefaultChat
+	}
+	return target
+}
+
+func writeOK(w http.ResponseWriter, fields map[string]any) {
+	response := map[string]any{"ok": true}
+	for key, value := range fields {
+		response[key] = value
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{"ok": false, "error": message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,59 @@
+package handlersTelegramBot
+
+import (
+	"strconv"
+	"time"
+
+	"telegramBot/models"
+)
+
+// Кнопки хранятся в памяти: после перезапуска бота или по истечении срока они перестают работать
+const callbackTTL = 24 * time.Hour
+
+// CallbackHandler обрабатывает нажатие inline-кнопки и возвращает текст всплывающего уведомления
+type CallbackHandler func(query *models.CallbackQuery) string
+
+type callbackEntry struct {
+	handler CallbackHandler
+	created time.Time
+}
+
+// callbackButton создает inline-кнопку, при нажатии на которую будет вызван handler.
+// В callback_data передается только короткий ключ - лимит Telegram 64 байта.
+func (h *MessageHandler) callbackButton(text string, handler CallbackHandler) models.InlineKeyboardButton {
+	id := h.callbackSeq.Add(1)
+	if id%100 == 0 {
+		h.cleanupCallbacks()
+	}
+
+	data := "cb:" + strconv.FormatUint(id, 36)
+	h.callbacks.Store(data, &callbackEntry{handler: handler, created: time.Now()})
+
+	return models.InlineKeyboardButton{Text: text, CallbackData: data}
+}
+
+func (h *MessageHandler) HandleCallbackQuery(query *models.CallbackQuery) {
+	entryI, ok := h.callbacks.Load(query.Data)
+	if !ok {
+		h.AnswerCallbackQuery(query.ID, "⌛ Кнопка устарела")
+		return
+	}
+
+	answer := entryI.(*callbackEntry).handler(query)
+	h.AnswerCallbackQuery(query.ID, answer)
+}
+
+// cleanupCallbacks удаляет просроченные обработчики кнопок
+func (h *MessageHandler) cleanupCallbacks() {
+	h.callbacks.Range(func(key, value any) bool {
+		if time.Since(value.(*callbackEntry).created) > callbackTTL {
+			h.callbacks.Delete(key)
+		}
+		return true
+	})
+}
+
+// keyboard собирает клавиатуру из рядов кнопок
+func keyboard(rows ...[]models.InlineKeyboardButton) models.InlineKeyboardMarkup {
+	return models.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
@@ -0,0 +1,208 @@
+package handlersTelegramBot
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"telegramBot/backup"
+	"telegramBot/models"
+	"telegramBot/yandexapi"
+)
+
+// HandleBackupNowCommand запускает резервное копирование вне расписания
+func (h *MessageHandler) HandleBackupNowCommand(update models.Update) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if !h.requireAdmin(message) {
+		return
+	}
+
+	h.SendMessage(chatID, threadID, "📦 Создаю резервную копию, это может занять несколько минут...")
+
+	h.goSafe(update, func() {
+		result, err := h.Backup.Run()
+		if err != nil {
+			h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка резервного копирования: %s", html.EscapeString(err.Error())))
+			return
+		}
+		h.SendMessage(chatID, threadID, backup.FormatResult(result))
+	})
+}
+
+// HandleBackupsCommand выводит список архивов резервных копий на Яндекс.Диске
+func (h *MessageHandler) HandleBackupsCommand(update models.Update) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if !h.requireAdmin(message) {
+		return
+	}
+
+	archives, err := h.Backup.List()
+	if err != nil {
+		updateLogger(update).Error("❌ Backup.List", "error", err)
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка получения списка архивов: %s", html.EscapeString(err.Error())))
+		return
+	}
+
+	if len(archives) == 0 {
+		h.SendMessage(chatID, threadID, "📭 Резервных копий пока нет")
+		return
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "📦 <b>Резервные копии</b> (%d):\n", len(archives))
+	fmt.Fprintln(&builder, strings.Repeat("─", 20))
+	for _, archive := range archives {
+		fmt.Fprintf(&builder, "🗄️ <code>%s</code>\n      %s · %s\n",
+			archive.Name,
+			archive.Created.Format("02.01.2006 15:04"),
+			yandexapi.FormatBytes(archive.Size),
+		)
+	}
+	fmt.Fprintln(&builder, strings.Repeat("─", 20))
+	fmt.Fprint(&builder, "♻️ Восстановить: <code>/restore &lt;архив&gt; &lt;каталог&gt;</code>")
+
+	h.SendMessage(chatID, threadID, builder.String())
+}
+
+// HandleRestoreCommand скачивает архив, показывает пробный прогон относительно каталога и после
+// подтверждения распаковывает архив в этот каталог
+func (h *MessageHandler) HandleRestoreCommand(update models.Update, args []string) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if !h.requireAdmin(message) {
+		return
+	}
+
+	if len(args) != 2 {
+		h.SendMessage(chatID, threadID, "ℹ️ Использование: <code>/restore &lt;архив&gt; &lt;каталог&gt;</code>\n"+
+			"Каталог - один из BACKUP_DIRS (восстанавливается только он, например <code>/backup/homeassistant</code>) "+
+			"или общий родительский каталог, в котором лежат папки всех источников (<code>/backup</code>)\n"+
+			"Список архивов: /backups")
+		return
+	}
+	archiveName, target := args[0], args[1]
+	source := h.Backup.SourceFor(target)
+
+	h.SendMessage(chatID, threadID, fmt.Sprintf("📥 Скачиваю <code>%s</code> и сравниваю с <code>%s</code>...",
+		html.EscapeString(archiveName), html.EscapeString(target)))
+
+	h.goSafe(update, func() {
+		data, verified, err := h.Backup.Download(archiveName)
+		if err != nil {
+			updateLogger(update).Error("❌ Backup.Download", "error", err)
+			h.SendMessage(chatID, threadID, fmt.Sprintf("❌ %s", html.EscapeString(err.Error())))
+			return
+		}
+
+		plan, err := backup.Plan(data, target, source)
+		if err != nil {
+			updateLogger(update).Error("❌ backup.Plan", "error", err)
+			h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка проверки архива: %s", html.EscapeString(err.Error())))
+			return
+		}
+		plan.Archive = archiveName
+		plan.Verified = verified
+
+		if len(plan.New) == 0 && len(plan.Changed) == 0 {
+			h.SendMessage(chatID, threadID, "✅ Каталог уже совпадает с архивом, восстанавливать нечего")
+			return
+		}
+
+		// Архив не держим в памяти до нажатия кнопки: при подтверждении он скачивается заново
+		markup := keyboard([]models.InlineKeyboardButton{
+			h.callbackButton("✅ Восстановить", func(query *models.CallbackQuery) string {
+				if !h.isAdmin(query.From.ID) {
+					return "⛔ Только для администраторов"
+				}
+				h.goSafe(models.Update{CallbackQuery: query}, func() {
+					h.extractBackup(query.Message, archiveName, target, source)
+				})
+				return "⏳ Распаковываю..."
+			}),
+			h.callbackButton("❌ Отмена", func(query *models.CallbackQuery) string {
+				if !h.isAdmin(query.From.ID) {
+					return "⛔ Только для администраторов"
+				}
+				h.EditMessageText(query.Message.Chat.ID, query.Message.MessageID, "❌ Восстановление отменено.", nil)
+				return ""
+			}),
+		})
+
+		if _, err := h.SendMessageWithKeyboard(chatID, threadID, formatRestorePlan(plan), markup); err != nil {
+			updateLogger(update).Error("❌ SendMessageWithKeyboard", "error", err)
+		}
+	})
+}
+
+// extractBackup повторно скачивает архив и распаковывает его в целевой каталог
+func (h *MessageHandler) extractBackup(message *models.Message, archiveName string, target string, source string) {
+	chatID := message.Chat.ID
+	messageID := message.MessageID
+
+	h.EditMessageText(chatID, messageID, fmt.Sprintf("⏳ Скачиваю и распаковываю <code>%s</code> в <code>%s</code>...",
+		html.EscapeString(archiveName), html.EscapeString(target)), nil)
+
+	data, _, err := h.Backup.Download(archiveName)
+	if err != nil {
+		logger.Error("❌ Backup.Download", "error", err)
+		h.EditMessageText(chatID, messageID, fmt.Sprintf("❌ %s", html.EscapeString(err.Error())), nil)
+		return
+	}
+
+	written, err := backup.Extract(data, target, source)
+	if err != nil {
+		logger.Error("❌ backup.Extract", "error", err)
+		h.EditMessageText(chatID, messageID, fmt.Sprintf("❌ Ошибка распаковки (записано файлов: %d): %s",
+			written, html.EscapeString(err.Error())), nil)
+		return
+	}
+
+	logger.Info("♻️ Архив восстановлен", "archive", archiveName, "target", target, "source", source, "files", written)
+	h.EditMessageText(chatID, messageID, fmt.Sprintf("✅ Архив <code>%s</code> восстановлен в <code>%s</code>\n📄 Записано файлов: <b>%d</b>\n\nПерезапустите сервис, чтобы он подхватил файлы",
+		html.EscapeString(archiveName), html.EscapeString(target), written), nil)
+}
+
+// formatRestorePlan описывает пробный прогон восстановления
+func formatRestorePlan(plan *backup.RestorePlan) string {
+	const maxListed = 15
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "🔍 <b>Пробный прогон восстановления</b>\n")
+	fmt.Fprintf(&builder, "📦 Архив: <code>%s</code>\n", html.EscapeString(plan.Archive))
+	fmt.Fprintf(&builder, "📁 Каталог: <code>%s</code>\n", html.EscapeString(plan.Target))
+	if plan.Source != "" {
+		fmt.Fprintf(&builder, "🗂️ Источник: <code>%s</code>\n", html.EscapeString(plan.Source))
+	}
+	if plan.Verified {
+		fmt.Fprintf(&builder, "🔐 Контрольная сумма: <b>совпадает</b>\n")
+	} else {
+		fmt.Fprintf(&builder, "⚠️ Контрольная сумма: <b>не проверена</b>\n")
+	}
+	fmt.Fprintf(&builder, "\n🆕 Новых: <b>%d</b> · ✏️ Изменится: <b>%d</b> · ✅ Без изменений: <b>%d</b>\n",
+		len(plan.New), len(plan.Changed), plan.Unchanged)
+
+	list := func(title string, files []string) {
+		if len(files) == 0 {
+			return
+		}
+		fmt.Fprintf(&builder, "\n<b>%s</b>\n", title)
+		for i, file := range files {
+			if i == maxListed {
+				fmt.Fprintf(&builder, "… и еще %d\n", len(files)-maxListed)
+				break
+			}
+			fmt.Fprintf(&builder, "• <code>%s</code>\n", html.EscapeString(file))
+		}
+	}
+	list("✏️ Будут перезаписаны:", plan.Changed)
+	list("🆕 Будут созданы:", plan.New)
+	return builder.String()
+}
@@ -4,11 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"strings"
 	"time"
 
+	"telegramBot/logging"
 	"telegramBot/models"
 	"telegramBot/yandexapi"
 )
@@ -31,10 +31,10 @@ func (h *MessageHandler) HandleStartCommand(update models.Update) {
 ✅ Проверять фотографии и документы
 ✅ Определять JPG изображения
 ✅ Показывать детальную информацию
-✅ Настраиваемый вывод логов API
+✅ Настраиваемые уровни логов по компонентам
 
 ⚙️ <b>Конфигурация:</b>
-• Макс. длина вывода API: <b>%d символов</b>
+• Уровень логов: <b>%s</b>
 
 📊 <b>Информация о текущем сообщении:</b>
 • 👤 Ваше имя: <b>%s</b>
@@ -42,7 +42,7 @@ func (h *MessageHandler) HandleStartCommand(update models.Update) {
 • 💬 ID чата: <code>%d</code>
 • 🏷️ ID топика: <code>%d</code>`,
 		message.From.FirstName,
-		h.Config.MaxLengthAPIOutput,
+		logging.Describe(),
 		message.From.FirstName,
 		message.From.ID,
 		message.Chat.ID,
@@ -61,10 +61,36 @@ func (h *MessageHandler) HandleHelpCommand(update models.Update) {
 • /help - показать эту справку
 • /features - возможности бота
 • /info - информация о текущем чате
+• /versions &lt;путь&gt; - предыдущие версии файла
+• /restoreversion &lt;путь&gt; &lt;номер&gt; - восстановить версию файла
+• /backupnow - создать резервную копию Home Assistant (для администраторов)
+• /backups - список резервных копий
+• /restore &lt;архив&gt; &lt;каталог&gt; - сравнить резервную копию с каталогом и восстановить ее
+• /mqtt - публикация и подписки MQTT (для администраторов)
+• /devices - список Zigbee-устройств
+• /device &lt;имя&gt; - состояние и управление устройством
+• /pair [минуты] - режим сопряжения Zigbee (для администраторов)
+• /zigbeemap - карта Zigbee-сети (для администраторов)
+• /health zigbee - батарейки, доступность и связь Zigbee-устройств
+• /server - состояние сервера: нагрузка, температура, память, диски, сеть (для администраторов)
+• /wake [устройство] - разбудить компьютер по сети (Wake-on-LAN)
+• /whoishome - какие устройства сейчас в домашней сети (для администраторов)
+• /containers - состояние Docker-контейнеров (для администраторов)
+• /restart &lt;имя&gt; - перезапустить контейнер
+• /logs &lt;имя&gt; [строк] - лог контейнера
+• /logs [debug|info|warn|error] [строк] - последние записи лога бота (для администраторов)
+• /connectdisk [код] - подключить Яндекс.Диск через OAuth (для администраторов)
+• /reloadconfig - перечитать файл конфигурации (для администраторов)
+• /ha states|get|call - сущности и сервисы Home Assistant (для администраторов)
+• /ha fav - избранные скрипты и сцены (для администраторов)
+• /snap [камера] - снимок с камеры (для администраторов)
+• /chart &lt;entity_id&gt; [24h|7d|30d] - график истории сенсора (для администраторов)
+• /energy [today|yesterday|month] - расход электроэнергии
+• Отправьте ссылку на файл или публичную папку Яндекс.Диска - бот предложит сохранить ее на диск
 
 ⚙️ <b>Настройки:</b>
-• Максимальная длина вывода API: <b>%d символов</b>`,
-		h.Config.MaxLengthAPIOutput,
+• Уровень логов: <b>%s</b>`,
+		logging.Describe(),
 	)
 
 	h.SendMessage(message.Chat.ID, message.MessageThreadID, response)
@@ -75,12 +101,12 @@ func (h *MessageHandler) HandleFeaturesCommand(update models.Update) {
 	response := fmt.Sprintf(`🚀 <b>Возможности бота</b>
 
 🔧 <b>Технические возможности:</b>
-• <b>Настраиваемый вывод API</b> - Макс. длина логов: <b>%d символов</b>
+• <b>Структурированные логи</b> - уровень: <b>%s</b>
 
 ⚙️ <b>Настройки конфигурации:</b>
-• MAX_LENGTH_MESSEGE_API - Макс. длина вывода API (текущее значение: %d)`,
-		h.Config.MaxLengthAPIOutput,
-		h.Config.MaxLengthAPIOutput,
+• DEBUG, LOG_LEVELS, LOG_FORMAT - уровни и формат логов (формат: %s)`,
+		logging.Describe(),
+		h.Config().LogFormat,
 	)
 
 	h.SendMessage(message.Chat.ID, message.MessageThreadID, response)
@@ -115,12 +141,12 @@ func (h *MessageHandler) HandleInfoCommand(update models.Update) {
 • 🏷️ Топик: %s
 
 🔧 <b>Техническая информация:</b>
-• Макс. длина API логов: <b>%d символов</b>`,
+• Уровень логов: <b>%s</b>`,
 		chatType,
 		h.getChatTitle(message.Chat),
 		message.Chat.ID,
 		topicStatus,
-		h.Config.MaxLengthAPIOutput,
+		logging.Describe(),
 	)
 
 	h.SendMessage(message.Chat.ID, message.MessageThreadID, response)
@@ -132,15 +158,14 @@ func (h *MessageHandler) HandleInfoDiskCommand(update models.Update) {
 	info, err := yandexapi.PrintDiskUsage()
 
 	if err != nil {
-		log.Printf("ERROR PrintDiskUsage: %v", err)
+		updateLogger(update).Error("❌ PrintDiskUsage", "error", err)
 		response := fmt.Sprintf("❌ Ошибка получения информации о диске: %v", err)
 		h.SendMessage(message.Chat.ID, message.MessageThreadID, response)
 		return
 	}
 
-	fmt.Println(info)
-	response := fmt.Sprintf(info)
-	h.SendMessage(message.Chat.ID, message.MessageThreadID, response)
+	logger.Debug("💾 Информация о диске", "info", info)
+	h.SendMessage(message.Chat.ID, message.MessageThreadID, info)
 }
 
 func (h *MessageHandler) HandleCreateDirectory(update models.Update) {
@@ -151,10 +176,8 @@ func (h *MessageHandler) HandleCreateDirectory(update models.Update) {
 	h.SendMessage(chatID, threadID, "📁 Введите путь для создания директории (например, /photos):")
 	step1 := func(text string) (string, InputHandler, error) {
 		path := text
-		print("step1. text %s", path)
 
 		step2 := func(name string) (string, InputHandler, error) {
-			print("step2. name %s", name)
 			err := yandexapi.CreateDirectory(path, name)
 
 			if err != nil {
@@ -175,10 +198,8 @@ func (h *MessageHandler) HandleDeleteDirectory(update models.Update) {
 	h.SendMessage(chatID, threadID, "📁 Введите путь для удаления директории (например, /photos):")
 	step1 := func(text string) (string, InputHandler, error) {
 		path := text
-		print("step1. text %s", path)
 
 		step2 := func(name string) (string, InputHandler, error) {
-			print("step2. name %s", name)
 			err := yandexapi.DeleteDirectory(path, name)
 
 			if err != nil {
@@ -199,7 +220,6 @@ func (h *MessageHandler) HandleContentsDirectory(update models.Update) {
 	h.SendMessage(chatID, threadID, "📁 Введите путь для просмотра содержимого директории (например, /photos):")
 	step1 := func(text string) (string, InputHandler, error) {
 		path := text
-		print("step1. text %s", path)
 
 		files, err := yandexapi.PrintDirectoryContents(path)
 		var builder strings.Builder
@@ -210,6 +230,11 @@ func (h *MessageHandler) HandleContentsDirectory(update models.Update) {
 			name, _ := file["name"].(string)
 			fileType, _ := file["type"].(string)
 
+			// Скрытую папку с версиями файлов не показываем
+			if name == ".versions" {
+				continue
+			}
+
 			if fileType == "file" {
 				size, _ := file["size"].(float64)
 				fmt.Fprintf(&builder, "📄 %-30s %10s \n", name, yandexapi.FormatBytes(int64(size)))
@@ -222,7 +247,7 @@ func (h *MessageHandler) HandleContentsDirectory(update models.Update) {
 			return "", nil, fmt.Errorf("не удалось просмотреть директорию: %w", err)
 		}
 
-		fmt.Fprintf(&builder, (strings.Repeat("─", 20)))
+		fmt.Fprint(&builder, strings.Repeat("─", 20))
 		fmt.Fprintf(&builder, "\nTotal items: %d\n", len(files))
 		h.SendMessage(chatID, threadID, builder.String())
 
@@ -0,0 +1,144 @@
+package handlersTelegramBot
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"time"
+
+	"telegramBot/camera"
+	"telegramBot/homeassistant"
+	"telegramBot/models"
+	"telegramBot/scheduler"
+)
+
+// startCameras настраивает камеры, снимки по расписанию и очистку архива
+func (h *MessageHandler) startCameras() error {
+	if len(h.Config().Camera.Cameras) == 0 {
+		return nil
+	}
+
+	service, err := camera.NewService(h.HA, h.Config().Camera)
+	if err != nil {
+		return err
+	}
+	if h.Config().Camera.Chat != "" {
+		if _, _, err := h.ResolveChat(h.Config().Camera.Chat); err != nil {
+			return fmt.Errorf("CAMERA_CHAT: %v", err)
+		}
+	}
+	h.Cameras = service
+
+	if schedule := h.Config().Camera.Schedule; schedule != "" {
+		if err := scheduler.Add("camera-snapshots", schedule, service.SnapshotAll); err != nil {
+			return err
+		}
+	}
+	if h.Config().Camera.RetentionDays > 0 {
+		if err := scheduler.Add("camera-prune", "30 4 * * *", service.Prune); err != nil {
+			return err
+		}
+	}
+
+	logger.Info("📷 Камеры настроены", "count", len(service.Cameras()))
+	return nil
+}
+
+// HandleSnapCommand отправляет текущий кадр камеры
+func (h *MessageHandler) HandleSnapCommand(update models.Update, args []string) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if !h.requireAdmin(message) {
+		return
+	}
+	if h.Cameras == nil {
+		h.SendMessage(chatID, threadID, "❌ Камеры не настроены (CAMERAS)")
+		return
+	}
+
+	if len(args) == 0 {
+		var buttons []models.InlineKeyboardButton
+		for _, cam := range h.Cameras.Cameras() {
+			buttons = append(buttons, h.callbackButton("📷 "+cam.Name, func(query *models.CallbackQuery) string {
+				if !h.isAdmin(query.From.ID) {
+					return "⛔ Только для администраторов"
+				}
+				h.goSafe(models.Update{CallbackQuery: query}, func() {
+					h.sendSnapshot(query.Message.Chat.ID, query.Message.MessageThreadID, cam)
+				})
+				return "📷 Снимаю..."
+			}))
+		}
+		if _, err := h.SendMessageWithKeyboard(chatID, threadID, "📷 <b>Выберите камеру:</b>", keyboard(buttons)); err != nil {
+			updateLogger(update).Error("❌ SendMessageWithKeyboard", "error", err)
+		}
+		return
+	}
+
+	cam, ok := h.Cameras.Camera(args[0])
+	if !ok {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Камера <b>%s</b> не найдена. Список: /snap", html.EscapeString(args[0])))
+		return
+	}
+	h.goSafe(update, func() {
+		h.sendSnapshot(chatID, threadID, cam)
+	})
+}
+
+// sendSnapshot снимает кадр и отправляет его в чат
+func (h *MessageHandler) sendSnapshot(chatID int64, threadID int, cam camera.Camera) {
+	image, err := h.Cameras.Snapshot(cam)
+	if err != nil {
+		logger.Error("❌ Ошибка снимка", "camera", cam.Name, "error", err)
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Камера <b>%s</b>: %s", html.EscapeString(cam.Name), html.EscapeString(err.Error())))
+		return
+	}
+
+	caption := fmt.Sprintf("📷 <b>%s</b> · %s", html.EscapeString(cam.Name), time.Now().Format("02.01.2006 15:04:05"))
+	if err := h.SendPhoto(chatID, threadID, image, cam.Name+".jpg", caption); err != nil {
+		logger.Error("❌ SendPhoto", "error", err)
+	}
+}
+
+// handleCameraEvent снимает камеры по событию (например, открытие двери),
+// архивирует кадры и отправляет их в CAMERA_CHAT
+func (h *MessageHandler) handleCameraEvent(event homeassistant.Event) {
+	if event.EventType != "state_changed" {
+		return
+	}
+
+	var change homeassistant.StateChange
+	if err := json.Unmarshal(event.Data, &change); err != nil {
+		return
+	}
+
+	for _, cam := range h.Cameras.Triggered(change) {
+		h.goSafe(models.Update{}, func() {
+			taken := time.Now()
+			image, err := h.Cameras.Snapshot(cam)
+			if err != nil {
+				logger.Error("❌ Ошибка снимка по событию", "camera", cam.Name, "entity_id", change.EntityID, "error", err)
+				return
+			}
+			logger.Info("📷 Снимок по событию", "camera", cam.Name, "entity_id", change.EntityID)
+
+			caption := fmt.Sprintf("📷 <b>%s</b> · %s\n🔔 %s", html.EscapeString(cam.Name),
+				taken.Format("02.01.2006 15:04:05"), html.EscapeString(change.NewState.Name()))
+
+			if _, err := h.Cameras.Archive(cam, image, taken); err != nil {
+				logger.Error("❌ Ошибка архивации снимка", "camera", cam.Name, "error", err)
+				caption += "\n⚠️ Не удалось сохранить на Яндекс.Диск"
+			}
+
+			if h.Config().Camera.Chat == "" {
+				return
+			}
+			chatID, threadID, _ := h.ResolveChat(h.Config().Camera.Chat)
+			if err := h.SendPhoto(chatID, threadID, image, cam.Name+".jpg", caption); err != nil {
+				logger.Error("❌ SendPhoto", "error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package handlersTelegramBot
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"telegramBot/chart"
+	"telegramBot/models"
+	"telegramBot/scheduler"
+)
+
+// Периоды, доступные в /chart
+var chartPeriods = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+// startDailyChart планирует ежедневную отправку графика
+func (h *MessageHandler) startDailyChart() error {
+	config := h.Config().Chart
+	if len(config.Entities) == 0 {
+		return nil
+	}
+
+	chatID, threadID, err := h.ResolveChat(config.Chat)
+	if err != nil {
+		return fmt.Errorf("CHART_DAILY_CHAT: %v", err)
+	}
+
+	return scheduler.Add("daily-chart", config.Schedule, func() {
+		if err := h.sendChart(chatID, threadID, config.Entities, config.Period); err != nil {
+			logger.Error("❌ Ошибка отправки ежедневного графика", "error", err)
+		}
+	})
+}
+
+// HandleChartCommand - /chart <entity> [entity...] [24h|7d|30d]
+func (h *MessageHandler) HandleChartCommand(update models.Update, args []string) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if !h.requireAdmin(message) {
+		return
+	}
+
+	if h.HA == nil {
+		h.SendMessage(chatID, threadID, "❌ Home Assistant не настроен (HA_TOKEN)")
+		return
+	}
+
+	period := 24 * time.Hour
+	if len(args) > 0 {
+		if value, ok := chartPeriods[args[len(args)-1]]; ok {
+			period = value
+			args = args[:len(args)-1]
+		}
+	}
+	if len(args) == 0 {
+		h.SendMessage(chatID, threadID, `ℹ️ <b>Использование:</b>
+<code>/chart &lt;entity_id&gt; [entity_id...] [24h|7d|30d]</code>
+Например: <code>/chart sensor.temperature_living sensor.temperature_bedroom 7d</code>`)
+		return
+	}
+
+	h.goSafe(update, func() {
+		if err := h.sendChart(chatID, threadID, args, period); err != nil {
+			h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка построения графика: %s", html.EscapeString(err.Error())))
+		}
+	})
+}
+
+// sendChart загружает историю из Home Assistant, рисует график и отправляет его в чат
+func (h *MessageHandler) sendChart(chatID int64, threadID int, entityIDs []string, period time.Duration) error {
+	end := time.Now()
+	history, err := h.HA.History(entityIDs, end.Add(-period), end)
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		return fmt.Errorf("нет истории для %s", strings.Join(entityIDs, ", "))
+	}
+
+	var series []chart.Series
+	var names []string
+	for _, entity := range history {
+		s := chart.Series{Name: entity.Name, Unit: entity.Unit}
+		for _, state := range entity.States {
+			// unavailable, unknown и т.п. пропускаем
+			if value, err := strconv.ParseFloat(state.State, 64); err == nil {
+				s.Points = append(s.Points, chart.Point{Time: state.LastChanged, Value: value})
+			}
+		}
+		series = append(series, s)
+		names = append(names, entity.Name)
+	}
+
+	title := fmt.Sprintf("%s — %s", strings.Join(names, ", "), formatPeriod(period))
+	image, err := chart.Render(title, series)
+	if err != nil {
+		return err
+	}
+
+	caption := fmt.Sprintf("📈 <b>%s</b>\n🕒 %s – %s", html.EscapeString(strings.Join(names, ", ")),
+		end.Add(-period).Format("02.01 15:04"), end.Format("02.01 15:04"))
+	return h.SendPhoto(chatID, threadID, image, "chart.png", caption)
+}
+
+func formatPeriod(period time.Duration) string {
+	if period < 48*time.Hour {
+		return fmt.Sprintf("%.0f ч", period.Hours())
+	}
+	return fmt.Sprintf("%.0f дн.", period.Hours()/24)
+}
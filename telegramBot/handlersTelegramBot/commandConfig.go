@@ -0,0 +1,130 @@
+package handlersTelegramBot
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"strings"
+	"time"
+
+	"telegramBot/config"
+	"telegramBot/logging"
+	"telegramBot/models"
+)
+
+// startConfigWatch перечитывает файл конфигурации при изменении и сообщает администраторам,
+// что применено, а что требует перезапуска
+func (h *MessageHandler) startConfigWatch() {
+	current := h.Config()
+	if current.WatchInterval <= 0 {
+		return
+	}
+
+	modified := func() time.Time {
+		info, err := os.Stat(current.File)
+		if err != nil {
+			return time.Time{}
+		}
+		return info.ModTime()
+	}
+	last := modified()
+
+	go func() {
+		ticker := time.NewTicker(current.WatchInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if changed := modified(); !changed.Equal(last) {
+				last = changed
+				logger.Info("📝 Файл конфигурации изменился", "file", current.File)
+				if report, changed := h.reloadConfig(); changed {
+					h.NotifyAdmin(report)
+				}
+			}
+		}
+	}()
+	logger.Info("👀 Слежение за файлом конфигурации", "file", current.File, "interval", current.WatchInterval)
+}
+
+// HandleReloadConfigCommand - /reloadconfig перечитывает конфигурацию
+func (h *MessageHandler) HandleReloadConfigCommand(update models.Update) {
+	message := update.Message
+	if !h.requireAdmin(message) {
+		return
+	}
+	report, _ := h.reloadConfig()
+	h.SendMessage(message.Chat.ID, message.MessageThreadID, report)
+}
+
+// reloadConfig перечитывает конфигурацию, применяет безопасные изменения и возвращает отчет.
+// changed - есть ли о чем сообщить: изменения или ошибки
+func (h *MessageHandler) reloadConfig() (report string, changed bool) {
+	h.reloadMu.Lock()
+	defer h.reloadMu.Unlock()
+
+	next, changes, err := h.Config().Reload()
+	if err != nil {
+		logger.Error("❌ Конфигурация не перечитана", "error", err)
+
+		var builder strings.Builder
+		builder.WriteString("❌ <b>Конфигурация не перечитана</b>, действуют прежние настройки:\n")
+		var validation *config.ValidationError
+		if errors.As(err, &validation) {
+			for _, problem := range validation.Problems {
+				fmt.Fprintf(&builder, "\n• %s", html.EscapeString(problem))
+			}
+		} else {
+			builder.WriteString(html.EscapeString(err.Error()))
+		}
+		return builder.String(), true
+	}
+
+	var live, restart []config.Change
+	for _, change := range changes {
+		if change.Live {
+			live = append(live, change)
+		} else {
+			restart = append(restart, change)
+		}
+	}
+	if len(changes) == 0 {
+		return "✅ Конфигурация перечитана, изменений нет", false
+	}
+
+	if len(live) > 0 {
+		h.applyConfig(next)
+	} else {
+		// Только запоминаем прочитанные значения, чтобы не сообщать об этих изменениях снова
+		h.config.Store(next)
+	}
+	logger.Info("🔄 Конфигурация перечитана", "applied", config.FormatChanges(live), "restart_required", config.FormatChanges(restart))
+
+	var builder strings.Builder
+	builder.WriteString("🔄 <b>Конфигурация перечитана</b>")
+	if len(live) > 0 {
+		fmt.Fprintf(&builder, "\n\n✅ Применено: <code>%s</code>", html.EscapeString(config.FormatChanges(live)))
+	}
+	if len(restart) > 0 {
+		fmt.Fprintf(&builder, "\n\n♻️ Требуют перезапуска бота: <code>%s</code>", html.EscapeString(config.FormatChanges(restart)))
+	}
+	return builder.String(), true
+}
+
+// applyConfig подставляет новую конфигурацию и передает пороги фоновым проверкам
+func (h *MessageHandler) applyConfig(next *config.Config) {
+	h.config.Store(next)
+
+	err := logging.Setup(logging.Options{Format: next.LogFormat, Debug: next.Debug, Levels: next.LogLevels, Buffer: next.LogBuffer})
+	if err != nil {
+		logger.Error("❌ Ошибка настройки логов", "error", err)
+	}
+	if h.ZigbeeMonitor != nil {
+		h.ZigbeeMonitor.Reconfigure(next.Zigbee)
+	}
+	if h.HostMonitor != nil {
+		h.HostMonitor.Reconfigure(next.Host)
+	}
+	if h.DockerMonitor != nil {
+		h.DockerMonitor.Reconfigure(next.Docker)
+	}
+}
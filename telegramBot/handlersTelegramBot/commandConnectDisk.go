@@ -0,0 +1,98 @@
+package handlersTelegramBot
+
+import (
+	"fmt"
+	"html"
+	"path/filepath"
+	"time"
+
+	"telegramBot/config"
+	"telegramBot/models"
+	"telegramBot/yandexapi/initYD"
+	"telegramBot/yandexapi/oauth"
+)
+
+// StartYandexOAuth подключает сохраненный токен Яндекс.Диска и обновляет его в фоне
+func (h *MessageHandler) StartYandexOAuth() {
+	current := h.Config()
+	h.YandexOAuth = oauth.NewManager(current.YandexOAuth, filepath.Join(current.DataDir, "yandex_token.enc"),
+		initYD.GetYandexDiskAPI().SetToken, h.NotifyAdmin)
+	if !h.YandexOAuth.Enabled() {
+		return
+	}
+
+	if err := h.YandexOAuth.Load(); err != nil {
+		logger.Error("❌ Ошибка загрузки токена Яндекс.Диска", "error", err)
+	}
+	h.YandexOAuth.Start()
+}
+
+// HandleConnectDiskCommand - /connectdisk подключает Яндекс.Диск по коду устройства,
+// /connectdisk <код> - по коду подтверждения со страницы авторизации
+func (h *MessageHandler) HandleConnectDiskCommand(update models.Update, args []string) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if !h.requireAdmin(message) {
+		return
+	}
+	if !h.YandexOAuth.Enabled() {
+		h.SendMessage(chatID, threadID, "❌ Подключение через OAuth не настроено: задайте YANDEX_CLIENT_ID и YANDEX_CLIENT_SECRET")
+		return
+	}
+	if _, err := config.SecretsKey(); err != nil {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Токены хранятся зашифрованными, а ключа нет: %s", html.EscapeString(err.Error())))
+		return
+	}
+
+	if len(args) > 0 {
+		if err := h.YandexOAuth.Exchange(args[0]); err != nil {
+			updateLogger(update).Error("❌ Ошибка подключения Яндекс.Диска", "error", err)
+			h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Не удалось подключить Яндекс.Диск: %s", html.EscapeString(err.Error())))
+			return
+		}
+		h.SendMessage(chatID, threadID, h.diskConnectedText())
+		return
+	}
+
+	code, err := h.YandexOAuth.RequestDeviceCode()
+	if err != nil {
+		updateLogger(update).Error("❌ Ошибка запроса кода Яндекс OAuth", "error", err)
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Не удалось начать подключение: %s", html.EscapeString(err.Error())))
+		return
+	}
+
+	text := fmt.Sprintf(`🔑 <b>Подключение Яндекс.Диска</b>
+
+1. Откройте %s
+2. Введите код <code>%s</code>
+
+Код действует %v. Бот сообщит, когда доступ будет разрешен.
+
+Или разрешите доступ <a href="%s">по ссылке</a> и пришлите /connectdisk &lt;код&gt;`,
+		html.EscapeString(code.VerificationURL), html.EscapeString(code.UserCode),
+		time.Duration(code.ExpiresIn)*time.Second, html.EscapeString(h.YandexOAuth.AuthorizeURL()))
+	messageID, err := h.sendMessage(chatID, threadID, text, nil)
+	if err != nil {
+		return
+	}
+
+	h.goSafe(update, func() {
+		result := h.diskConnectedText()
+		if err := h.YandexOAuth.WaitDevice(code); err != nil {
+			updateLogger(update).Error("❌ Ошибка подключения Яндекс.Диска", "error", err)
+			result = fmt.Sprintf("❌ Не удалось подключить Яндекс.Диск: %s", html.EscapeString(err.Error()))
+		}
+		h.EditMessageText(chatID, messageID, result, nil)
+	})
+}
+
+func (h *MessageHandler) diskConnectedText() string {
+	token := h.YandexOAuth.Token()
+	if token == nil {
+		return "✅ Яндекс.Диск подключен"
+	}
+	return fmt.Sprintf("✅ Яндекс.Диск подключен. Токен действует до %s и обновляется автоматически",
+		token.Expiry.Format("02.01.2006 15:04"))
+}
@@ -0,0 +1,245 @@
+package handlersTelegramBot
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"telegramBot/dockerapi"
+	"telegramBot/models"
+)
+
+const (
+	dockerRestartTimeout = 30 * time.Second
+	dockerLogsDefault    = 50
+	dockerLogsMax        = 5000
+
+	// Логи длиннее отправляются файлом
+	dockerLogsInlineLimit = 3500
+)
+
+// StartDocker подключается к Docker Engine и запускает мониторинг контейнеров
+func (h *MessageHandler) StartDocker() error {
+	socket := h.Config().Docker.Socket
+	if socket == "" {
+		return nil
+	}
+	if _, err := os.Stat(socket); err != nil {
+		logger.Info("ℹ️ Docker-сокет недоступен, управление контейнерами выключено", "socket", socket)
+		return nil
+	}
+
+	h.Docker = dockerapi.New(socket)
+	if _, err := h.Docker.Containers(); err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			return fmt.Errorf("нет прав на %s: добавьте пользователя бота в группу сокета (group_add: DOCKER_GID в docker-compose.yml, DOCKER_GID=$(stat -c %%g %s)): %w",
+				socket, socket, err)
+		}
+		return err
+	}
+
+	h.DockerMonitor = dockerapi.NewMonitor(h.Docker, h.Config().Docker, h.NotifyAdmin)
+	h.DockerMonitor.Start()
+	return nil
+}
+
+// HandleContainersCommand выводит состояние контейнеров
+func (h *MessageHandler) HandleContainersCommand(update models.Update) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if !h.requireAdmin(message) {
+		return
+	}
+	if h.Docker == nil {
+		h.SendMessage(chatID, threadID, "❌ Docker недоступен (DOCKER_SOCKET)")
+		return
+	}
+
+	containers, err := h.Docker.Containers()
+	if err != nil {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка получения контейнеров: %s", html.EscapeString(err.Error())))
+		return
+	}
+	sort.Slice(containers, func(i, j int) bool { return containers[i].Name() < containers[j].Name() })
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "🐳 <b>Контейнеры</b> (%d):\n", len(containers))
+	fmt.Fprintln(&builder, strings.Repeat("─", 20))
+	for _, container := range containers {
+		if !h.DockerMonitor.Watched(container.Name()) {
+			continue
+		}
+
+		details, err := h.Docker.Inspect(container.ID)
+		if err != nil {
+			fmt.Fprintf(&builder, "❔ <b>%s</b> — %s\n", html.EscapeString(container.Name()), html.EscapeString(err.Error()))
+			continue
+		}
+
+		fmt.Fprintf(&builder, "%s <b>%s</b> — %s", containerIcon(details), html.EscapeString(container.Name()), html.EscapeString(details.State.Status))
+		if health := details.HealthStatus(); health != "" {
+			fmt.Fprintf(&builder, " (%s)", html.EscapeString(health))
+		}
+		fmt.Fprintln(&builder)
+
+		if details.State.Running {
+			fmt.Fprintf(&builder, "      ⏱️ запущен %s", formatAgo(details.State.StartedAt))
+		} else {
+			fmt.Fprintf(&builder, "      ⏹️ код выхода %d", details.State.ExitCode)
+		}
+		fmt.Fprintf(&builder, " · 🔄 перезапусков: %d\n", details.RestartCount)
+	}
+
+	if alerts := h.DockerMonitor.Active(); len(alerts) > 0 {
+		fmt.Fprintln(&builder, "\n🚨 <b>Активные оповещения:</b>")
+		for _, alert := range alerts {
+			fmt.Fprintf(&builder, "• %s\n", alert.Message)
+		}
+	}
+	fmt.Fprint(&builder, "\n<code>/restart &lt;имя&gt;</code> · <code>/logs &lt;имя&gt; [строк]</code>")
+
+	h.SendMessage(chatID, threadID, builder.String())
+}
+
+func containerIcon(details *dockerapi.Details) string {
+	switch {
+	case details.HealthStatus() == "unhealthy" || details.State.Restarting:
+		return "🔴"
+	case details.State.Running:
+		return "🟢"
+	default:
+		return "⚪"
+	}
+}
+
+// HandleRestartCommand перезапускает контейнер после подтверждения
+func (h *MessageHandler) HandleRestartCommand(update models.Update, args []string) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if !h.requireAdmin(message) {
+		return
+	}
+	if h.Docker == nil {
+		h.SendMessage(chatID, threadID, "❌ Docker недоступен (DOCKER_SOCKET)")
+		return
+	}
+	if len(args) != 1 {
+		h.SendMessage(chatID, threadID, "ℹ️ Использование: <code>/restart &lt;имя&gt;</code>\nСписок контейнеров: /containers")
+		return
+	}
+
+	name := args[0]
+	details, err := h.Docker.Inspect(name)
+	if err != nil {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Контейнер <b>%s</b>: %s", html.EscapeString(name), html.EscapeString(err.Error())))
+		return
+	}
+
+	escaped := html.EscapeString(name)
+	markup := keyboard([]models.InlineKeyboardButton{
+		h.callbackButton("🔄 Перезапустить", func(query *models.CallbackQuery) string {
+			if !h.isAdmin(query.From.ID) {
+				return "⛔ Только для администраторов"
+			}
+			h.goSafe(models.Update{CallbackQuery: query}, func() {
+				h.restartContainer(query.Message, name)
+			})
+			return "⏳ Перезапускаю..."
+		}),
+		h.callbackButton("❌ Отмена", func(query *models.CallbackQuery) string {
+			if !h.isAdmin(query.From.ID) {
+				return "⛔ Только для администраторов"
+			}
+			h.EditMessageText(query.Message.Chat.ID, query.Message.MessageID, "❌ Перезапуск отменен.", nil)
+			return ""
+		}),
+	})
+
+	text := fmt.Sprintf("🐳 Перезапустить контейнер <b>%s</b>?\n📊 Сейчас: %s", escaped, html.EscapeString(details.State.Status))
+	if _, err := h.SendMessageWithKeyboard(chatID, threadID, text, markup); err != nil {
+		updateLogger(update).Error("❌ SendMessageWithKeyboard", "error", err)
+	}
+}
+
+func (h *MessageHandler) restartContainer(message *models.Message, name string) {
+	chatID := message.Chat.ID
+	messageID := message.MessageID
+	escaped := html.EscapeString(name)
+
+	h.EditMessageText(chatID, messageID, fmt.Sprintf("⏳ Перезапускаю <b>%s</b>...", escaped), nil)
+	logger.Info("🔄 Docker: перезапуск контейнера", "container", name)
+
+	if err := h.Docker.Restart(name, dockerRestartTimeout); err != nil {
+		logger.Error("❌ Docker: ошибка перезапуска", "container", name, "error", err)
+		h.EditMessageText(chatID, messageID, fmt.Sprintf("❌ Ошибка перезапуска <b>%s</b>: %s", escaped, html.EscapeString(err.Error())), nil)
+		return
+	}
+
+	status := "запущен"
+	if details, err := h.Docker.Inspect(name); err == nil {
+		status = details.State.Status
+	}
+	h.EditMessageText(chatID, messageID, fmt.Sprintf("✅ Контейнер <b>%s</b> перезапущен (%s)", escaped, html.EscapeString(status)), nil)
+}
+
+// HandleContainerLogsCommand отправляет последние строки лога контейнера
+func (h *MessageHandler) HandleContainerLogsCommand(update models.Update, args []string) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if !h.requireAdmin(message) {
+		return
+	}
+	if h.Docker == nil {
+		h.SendMessage(chatID, threadID, "❌ Docker недоступен (DOCKER_SOCKET)")
+		return
+	}
+	if len(args) == 0 {
+		h.SendMessage(chatID, threadID, "ℹ️ Использование: <code>/logs &lt;имя&gt; [строк]</code>")
+		return
+	}
+
+	name := args[0]
+	lines := dockerLogsDefault
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			h.SendMessage(chatID, threadID, "❌ Количество строк должно быть положительным числом")
+			return
+		}
+		lines = min(n, dockerLogsMax)
+	}
+
+	logs, err := h.Docker.Logs(name, lines)
+	if err != nil {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка чтения логов <b>%s</b>: %s", html.EscapeString(name), html.EscapeString(err.Error())))
+		return
+	}
+	logs = strings.ToValidUTF8(logs, "�")
+	if strings.TrimSpace(logs) == "" {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("📭 Лог <b>%s</b> пуст", html.EscapeString(name)))
+		return
+	}
+
+	if utf8.RuneCountInString(logs) > dockerLogsInlineLimit {
+		fileName := fmt.Sprintf("%s-%s.log", name, time.Now().Format("20060102-150405"))
+		caption := fmt.Sprintf("📜 Лог <b>%s</b>, последние %d строк", html.EscapeString(name), lines)
+		if err := h.SendDocument(chatID, threadID, []byte(logs), fileName, caption); err != nil {
+			updateLogger(update).Error("❌ SendDocument", "error", err)
+		}
+		return
+	}
+
+	h.SendMessage(chatID, threadID, fmt.Sprintf("📜 <b>%s</b>\n<pre>%s</pre>", html.EscapeString(name), html.EscapeString(logs)))
+}
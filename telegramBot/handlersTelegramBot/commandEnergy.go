@@ -0,0 +1,248 @@
+package handlersTelegramBot
+
+import (
+	"fmt"
+	"html"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"telegramBot/config"
+	"telegramBot/energy"
+	"telegramBot/models"
+	"telegramBot/scheduler"
+	"telegramBot/yandexapi"
+)
+
+// Показания загружаются с запасом до начала периода, чтобы посчитать первое приращение
+const energyLookback = 24 * time.Hour
+
+// StartEnergy запускает сбор показаний счетчиков, отчеты и выгрузку CSV
+func (h *MessageHandler) StartEnergy() error {
+	config := h.Config().Energy
+
+	var sources []energy.Source
+	if len(config.Devices) > 0 && h.Zigbee != nil {
+		sources = append(sources, energy.ZigbeeSource(h.Zigbee, config.Devices))
+	}
+	if len(config.HAEntities) > 0 && h.HA != nil {
+		entities, err := energy.ParseHAEntities(config.HAEntities)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, energy.HASource(h.HA, entities))
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+
+	tariff, err := energyTariff(config)
+	if err != nil {
+		return err
+	}
+
+	store, err := energy.NewStore(filepath.Join(h.Config().DataDir, "energy"))
+	if err != nil {
+		return err
+	}
+	h.energyStore = store
+	h.energyTariff = tariff
+	h.EnergyMeter = energy.NewMeter(store, config.SampleInterval, sources...)
+	h.EnergyMeter.Start()
+
+	if config.ReportSchedule != "" {
+		chatID, threadID, err := h.ResolveChat(config.ReportChat)
+		if err != nil {
+			return fmt.Errorf("ENERGY_REPORT_CHAT: %v", err)
+		}
+		err = scheduler.Add("energy-report", config.ReportSchedule, func() {
+			yesterday := energy.DayStart(time.Now()).AddDate(0, 0, -1)
+			h.SendMessage(chatID, threadID, h.energyReport(yesterday, yesterday.AddDate(0, 0, 1), true))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if config.ExportSchedule != "" {
+		err := scheduler.Add("energy-export", config.ExportSchedule, func() {
+			month := energy.MonthStart(time.Now()).AddDate(0, -1, 0)
+			if remotePath, err := h.exportEnergy(month); err != nil {
+				h.NotifyAdmin(fmt.Sprintf("❌ Ошибка выгрузки расхода электроэнергии: %s", html.EscapeString(err.Error())))
+			} else {
+				logger.Info("✅ Расход электроэнергии выгружен", "month", month.Format("2006-01"), "path", remotePath)
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	logger.Info("⚡ Учет электроэнергии запущен", "sources", len(sources))
+	return nil
+}
+
+func energyTariff(config config.EnergyConfig) (energy.Tariff, error) {
+	nightStart, err := energy.ParseClock(config.NightStart)
+	if err != nil {
+		return energy.Tariff{}, fmt.Errorf("ENERGY_NIGHT_START: %v", err)
+	}
+	nightEnd, err := energy.ParseClock(config.NightEnd)
+	if err != nil {
+		return energy.Tariff{}, fmt.Errorf("ENERGY_NIGHT_END: %v", err)
+	}
+
+	return energy.Tariff{
+		Day:        config.TariffDay,
+		Night:      config.TariffNight,
+		NightStart: nightStart,
+		NightEnd:   nightEnd,
+		Currency:   config.Currency,
+	}, nil
+}
+
+// HandleEnergyCommand - /energy [today|yesterday|month|ГГГГ-ММ|ГГГГ-ММ-ДД], /energy export [ГГГГ-ММ]
+func (h *MessageHandler) HandleEnergyCommand(update models.Update, args []string) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if h.EnergyMeter == nil {
+		h.SendMessage(chatID, threadID, "❌ Учет электроэнергии не настроен (ENERGY_DEVICES / ENERGY_HA_ENTITIES)")
+		return
+	}
+
+	usage := `ℹ️ <b>Использование:</b>
+• <code>/energy [today|yesterday|month]</code> - расход за период
+• <code>/energy ГГГГ-ММ-ДД</code> или <code>/energy ГГГГ-ММ</code> - за день или месяц
+• <code>/energy export [ГГГГ-ММ]</code> - выгрузить CSV на Яндекс.Диск`
+
+	now := time.Now()
+	period := "today"
+	if len(args) > 0 {
+		period = args[0]
+	}
+
+	switch {
+	case period == "today":
+		h.SendMessage(chatID, threadID, h.energyReport(energy.DayStart(now), now, false))
+	case period == "yesterday":
+		yesterday := energy.DayStart(now).AddDate(0, 0, -1)
+		h.SendMessage(chatID, threadID, h.energyReport(yesterday, yesterday.AddDate(0, 0, 1), false))
+	case period == "month":
+		h.SendMessage(chatID, threadID, h.energyReport(energy.MonthStart(now), now, false))
+	case period == "export":
+		if !h.requireAdmin(message) {
+			return
+		}
+		month := energy.MonthStart(now)
+		if len(args) > 1 {
+			parsed, err := time.ParseInLocation("2006-01", args[1], time.Local)
+			if err != nil {
+				h.SendMessage(chatID, threadID, usage)
+				return
+			}
+			month = parsed
+		}
+		remotePath, err := h.exportEnergy(month)
+		if err != nil {
+			h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка выгрузки: %s", html.EscapeString(err.Error())))
+			return
+		}
+		h.SendMessage(chatID, threadID, fmt.Sprintf("✅ CSV сохранен: <code>%s</code>", html.EscapeString(remotePath)))
+	default:
+		if day, err := time.ParseInLocation("2006-01-02", period, time.Local); err == nil {
+			h.SendMessage(chatID, threadID, h.energyReport(day, day.AddDate(0, 0, 1), false))
+		} else if month, err := time.ParseInLocation("2006-01", period, time.Local); err == nil {
+			h.SendMessage(chatID, threadID, h.energyReport(month, month.AddDate(0, 1, 0), false))
+		} else {
+			h.SendMessage(chatID, threadID, usage)
+		}
+	}
+}
+
+// energyReport готовит отчет о расходе за период; withMonth добавляет итог с начала месяца
+func (h *MessageHandler) energyReport(from time.Time, to time.Time, withMonth bool) string {
+	readings, err := h.energyStore.Load(from.Add(-energyLookback), to)
+	if err != nil {
+		return fmt.Sprintf("❌ Ошибка чтения показаний: %s", html.EscapeString(err.Error()))
+	}
+	items := energy.Summarize(readings, from, to, h.energyTariff)
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "⚡ <b>Электроэнергия: %s</b>\n", formatEnergyPeriod(from, to))
+	fmt.Fprintln(&builder, strings.Repeat("─", 20))
+
+	if len(items) == 0 {
+		fmt.Fprintln(&builder, "📭 Нет показаний за период")
+	}
+	for _, item := range items {
+		fmt.Fprintf(&builder, "• <b>%s</b> — %s", html.EscapeString(item.Device), h.formatUsage(item))
+		if item.Power > 0 && to.After(time.Now().Add(-time.Minute)) {
+			fmt.Fprintf(&builder, " · сейчас %.0f Вт", item.Power)
+		}
+		fmt.Fprintln(&builder)
+	}
+
+	if len(items) > 1 {
+		fmt.Fprintf(&builder, "\n∑ <b>Итого:</b> %s\n", h.formatUsage(energy.Sum(items)))
+	}
+
+	if withMonth {
+		month := energy.MonthStart(from)
+		readings, err := h.energyStore.Load(month.Add(-energyLookback), to)
+		if err == nil {
+			total := energy.Sum(energy.Summarize(readings, month, to, h.energyTariff))
+			fmt.Fprintf(&builder, "📅 <b>С начала месяца:</b> %s\n", h.formatUsage(total))
+		}
+	}
+	return builder.String()
+}
+
+// formatUsage: "1.234 кВт·ч (🌙 0.400) · 8.50 ₽"
+func (h *MessageHandler) formatUsage(usage energy.Usage) string {
+	text := fmt.Sprintf("%.3f кВт·ч", usage.Total())
+	if h.energyTariff.Zoned() && usage.NightKWh > 0 {
+		text += fmt.Sprintf(" (🌙 %.3f)", usage.NightKWh)
+	}
+	if h.energyTariff.Day > 0 {
+		text += fmt.Sprintf(" · %.2f %s", usage.Cost, html.EscapeString(h.energyTariff.Currency))
+	}
+	return text
+}
+
+// exportEnergy выгружает расход за месяц по дням в CSV на Яндекс.Диск
+func (h *MessageHandler) exportEnergy(month time.Time) (string, error) {
+	from := energy.MonthStart(month)
+	to := from.AddDate(0, 1, 0)
+
+	readings, err := h.energyStore.Load(from.Add(-energyLookback), to)
+	if err != nil {
+		return "", err
+	}
+	data, err := energy.ExportCSV(energy.Daily(readings, from, to, h.energyTariff))
+	if err != nil {
+		return "", err
+	}
+
+	dir := h.Config().Energy.ExportDir
+	if err := yandexapi.EnsureDirectory(dir); err != nil {
+		return "", err
+	}
+	name := "energy-" + from.Format("2006-01") + ".csv"
+	if err := yandexapi.UploadFile(dir, name, data); err != nil {
+		return "", err
+	}
+	return dir + "/" + name, nil
+}
+
+func formatEnergyPeriod(from time.Time, to time.Time) string {
+	switch {
+	case to.Sub(from) <= 24*time.Hour && from.Equal(energy.DayStart(from)):
+		return from.Format("02.01.2006")
+	case from.Equal(energy.MonthStart(from)) && to.Equal(from.AddDate(0, 1, 0)):
+		return from.Format("01.2006")
+	default:
+		return fmt.Sprintf("%s – %s", from.Format("02.01.2006"), to.Format("02.01.2006 15:04"))
+	}
+}
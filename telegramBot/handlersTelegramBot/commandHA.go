@@ -0,0 +1,262 @@
+package handlersTelegramBot
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"telegramBot/homeassistant"
+	"telegramBot/models"
+)
+
+// Лимит Telegram - 4096 символов, оставляем запас на заголовок и подсказку
+const haStatesMaxLength = 3500
+
+// StartHomeAssistant подключает клиент Home Assistant, если задан токен
+func (h *MessageHandler) StartHomeAssistant() error {
+	if h.Config().HA.Token == "" {
+		logger.Info("ℹ️ HA_TOKEN не задан, интеграция с Home Assistant выключена")
+		return nil
+	}
+
+	favorites, err := homeassistant.ParseFavorites(h.Config().HA.Favorites)
+	if err != nil {
+		return err
+	}
+
+	client := homeassistant.New(h.Config().HA)
+	if err := client.Ping(); err != nil {
+		// Home Assistant может стартовать позже бота - клиент все равно сохраняем
+		logger.Warn("⚠️ Home Assistant недоступен", "error", err)
+	} else {
+		logger.Info("✅ Home Assistant подключен", "url", h.Config().HA.URL)
+	}
+
+	h.HA = client
+	h.haFavorites = favorites
+
+	if err := h.startCameras(); err != nil {
+		logger.Error("❌ Ошибка запуска камер", "error", err)
+	}
+	if err := h.startDailyChart(); err != nil {
+		logger.Error("❌ Ошибка планирования ежедневного графика", "error", err)
+	}
+	return h.startHAEvents()
+}
+
+// HandleHACommand - /ha states|get|call
+func (h *MessageHandler) HandleHACommand(update models.Update, args []string) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if h.HA == nil {
+		h.SendMessage(chatID, threadID, "❌ Home Assistant не настроен (HA_TOKEN)")
+		return
+	}
+
+	usage := `ℹ️ <b>Использование:</b>
+• <code>/ha states [фильтр]</code> - список сущностей
+• <code>/ha get &lt;entity_id&gt;</code> - подробности о сущности
+• <code>/ha call &lt;домен.сервис&gt; &lt;entity_id&gt; [json]</code> - вызвать сервис
+• <code>/ha fav</code> - избранные скрипты и сцены
+• <code>/ha rules</code> - правила пересылки событий`
+
+	// Список сущностей и их состояния выдают, что происходит дома - только для администраторов
+	if !h.requireAdmin(message) {
+		return
+	}
+	if len(args) == 0 {
+		h.SendMessage(chatID, threadID, usage)
+		return
+	}
+
+	switch args[0] {
+	case "states":
+		h.haStates(message, commandRest(message.Text, 2))
+	case "get":
+		if len(args) < 2 {
+			h.SendMessage(chatID, threadID, usage)
+			return
+		}
+		h.haGet(message, args[1])
+	case "call":
+		if len(args) < 3 {
+			h.SendMessage(chatID, threadID, usage)
+			return
+		}
+		h.haCall(message, args[1], args[2], commandRest(message.Text, 4))
+	case "fav", "favorites":
+		h.haFavoritesMenu(message)
+	case "rules":
+		h.haRulesList(message)
+	default:
+		h.SendMessage(chatID, threadID, usage)
+	}
+}
+
+func (h *MessageHandler) haStates(message *models.Message, filter string) {
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	states, err := h.HA.States()
+	if err != nil {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка получения состояний: %s", html.EscapeString(err.Error())))
+		return
+	}
+
+	var matched []homeassistant.State
+	for _, state := range states {
+		if state.Matches(filter) {
+			matched = append(matched, state)
+		}
+	}
+	if len(matched) == 0 {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("📭 Нет сущностей по фильтру <code>%s</code>", html.EscapeString(filter)))
+		return
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].EntityID < matched[j].EntityID })
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "🏠 <b>Сущности Home Assistant</b> (%d):\n", len(matched))
+	shown := 0
+	for _, state := range matched {
+		line := fmt.Sprintf("• <code>%s</code> — <b>%s</b>\n", html.EscapeString(state.EntityID), html.EscapeString(state.Value()))
+		if builder.Len()+len(line) > haStatesMaxLength {
+			break
+		}
+		builder.WriteString(line)
+		shown++
+	}
+	if shown < len(matched) {
+		fmt.Fprintf(&builder, "\n… и еще %d, уточните фильтр: <code>/ha states &lt;фильтр&gt;</code>", len(matched)-shown)
+	}
+
+	h.SendMessage(chatID, threadID, builder.String())
+}
+
+func (h *MessageHandler) haGet(message *models.Message, entityID string) {
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	state, err := h.HA.State(entityID)
+	if homeassistant.IsNotFound(err) {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Сущность <code>%s</code> не найдена", html.EscapeString(entityID)))
+		return
+	}
+	if err != nil {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка получения состояния: %s", html.EscapeString(err.Error())))
+		return
+	}
+
+	h.SendMessage(chatID, threadID, formatHAState(*state))
+}
+
+// formatHAState готовит карточку сущности со всеми атрибутами
+func formatHAState(state homeassistant.State) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "🏠 <b>%s</b>\n", html.EscapeString(state.Name()))
+	fmt.Fprintf(&builder, "🆔 <code>%s</code>\n", html.EscapeString(state.EntityID))
+	fmt.Fprintf(&builder, "📊 Состояние: <b>%s</b>\n", html.EscapeString(state.Value()))
+	fmt.Fprintf(&builder, "🕒 Изменено: %s\n", state.LastChanged.Local().Format("02.01.2006 15:04:05"))
+
+	keys := make([]string, 0, len(state.Attributes))
+	for key := range state.Attributes {
+		if key != "friendly_name" && key != "unit_of_measurement" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	if len(keys) > 0 {
+		fmt.Fprintln(&builder, "\n📋 <b>Атрибуты:</b>")
+		for _, key := range keys {
+			value, _ := json.Marshal(state.Attributes[key])
+			fmt.Fprintf(&builder, "• %s: <code>%s</code>\n", html.EscapeString(key), html.EscapeString(string(value)))
+		}
+	}
+	return builder.String()
+}
+
+func (h *MessageHandler) haCall(message *models.Message, service string, entityID string, rawData string) {
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	domain, name, ok := strings.Cut(service, ".")
+	if !ok || domain == "" || name == "" {
+		h.SendMessage(chatID, threadID, "❌ Сервис указывается как <code>домен.сервис</code>, например <code>light.turn_on</code>")
+		return
+	}
+
+	data := map[string]any{}
+	if rawData != "" {
+		if err := json.Unmarshal([]byte(rawData), &data); err != nil {
+			h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Некорректный JSON: %s", html.EscapeString(err.Error())))
+			return
+		}
+	}
+	data["entity_id"] = entityID
+
+	logger.Info("🏠 Вызов сервиса", "service", service, "entity_id", entityID, "user_id", message.From.ID)
+	changed, err := h.HA.CallService(domain, name, data)
+	if err != nil {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка вызова <code>%s</code>: %s", html.EscapeString(service), html.EscapeString(err.Error())))
+		return
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "✅ Сервис <code>%s</code> вызван для <code>%s</code>", html.EscapeString(service), html.EscapeString(entityID))
+	for _, state := range changed {
+		fmt.Fprintf(&builder, "\n• %s — <b>%s</b>", html.EscapeString(state.Name()), html.EscapeString(state.Value()))
+	}
+	h.SendMessage(chatID, threadID, builder.String())
+}
+
+// haFavoritesMenu отправляет клавиатуру с избранными скриптами и сценами
+func (h *MessageHandler) haFavoritesMenu(message *models.Message) {
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if len(h.haFavorites) == 0 {
+		h.SendMessage(chatID, threadID, "📭 Избранное пусто. Задайте список в HA_FAVORITES: <code>Название=script.id,...</code>")
+		return
+	}
+
+	var rows [][]models.InlineKeyboardButton
+	var row []models.InlineKeyboardButton
+	for _, favorite := range h.haFavorites {
+		row = append(row, h.favoriteButton(favorite))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	if _, err := h.SendMessageWithKeyboard(chatID, threadID, "⭐ <b>Избранное Home Assistant</b>", keyboard(rows...)); err != nil {
+		logger.Error("❌ SendMessageWithKeyboard", "error", err)
+	}
+}
+
+func (h *MessageHandler) favoriteButton(favorite homeassistant.Favorite) models.InlineKeyboardButton {
+	icon := "▶️"
+	if homeassistant.Domain(favorite.EntityID) == "scene" {
+		icon = "🎬"
+	}
+
+	return h.callbackButton(icon+" "+favorite.Name, func(query *models.CallbackQuery) string {
+		if !h.isAdmin(query.From.ID) {
+			return "⛔ Только для администраторов"
+		}
+		logger.Info("🏠 Запуск избранного", "entity_id", favorite.EntityID, "user", query.From.FirstName)
+		if err := h.HA.Run(favorite); err != nil {
+			logger.Error("❌ Ошибка запуска избранного", "entity_id", favorite.EntityID, "error", err)
+			return "❌ Ошибка: " + err.Error()
+		}
+		return "✅ " + favorite.Name
+	})
+}
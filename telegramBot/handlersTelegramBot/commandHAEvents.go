@@ -0,0 +1,153 @@
+package handlersTelegramBot
+
+import (
+	"fmt"
+	"html"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"telegramBot/homeassistant"
+	"telegramBot/models"
+)
+
+// startHAEvents загружает правила пересылки и подписывается на шину событий Home Assistant
+func (h *MessageHandler) startHAEvents() error {
+	rules, err := homeassistant.LoadRules(filepath.Join(h.Config().DataDir, "ha_rules.json"))
+	if err != nil {
+		return err
+	}
+	h.haRules = rules
+
+	eventTypes := rules.EventTypes()
+	cameraTriggers := h.Cameras != nil && h.Cameras.HasTriggers()
+	if cameraTriggers && !slices.Contains(eventTypes, "state_changed") {
+		eventTypes = append(eventTypes, "state_changed")
+	}
+	if len(eventTypes) == 0 {
+		logger.Info("ℹ️ Правила пересылки событий Home Assistant не заданы")
+		return nil
+	}
+
+	for _, rule := range rules.List() {
+		if _, _, err := h.ResolveChat(rule.Chat); err != nil {
+			return fmt.Errorf("правило %s: %v", rule.Name, err)
+		}
+	}
+
+	h.haEvents = h.HA.Events(eventTypes)
+	h.haEvents.OnEvent(h.handleHAEvent)
+	if cameraTriggers {
+		h.haEvents.OnEvent(h.handleCameraEvent)
+	}
+	h.haEvents.Start()
+
+	logger.Info("✅ Загружены правила пересылки событий Home Assistant", "count", len(rules.List()))
+	return nil
+}
+
+// handleHAEvent отправляет уведомления по сработавшим правилам
+func (h *MessageHandler) handleHAEvent(event homeassistant.Event) {
+	for _, notification := range h.haRules.Match(event) {
+		chatID, threadID, err := h.ResolveChat(notification.Rule.Chat)
+		if err != nil {
+			logger.Error("❌ Ошибка правила", "rule", notification.Rule.Name, "error", err)
+			continue
+		}
+
+		if len(notification.Rule.Actions) == 0 {
+			if err := h.SendMessage(chatID, threadID, notification.Text); err != nil {
+				logger.Error("❌ Ошибка отправки уведомления Home Assistant", "rule", notification.Rule.Name, "error", err)
+			}
+			continue
+		}
+
+		var buttons []models.InlineKeyboardButton
+		for _, action := range notification.Rule.Actions {
+			buttons = append(buttons, h.haActionButton(notification, action))
+		}
+		if _, err := h.SendMessageWithKeyboard(chatID, threadID, notification.Text, keyboard(buttons)); err != nil {
+			logger.Error("❌ Ошибка отправки уведомления Home Assistant", "rule", notification.Rule.Name, "error", err)
+		}
+	}
+}
+
+// haActionButton создает кнопку действия под уведомлением
+func (h *MessageHandler) haActionButton(notification homeassistant.Notification, action homeassistant.RuleAction) models.InlineKeyboardButton {
+	rule := notification.Rule
+	entityID := notification.EntityID
+
+	return h.callbackButton(action.Text, func(query *models.CallbackQuery) string {
+		if rule.AdminOnly && !h.isAdmin(query.From.ID) {
+			return "⛔ Только для администраторов"
+		}
+
+		who := html.EscapeString(query.From.FirstName)
+		if action.Snooze > 0 {
+			duration := time.Duration(action.Snooze)
+			h.haRules.Snooze(rule, entityID, duration)
+			logger.Info("🔕 Правило отложено", "rule", rule.Name, "entity_id", entityID, "duration", duration, "user", query.From.FirstName)
+			h.appendToMessage(query.Message, fmt.Sprintf("🔕 %s отключил(а) уведомления на %s", who, duration))
+			return "🔕 Уведомления отложены"
+		}
+
+		domain, service, ok := strings.Cut(action.Service, ".")
+		if !ok {
+			return "❌ Некорректный сервис: " + action.Service
+		}
+
+		data := map[string]any{}
+		for key, value := range action.Data {
+			data[key] = value
+		}
+		if _, ok := data["entity_id"]; !ok && entityID != "" {
+			data["entity_id"] = entityID
+		}
+
+		logger.Info("🏠 Кнопка правила", "rule", rule.Name, "service", action.Service, "user", query.From.FirstName)
+		if _, err := h.HA.CallService(domain, service, data); err != nil {
+			logger.Error("❌ Ошибка вызова сервиса", "service", action.Service, "error", err)
+			return "❌ Ошибка: " + err.Error()
+		}
+		h.appendToMessage(query.Message, fmt.Sprintf("✅ %s: %s", who, html.EscapeString(action.Text)))
+		return "✅ Готово"
+	})
+}
+
+// appendToMessage дописывает строку к уведомлению и убирает кнопки
+func (h *MessageHandler) appendToMessage(message *models.Message, line string) {
+	if message == nil {
+		return
+	}
+	text := html.EscapeString(message.Text) + "\n\n" + line
+	if err := h.EditMessageText(message.Chat.ID, message.MessageID, text, nil); err != nil {
+		logger.Error("❌ EditMessageText", "error", err)
+	}
+}
+
+// haRulesList выводит загруженные правила пересылки событий
+func (h *MessageHandler) haRulesList(message *models.Message) {
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if h.haRules == nil || len(h.haRules.List()) == 0 {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("📭 Правил нет. Добавьте их в <code>%s</code>",
+			html.EscapeString(filepath.Join(h.Config().DataDir, "ha_rules.json"))))
+		return
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "📜 <b>Правила пересылки событий</b> (%d):\n", len(h.haRules.List()))
+	for _, rule := range h.haRules.List() {
+		fmt.Fprintf(&builder, "\n• <b>%s</b>: %s", html.EscapeString(rule.Name), html.EscapeString(rule.Event))
+		if rule.Entity != "" {
+			fmt.Fprintf(&builder, " <code>%s</code>", html.EscapeString(rule.Entity))
+		}
+		if rule.From != "" || rule.To != "" {
+			fmt.Fprintf(&builder, " %s→%s", html.EscapeString(rule.From), html.EscapeString(rule.To))
+		}
+		fmt.Fprintf(&builder, " ➡️ %s", html.EscapeString(rule.Chat))
+	}
+	h.SendMessage(chatID, threadID, builder.String())
+}
@@ -0,0 +1,179 @@
+package handlersTelegramBot
+
+import (
+	"fmt"
+	"html"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"telegramBot/lan"
+	"telegramBot/models"
+)
+
+// Сколько ждать, пока разбуженное устройство появится в сети
+const wakeWaitTimeout = 3 * time.Minute
+
+// StartLAN загружает устройства локальной сети и запускает отслеживание присутствия
+func (h *MessageHandler) StartLAN() error {
+	devices, err := lan.LoadDevices(h.lanDevicesPath())
+	if err != nil {
+		return err
+	}
+	if len(devices) == 0 {
+		return nil
+	}
+	h.lanDevices = devices
+
+	config := h.Config().LAN
+	if config.PresenceInterval > 0 {
+		chatID, threadID, err := h.ResolveChat(config.PresenceChat)
+		if err != nil {
+			return fmt.Errorf("LAN_PRESENCE_CHAT: %v", err)
+		}
+		monitor := lan.NewMonitor(devices, h.arpPath(), config.ProbeTimeout, config.AwayAfter, func(text string) {
+			h.SendMessage(chatID, threadID, text)
+		})
+		if monitor.Watched() > 0 {
+			monitor.Start(config.PresenceInterval)
+		}
+	}
+
+	logger.Info("🖧 Устройства локальной сети загружены", "count", len(devices))
+	return nil
+}
+
+func (h *MessageHandler) lanDevicesPath() string {
+	return filepath.Join(h.Config().DataDir, "lan_devices.json")
+}
+
+func (h *MessageHandler) arpPath() string {
+	return filepath.Join(h.Config().Host.ProcPath, "net", "arp")
+}
+
+// HandleWakeCommand будит устройство magic packet'ом
+func (h *MessageHandler) HandleWakeCommand(update models.Update, args []string) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	var wakeable []*lan.Device
+	for _, device := range h.lanDevices {
+		if device.Wake && (!device.AdminOnly || h.isAdmin(message.From.ID)) {
+			wakeable = append(wakeable, device)
+		}
+	}
+	if len(wakeable) == 0 {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Нет устройств для пробуждения. Добавьте их в <code>%s</code> с <code>\"wake\": true</code>",
+			html.EscapeString(h.lanDevicesPath())))
+		return
+	}
+
+	if len(args) == 0 {
+		var buttons []models.InlineKeyboardButton
+		for _, device := range wakeable {
+			buttons = append(buttons, h.callbackButton("⏰ "+device.Name, func(query *models.CallbackQuery) string {
+				if device.AdminOnly && !h.isAdmin(query.From.ID) {
+					return "⛔ Только для администраторов"
+				}
+				h.goSafe(models.Update{CallbackQuery: query}, func() {
+					h.wakeDevice(query.Message.Chat.ID, query.Message.MessageThreadID, device, query.From.FirstName)
+				})
+				return "⏰ Бужу..."
+			}))
+		}
+		if _, err := h.SendMessageWithKeyboard(chatID, threadID, "⏰ <b>Какое устройство разбудить?</b>", keyboard(buttons)); err != nil {
+			updateLogger(update).Error("❌ SendMessageWithKeyboard", "error", err)
+		}
+		return
+	}
+
+	device, ok := lan.Find(wakeable, args[0])
+	if !ok {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Устройство <b>%s</b> не найдено. Список: /wake", html.EscapeString(args[0])))
+		return
+	}
+	h.goSafe(update, func() {
+		h.wakeDevice(chatID, threadID, device, message.From.FirstName)
+	})
+}
+
+// wakeDevice отправляет magic packet и, если у устройства есть проверки, ждет его появления в сети
+func (h *MessageHandler) wakeDevice(chatID int64, threadID int, device *lan.Device, who string) {
+	escaped := html.EscapeString(device.Name)
+
+	if err := lan.Wake(device, h.Config().LAN.Broadcast); err != nil {
+		logger.Error("❌ Wake-on-LAN", "device", device.Name, "error", err)
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Не удалось разбудить <b>%s</b>: %s", escaped, html.EscapeString(err.Error())))
+		return
+	}
+	logger.Info("⏰ Wake-on-LAN", "device", device.Name, "mac", device.MAC, "user", who)
+
+	if device.IP == "" || len(device.Probes) == 0 {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("⏰ Magic packet отправлен на <b>%s</b>", escaped))
+		return
+	}
+
+	messageID, err := h.sendMessage(chatID, threadID, fmt.Sprintf("⏰ Magic packet отправлен на <b>%s</b>, жду появления в сети...", escaped), nil)
+	if err != nil {
+		logger.Error("❌ sendMessage", "error", err)
+		return
+	}
+
+	started := time.Now()
+	for time.Since(started) < wakeWaitTimeout {
+		statuses, err := lan.Check([]*lan.Device{device}, h.arpPath(), h.Config().LAN.ProbeTimeout)
+		if err == nil && statuses[0].Present {
+			h.EditMessageText(chatID, messageID, fmt.Sprintf("🟢 <b>%s</b> в сети через %s", escaped, time.Since(started).Round(time.Second)), nil)
+			return
+		}
+		time.Sleep(5 * time.Second)
+	}
+	h.EditMessageText(chatID, messageID, fmt.Sprintf("⚠️ Magic packet отправлен, но <b>%s</b> не появился в сети за %s", escaped, wakeWaitTimeout), nil)
+}
+
+// HandleWhoIsHomeCommand показывает, какие известные устройства сейчас в сети.
+// Это выдает, есть ли кто-то дома, поэтому только для администраторов
+func (h *MessageHandler) HandleWhoIsHomeCommand(update models.Update) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if !h.requireAdmin(message) {
+		return
+	}
+
+	if len(h.lanDevices) == 0 {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Устройства не настроены. Добавьте их в <code>%s</code>",
+			html.EscapeString(h.lanDevicesPath())))
+		return
+	}
+
+	statuses, err := lan.Check(h.lanDevices, h.arpPath(), h.Config().LAN.ProbeTimeout)
+	if err != nil {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка чтения таблицы соседей: %s", html.EscapeString(err.Error())))
+		return
+	}
+
+	var online, offline []string
+	for _, status := range statuses {
+		name := html.EscapeString(status.Device.Name)
+		if status.Present {
+			line := fmt.Sprintf("🟢 <b>%s</b>", name)
+			if status.IP != "" {
+				line += fmt.Sprintf(" · <code>%s</code>", html.EscapeString(status.IP))
+			}
+			online = append(online, line+" · "+html.EscapeString(status.Via))
+		} else {
+			offline = append(offline, fmt.Sprintf("⚪ %s", name))
+		}
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "🏠 <b>Кто дома</b> (%d из %d в сети):\n", len(online), len(statuses))
+	fmt.Fprintln(&builder, strings.Repeat("─", 20))
+	for _, line := range append(online, offline...) {
+		fmt.Fprintln(&builder, line)
+	}
+	h.SendMessage(chatID, threadID, builder.String())
+}
@@ -0,0 +1,102 @@
+package handlersTelegramBot
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"telegramBot/models"
+	"telegramBot/yandexapi"
+	"telegramBot/yandexapi/method"
+)
+
+var linkPattern = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+// HandleLinkMessage предлагает сохранить на Яндекс.Диск ссылку из сообщения
+func (h *MessageHandler) HandleLinkMessage(update models.Update) {
+	message := update.Message
+
+	link := linkPattern.FindString(message.Text)
+	if link == "" {
+		return
+	}
+
+	isPublic := yandexapi.IsPublicLink(link)
+	escapedLink := html.EscapeString(link)
+
+	var text string
+	if isPublic {
+		text = fmt.Sprintf("🔗 <b>Публичная ссылка Яндекс.Диска</b>\n<code>%s</code>\n\nСохранить на мой диск?", escapedLink)
+	} else {
+		text = fmt.Sprintf("🌐 <b>Ссылка на файл</b>\n<code>%s</code>\n\nЗагрузить на Яндекс.Диск?", escapedLink)
+	}
+
+	defaultDir := h.Config().DownloadsDir
+	markup := keyboard(
+		[]models.InlineKeyboardButton{
+			h.callbackButton("💾 В "+defaultDir, func(query *models.CallbackQuery) string {
+				h.goSafe(models.Update{CallbackQuery: query}, func() {
+					h.saveLink(query.Message, link, isPublic, defaultDir)
+				})
+				return "⏳ Сохраняю..."
+			}),
+			h.callbackButton("📁 Другая папка", func(query *models.CallbackQuery) string {
+				h.askLinkFolder(query.Message, link, isPublic)
+				return ""
+			}),
+		},
+		[]models.InlineKeyboardButton{
+			h.callbackButton("❌ Отмена", func(query *models.CallbackQuery) string {
+				h.EditMessageText(query.Message.Chat.ID, query.Message.MessageID, "❌ Сохранение отменено.", nil)
+				return ""
+			}),
+		},
+	)
+
+	if _, err := h.SendMessageWithKeyboard(message.Chat.ID, message.MessageThreadID, text, markup); err != nil {
+		updateLogger(update).Error("❌ SendMessageWithKeyboard", "error", err)
+	}
+}
+
+// askLinkFolder запрашивает папку назначения текстом
+func (h *MessageHandler) askLinkFolder(message *models.Message, link string, isPublic bool) {
+	chatID := message.Chat.ID
+
+	h.EditMessageText(chatID, message.MessageID, "📁 Введите папку для сохранения (например, /Documents):", nil)
+	step := func(folder string) (string, InputHandler, error) {
+		h.goSafe(models.Update{Message: message}, func() {
+			h.saveLink(message, link, isPublic, strings.TrimSpace(folder))
+		})
+		return "", nil, nil
+	}
+	h.states.Store(chatID, &UserState{handler: step})
+}
+
+// saveLink сохраняет ссылку в папку и обновляет сообщение с кнопками результатом
+func (h *MessageHandler) saveLink(message *models.Message, link string, isPublic bool, folder string) {
+	chatID := message.Chat.ID
+	messageID := message.MessageID
+
+	h.EditMessageText(chatID, messageID, fmt.Sprintf("⏳ Сохраняю в <code>%s</code>...\n<code>%s</code>", html.EscapeString(folder), html.EscapeString(link)), nil)
+
+	var resource *method.Resource
+	var err error
+	if isPublic {
+		resource, err = yandexapi.SavePublicResource(link, folder)
+	} else {
+		resource, err = yandexapi.UploadFromURL(link, folder)
+	}
+	if err != nil {
+		logger.Error("❌ saveLink", "link", link, "error", err)
+		h.EditMessageText(chatID, messageID, fmt.Sprintf("❌ Не удалось сохранить <code>%s</code>: %v", html.EscapeString(link), html.EscapeString(err.Error())), nil)
+		return
+	}
+
+	h.EditMessageText(chatID, messageID, fmt.Sprintf(`✅ <b>Сохранено на Яндекс.Диск</b>
+📄 Путь: <code>%s</code>
+💾 Размер: <b>%s</b>`,
+		strings.TrimPrefix(resource.Path, "disk:"),
+		yandexapi.FormatBytes(resource.Size),
+	), nil)
+}
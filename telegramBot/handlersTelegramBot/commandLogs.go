@@ -0,0 +1,92 @@
+package handlersTelegramBot
+
+import (
+	"fmt"
+	"html"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"telegramBot/logging"
+	"telegramBot/models"
+)
+
+const botLogsDefault = 30
+
+// HandleLogsCommand - /logs [уровень] [строк] показывает лог бота, /logs <имя> [строк] -
+// лог контейнера. Если первый аргумент - уровень или число, речь о логе бота
+func (h *MessageHandler) HandleLogsCommand(update models.Update, args []string) {
+	if len(args) > 0 {
+		if _, ok := parseLogLevel(args[0]); !ok {
+			if _, err := strconv.Atoi(args[0]); err != nil {
+				h.HandleContainerLogsCommand(update, args)
+				return
+			}
+		}
+	}
+	h.HandleBotLogsCommand(update, args)
+}
+
+// HandleBotLogsCommand отправляет последние записи лога бота из памяти
+func (h *MessageHandler) HandleBotLogsCommand(update models.Update, args []string) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if !h.requireAdmin(message) {
+		return
+	}
+
+	usage := "ℹ️ Использование: <code>/logs [debug|info|warn|error] [строк]</code> - лог бота, <code>/logs &lt;контейнер&gt; [строк]</code> - лог контейнера"
+	level := slog.LevelDebug
+	count := botLogsDefault
+	if len(args) > 0 {
+		if parsed, ok := parseLogLevel(args[0]); ok {
+			level = parsed
+			args = args[1:]
+		}
+	}
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			h.SendMessage(chatID, threadID, usage)
+			return
+		}
+		count = n
+	}
+
+	entries := logging.Recent(level, count)
+	levelName := strings.ToLower(level.String())
+	if len(entries) == 0 {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("📭 В логе нет записей уровня <b>%s</b> и выше", levelName))
+		return
+	}
+
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = entry.String()
+	}
+	text := strings.Join(lines, "\n")
+
+	if utf8.RuneCountInString(text) > dockerLogsInlineLimit {
+		fileName := fmt.Sprintf("bot-%s.log", time.Now().Format("20060102-150405"))
+		caption := fmt.Sprintf("📜 Лог бота, последние %d записей уровня <b>%s</b> и выше", len(entries), levelName)
+		if err := h.SendDocument(chatID, threadID, []byte(text), fileName, caption); err != nil {
+			updateLogger(update).Error("❌ SendDocument", "error", err)
+		}
+		return
+	}
+
+	h.SendMessage(chatID, threadID, fmt.Sprintf("📜 <b>Лог бота</b> (%s+)\n<pre>%s</pre>", levelName, html.EscapeString(text)))
+}
+
+// parseLogLevel разбирает уровень лога: debug, info, warn, error
+func parseLogLevel(value string) (slog.Level, bool) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return 0, false
+	}
+	return level, true
+}
@@ -0,0 +1,258 @@
+package handlersTelegramBot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"telegramBot/models"
+	"telegramBot/mqttclient"
+)
+
+const maxMQTTPayloadLength = 3000
+
+// StartMQTT подключается к брокеру и восстанавливает маршруты подписок в чаты
+func (h *MessageHandler) StartMQTT() error {
+	if h.Config().MQTT.Broker == "" {
+		logger.Info("💤 MQTT выключен (MQTT_BROKER пуст)")
+		return nil
+	}
+
+	client, err := mqttclient.New(h.Config().MQTT)
+	if err != nil {
+		return err
+	}
+	h.MQTT = client
+
+	routes, err := mqttclient.LoadRoutes(filepath.Join(h.Config().DataDir, "mqtt_routes.json"))
+	if err != nil {
+		return err
+	}
+	h.mqttRoutes = routes
+
+	for _, route := range routes.List() {
+		if err := h.subscribeRoute(route); err != nil {
+			logger.Error("❌ MQTT: ошибка подписки", "topic", route.Topic, "error", err)
+		}
+	}
+
+	client.Connect()
+	return nil
+}
+
+// HandleMQTTCommand - /mqtt pub|sub|unsub|list
+func (h *MessageHandler) HandleMQTTCommand(update models.Update, args []string) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if !h.requireAdmin(message) {
+		return
+	}
+
+	if h.MQTT == nil {
+		h.SendMessage(chatID, threadID, "❌ MQTT не настроен (MQTT_BROKER)")
+		return
+	}
+
+	usage := `ℹ️ <b>Использование:</b>
+• <code>/mqtt pub &lt;топик&gt; &lt;сообщение&gt;</code> - опубликовать
+• <code>/mqtt sub &lt;топик&gt; [чат]</code> - пересылать сообщения в чат
+• <code>/mqtt unsub &lt;топик&gt; [чат]</code> - отменить пересылку
+• <code>/mqtt list</code> - активные подписки
+Чат: <code>chat_id[:thread_id]</code> или псевдоним из CHAT_ALIASES`
+
+	if len(args) == 0 {
+		h.SendMessage(chatID, threadID, usage)
+		return
+	}
+
+	switch args[0] {
+	case "pub":
+		if len(args) < 3 {
+			h.SendMessage(chatID, threadID, usage)
+			return
+		}
+		topic := args[1]
+		payload := commandRest(message.Text, 3)
+
+		if err := h.MQTT.Publish(topic, []byte(payload), false); err != nil {
+			h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка публикации: %s", html.EscapeString(err.Error())))
+			return
+		}
+		h.SendMessage(chatID, threadID, fmt.Sprintf("✅ Опубликовано в <code>%s</code>", html.EscapeString(topic)))
+
+	case "sub", "unsub":
+		if len(args) < 2 {
+			h.SendMessage(chatID, threadID, usage)
+			return
+		}
+
+		route := mqttclient.Route{Topic: args[1], ChatID: chatID, ThreadID: threadID}
+		if len(args) > 2 {
+			targetChat, targetThread, err := h.ResolveChat(args[2])
+			if err != nil {
+				h.SendMessage(chatID, threadID, fmt.Sprintf("❌ %s", html.EscapeString(err.Error())))
+				return
+			}
+			route.ChatID, route.ThreadID = targetChat, targetThread
+		}
+
+		if args[0] == "sub" {
+			h.addMQTTRoute(message, route)
+		} else {
+			h.removeMQTTRoute(message, route)
+		}
+
+	case "list":
+		h.listMQTTRoutes(message)
+
+	default:
+		h.SendMessage(chatID, threadID, usage)
+	}
+}
+
+func (h *MessageHandler) addMQTTRoute(message *models.Message, route mqttclient.Route) {
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	added, err := h.mqttRoutes.Add(route)
+	if err != nil {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка сохранения подписки: %s", html.EscapeString(err.Error())))
+		return
+	}
+	if !added {
+		h.SendMessage(chatID, threadID, "ℹ️ Такая подписка уже есть")
+		return
+	}
+
+	if err := h.subscribeRoute(route); err != nil {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка подписки: %s", html.EscapeString(err.Error())))
+		return
+	}
+	h.SendMessage(chatID, threadID, fmt.Sprintf("✅ Сообщения из <code>%s</code> будут приходить в %s",
+		html.EscapeString(route.Topic), formatChatTarget(route.ChatID, route.ThreadID)))
+}
+
+func (h *MessageHandler) removeMQTTRoute(message *models.Message, route mqttclient.Route) {
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	removed, err := h.mqttRoutes.Remove(route)
+	if err != nil {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка сохранения подписок: %s", html.EscapeString(err.Error())))
+		return
+	}
+	if !removed {
+		h.SendMessage(chatID, threadID, "ℹ️ Такой подписки нет, см. <code>/mqtt list</code>")
+		return
+	}
+
+	if cancel, ok := h.mqttCancels.LoadAndDelete(route); ok {
+		cancel.(func())()
+	}
+	h.SendMessage(chatID, threadID, fmt.Sprintf("✅ Подписка на <code>%s</code> отменена", html.EscapeString(route.Topic)))
+}
+
+func (h *MessageHandler) listMQTTRoutes(message *models.Message) {
+	routes := h.mqttRoutes.List()
+
+	status := "🟢 подключено"
+	if !h.MQTT.IsConnected() {
+		status = "🔴 нет соединения"
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "📡 <b>MQTT</b>: %s\n", status)
+	if len(routes) == 0 {
+		fmt.Fprint(&builder, "📭 Подписок нет")
+	}
+	for _, route := range routes {
+		fmt.Fprintf(&builder, "• <code>%s</code> → %s\n", html.EscapeString(route.Topic), formatChatTarget(route.ChatID, route.ThreadID))
+	}
+
+	h.SendMessage(message.Chat.ID, message.MessageThreadID, builder.String())
+}
+
+// subscribeRoute подписывается на топик маршрута и пересылает сообщения в его чат
+func (h *MessageHandler) subscribeRoute(route mqttclient.Route) error {
+	cancel, err := h.MQTT.Subscribe(route.Topic, func(message mqttclient.Message) {
+		// Сохраненные (retained) сообщения брокер присылает при каждом переподключении -
+		// в чат пересылаем только новые
+		if message.Retained {
+			return
+		}
+		if err := h.SendMessage(route.ChatID, route.ThreadID, formatMQTTMessage(message)); err != nil {
+			logger.Error("❌ MQTT: ошибка пересылки", "topic", message.Topic, "chat_id", route.ChatID, "error", err)
+		}
+	})
+	if cancel != nil {
+		h.mqttCancels.Store(route, cancel)
+	}
+	return err
+}
+
+// formatMQTTMessage оформляет сообщение: JSON форматируется с отступами, длинный текст обрезается
+func formatMQTTMessage(message mqttclient.Message) string {
+	payload := string(message.Payload)
+
+	var indented bytes.Buffer
+	if json.Indent(&indented, message.Payload, "", "  ") == nil {
+		payload = indented.String()
+	}
+
+	if len(payload) > maxMQTTPayloadLength {
+		cut := maxMQTTPayloadLength
+		for cut > 0 && !utf8.RuneStart(payload[cut]) {
+			cut--
+		}
+		payload = payload[:cut] + "\n…"
+	}
+
+	return fmt.Sprintf("📡 <b>%s</b>\n<pre>%s</pre>", html.EscapeString(message.Topic), html.EscapeString(payload))
+}
+
+// ResolveChat понимает псевдонимы из CHAT_ALIASES ("admin" - чат администраторов)
+// и явное указание chat_id[:thread_id]
+func (h *MessageHandler) ResolveChat(target string) (int64, int, error) {
+	for _, alias := range h.Config().ChatAliases {
+		name, value, ok := strings.Cut(alias, "=")
+		if ok && strings.TrimSpace(name) == target {
+			return parseChatTarget(strings.TrimSpace(value))
+		}
+	}
+	if target == "admin" && h.Config().AdminChatID != 0 {
+		return h.Config().AdminChatID, h.Config().AdminThreadID, nil
+	}
+	return parseChatTarget(target)
+}
+
+// parseChatTarget разбирает адрес вида chat_id или chat_id:thread_id
+func parseChatTarget(target string) (int64, int, error) {
+	chatPart, threadPart, hasThread := strings.Cut(target, ":")
+
+	chatID, err := strconv.ParseInt(chatPart, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("неверный ID чата: %s", chatPart)
+	}
+
+	threadID := 0
+	if hasThread {
+		if threadID, err = strconv.Atoi(threadPart); err != nil {
+			return 0, 0, fmt.Errorf("неверный ID топика: %s", threadPart)
+		}
+	}
+	return chatID, threadID, nil
+}
+
+func formatChatTarget(chatID int64, threadID int) string {
+	if threadID != 0 {
+		return fmt.Sprintf("чат <code>%d</code>, топик <code>%d</code>", chatID, threadID)
+	}
+	return fmt.Sprintf("чат <code>%d</code>", chatID)
+}
@@ -0,0 +1,263 @@
+package handlersTelegramBot
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"telegramBot/models"
+	"telegramBot/zigbee"
+)
+
+const (
+	defaultPairMinutes = 5
+	maxPairMinutes     = 60
+	// zigbee2mqtt принимает не больше 254 секунд, более длинное окно продлеваем повторными запросами
+	permitJoinChunk   = 240 * time.Second
+	countdownInterval = 15 * time.Second
+)
+
+// HandlePairCommand включает режим сопряжения: /pair [минуты] или /pair off
+func (h *MessageHandler) HandlePairCommand(update models.Update, args []string) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if !h.requireAdmin(message) {
+		return
+	}
+	if h.Zigbee == nil {
+		h.SendMessage(chatID, threadID, "❌ Zigbee недоступен: MQTT не настроен")
+		return
+	}
+
+	minutes := defaultPairMinutes
+	if len(args) > 0 {
+		if args[0] == "off" || args[0] == "0" {
+			h.stopPairing()
+			if err := h.Zigbee.PermitJoin(0); err != nil {
+				h.SendMessage(chatID, threadID, fmt.Sprintf("❌ %s", html.EscapeString(err.Error())))
+				return
+			}
+			h.SendMessage(chatID, threadID, "🔒 Режим сопряжения выключен")
+			return
+		}
+
+		parsed, err := strconv.Atoi(args[0])
+		if err != nil || parsed < 1 || parsed > maxPairMinutes {
+			h.SendMessage(chatID, threadID, fmt.Sprintf("ℹ️ Использование: <code>/pair [1-%d]</code> или <code>/pair off</code>", maxPairMinutes))
+			return
+		}
+		minutes = parsed
+	}
+
+	if err := h.Zigbee.PermitJoin(int(min(permitJoinChunk, time.Duration(minutes)*time.Minute).Seconds())); err != nil {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Не удалось включить сопряжение: %s", html.EscapeString(err.Error())))
+		return
+	}
+
+	deadline := time.Now().Add(time.Duration(minutes) * time.Minute)
+	stopButton := keyboard([]models.InlineKeyboardButton{
+		h.callbackButton("⏹ Остановить", func(query *models.CallbackQuery) string {
+			if !h.isAdmin(query.From.ID) {
+				return "⛔ Только для администраторов"
+			}
+			h.stopPairing()
+			if err := h.Zigbee.PermitJoin(0); err != nil {
+				return "❌ " + err.Error()
+			}
+			return "🔒 Сопряжение выключено"
+		}),
+	})
+
+	messageID, err := h.SendMessageWithKeyboard(chatID, threadID, formatPairing(deadline), stopButton)
+	if err != nil {
+		updateLogger(update).Error("❌ SendMessageWithKeyboard", "error", err)
+		return
+	}
+
+	stop := make(chan struct{})
+	h.pairMu.Lock()
+	if h.pairStop != nil {
+		close(h.pairStop)
+	}
+	h.pairStop = stop
+	h.pairMu.Unlock()
+
+	h.goSafe(update, func() {
+		h.runPairingCountdown(chatID, messageID, deadline, stopButton, stop)
+	})
+}
+
+// runPairingCountdown обновляет обратный отсчет и продлевает permit_join до окончания окна
+func (h *MessageHandler) runPairingCountdown(chatID int64, messageID int, deadline time.Time, stopButton models.InlineKeyboardMarkup, stop chan struct{}) {
+	ticker := time.NewTicker(countdownInterval)
+	defer ticker.Stop()
+	lastPermit := time.Now()
+
+	for {
+		select {
+		case <-stop:
+			h.EditMessageText(chatID, messageID, "🔒 Режим сопряжения выключен", nil)
+			return
+		case <-ticker.C:
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			h.EditMessageText(chatID, messageID, "🔒 Режим сопряжения завершен", nil)
+			h.pairMu.Lock()
+			if h.pairStop == stop {
+				h.pairStop = nil
+			}
+			h.pairMu.Unlock()
+			return
+		}
+
+		if time.Since(lastPermit) >= permitJoinChunk-countdownInterval {
+			if err := h.Zigbee.PermitJoin(int(min(permitJoinChunk, remaining).Seconds())); err != nil {
+				logger.Error("❌ Zigbee: не удалось продлить сопряжение", "error", err)
+			}
+			lastPermit = time.Now()
+		}
+
+		h.EditMessageText(chatID, messageID, formatPairing(deadline), &stopButton)
+	}
+}
+
+func (h *MessageHandler) stopPairing() {
+	h.pairMu.Lock()
+	defer h.pairMu.Unlock()
+
+	if h.pairStop != nil {
+		close(h.pairStop)
+		h.pairStop = nil
+	}
+}
+
+func formatPairing(deadline time.Time) string {
+	remaining := time.Until(deadline).Round(time.Second)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return fmt.Sprintf("🔓 <b>Режим сопряжения Zigbee включен</b>\n⏳ Осталось: <b>%d:%02d</b>\n\nПереведите устройство в режим подключения.",
+		int(remaining.Minutes()), int(remaining.Seconds())%60)
+}
+
+// handleZigbeeEvent сообщает администраторам о подключении, опросе и уходе устройств
+func (h *MessageHandler) handleZigbeeEvent(event zigbee.Event) {
+	name := html.EscapeString(event.Data.FriendlyName)
+	ieee := event.Data.IEEEAddress
+
+	var text string
+	withButtons := false
+
+	switch event.Type {
+	case "device_joined":
+		text = fmt.Sprintf("🆕 <b>Новое устройство в сети</b>\n🐝 %s (<code>%s</code>)", name, ieee)
+		withButtons = true
+	case "device_interview":
+		switch event.Data.Status {
+		case "started":
+			text = fmt.Sprintf("🔍 Опрос устройства %s...", name)
+		case "successful":
+			text = fmt.Sprintf("✅ <b>Устройство опрошено</b>\n🐝 %s (<code>%s</code>)", name, ieee)
+			if definition := event.Data.Definition; definition != nil {
+				text += fmt.Sprintf("\n🏷️ %s %s\n<i>%s</i>",
+					html.EscapeString(definition.Vendor),
+					html.EscapeString(definition.Model),
+					html.EscapeString(definition.Description))
+			}
+			if !event.Data.Supported {
+				text += "\n⚠️ Устройство не поддерживается zigbee2mqtt"
+			}
+			withButtons = true
+		case "failed":
+			text = fmt.Sprintf("⚠️ <b>Не удалось опросить устройство</b>\n🐝 %s (<code>%s</code>)\nПопробуйте переподключить его.", name, ieee)
+			withButtons = true
+		}
+	case "device_leave":
+		text = fmt.Sprintf("👋 Устройство покинуло сеть: %s (<code>%s</code>)", name, ieee)
+	}
+
+	if text == "" {
+		return
+	}
+	if !withButtons || h.Config().AdminChatID == 0 {
+		h.NotifyAdmin(text)
+		return
+	}
+
+	markup := keyboard([]models.InlineKeyboardButton{
+		h.callbackButton("✏️ Переименовать", func(query *models.CallbackQuery) string {
+			if !h.isAdmin(query.From.ID) {
+				return "⛔ Только для администраторов"
+			}
+			h.askDeviceRename(query.Message, ieee)
+			return ""
+		}),
+		h.callbackButton("🗑 Удалить", func(query *models.CallbackQuery) string {
+			if !h.isAdmin(query.From.ID) {
+				return "⛔ Только для администраторов"
+			}
+			h.goSafe(models.Update{CallbackQuery: query}, func() {
+				h.removeZigbeeDevice(query.Message, ieee, false)
+			})
+			return "⏳ Удаляю..."
+		}),
+	})
+	if _, err := h.SendMessageWithKeyboard(h.Config().AdminChatID, h.Config().AdminThreadID, text, markup); err != nil {
+		logger.Error("❌ Ошибка отправки уведомления администраторам", "error", err)
+	}
+}
+
+// askDeviceRename запрашивает новое имя устройства текстом
+func (h *MessageHandler) askDeviceRename(message *models.Message, ieee string) {
+	chatID := message.Chat.ID
+	h.SendMessage(chatID, message.MessageThreadID, fmt.Sprintf("✏️ Введите новое имя для <code>%s</code>:", ieee))
+
+	step := func(text string) (string, InputHandler, error) {
+		newName := strings.TrimSpace(text)
+		if newName == "" {
+			return "", nil, fmt.Errorf("имя не может быть пустым")
+		}
+		if err := h.Zigbee.RenameDevice(ieee, newName); err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("✅ Устройство переименовано в <b>%s</b>", html.EscapeString(newName)), nil, nil
+	}
+	h.states.Store(chatID, &UserState{handler: step})
+}
+
+// removeZigbeeDevice удаляет устройство, а при ошибке предлагает принудительное удаление
+func (h *MessageHandler) removeZigbeeDevice(message *models.Message, ieee string, force bool) {
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	err := h.Zigbee.RemoveDevice(ieee, force)
+	if err == nil {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("🗑 Устройство <code>%s</code> удалено", ieee))
+		return
+	}
+
+	logger.Error("❌ Zigbee: ошибка удаления", "ieee", ieee, "error", err)
+	if force {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Не удалось удалить <code>%s</code>: %s", ieee, html.EscapeString(err.Error())))
+		return
+	}
+
+	markup := keyboard([]models.InlineKeyboardButton{
+		h.callbackButton("⚠️ Удалить принудительно", func(query *models.CallbackQuery) string {
+			if !h.isAdmin(query.From.ID) {
+				return "⛔ Только для администраторов"
+			}
+			h.goSafe(models.Update{CallbackQuery: query}, func() {
+				h.removeZigbeeDevice(query.Message, ieee, true)
+			})
+			return "⏳ Удаляю..."
+		}),
+	})
+	h.SendMessageWithKeyboard(chatID, threadID, fmt.Sprintf("❌ Устройство <code>%s</code> не ответило: %s", ieee, html.EscapeString(err.Error())), markup)
+}
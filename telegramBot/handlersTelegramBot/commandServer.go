@@ -0,0 +1,24 @@
+package handlersTelegramBot
+
+import (
+	"telegramBot/hostinfo"
+	"telegramBot/models"
+)
+
+// StartHostMonitor запускает фоновый мониторинг сервера
+func (h *MessageHandler) StartHostMonitor() {
+	h.HostMonitor = hostinfo.NewMonitor(h.Config().Host, h.NotifyAdmin)
+	if h.Config().Host.MonitorInterval > 0 {
+		h.HostMonitor.Start()
+	}
+}
+
+// HandleServerCommand показывает нагрузку, температуру, память, диски и сеть сервера
+func (h *MessageHandler) HandleServerCommand(update models.Update) {
+	message := update.Message
+
+	if !h.requireAdmin(message) {
+		return
+	}
+	h.SendMessage(message.Chat.ID, message.MessageThreadID, h.HostMonitor.Report())
+}
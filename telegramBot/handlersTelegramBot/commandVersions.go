@@ -0,0 +1,81 @@
+package handlersTelegramBot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"telegramBot/models"
+	"telegramBot/yandexapi"
+)
+
+// HandleVersionsCommand выводит список сохраненных версий файла: /versions <путь>
+func (h *MessageHandler) HandleVersionsCommand(update models.Update, args []string) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if len(args) == 0 {
+		h.SendMessage(chatID, threadID, "ℹ️ Использование: <code>/versions /путь/к/файлу</code>")
+		return
+	}
+	filePath := strings.Join(args, " ")
+
+	versions, err := yandexapi.ListVersions(filePath)
+	if err != nil {
+		updateLogger(update).Error("❌ ListVersions", "error", err)
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка получения версий: %v", err))
+		return
+	}
+
+	if len(versions) == 0 {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("📭 У файла <code>%s</code> нет сохраненных версий", filePath))
+		return
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "🗂️ <b>Версии файла</b> <code>%s</code>:\n", filePath)
+	fmt.Fprintln(&builder, strings.Repeat("─", 20))
+	for i, version := range versions {
+		fmt.Fprintf(&builder, "%d. %s — %s\n",
+			i+1,
+			version.Created.Format("02.01.2006 15:04:05"),
+			yandexapi.FormatBytes(version.Size),
+		)
+	}
+	fmt.Fprintln(&builder, strings.Repeat("─", 20))
+	fmt.Fprintf(&builder, "♻️ Восстановить: <code>/restoreversion %s &lt;номер&gt;</code>", filePath)
+
+	h.SendMessage(chatID, threadID, builder.String())
+}
+
+// HandleRestoreVersionCommand восстанавливает версию файла: /restoreversion <путь> <номер>
+func (h *MessageHandler) HandleRestoreVersionCommand(update models.Update, args []string) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if len(args) < 2 {
+		h.SendMessage(chatID, threadID, "ℹ️ Использование: <code>/restoreversion /путь/к/файлу номер</code>\nНомера версий можно узнать командой /versions")
+		return
+	}
+
+	number, err := strconv.Atoi(args[len(args)-1])
+	if err != nil {
+		h.SendMessage(chatID, threadID, "❌ Номер версии должен быть числом")
+		return
+	}
+	filePath := strings.Join(args[:len(args)-1], " ")
+
+	version, err := yandexapi.RestoreVersion(filePath, number)
+	if err != nil {
+		updateLogger(update).Error("❌ RestoreVersion", "error", err)
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка восстановления: %v", err))
+		return
+	}
+
+	h.SendMessage(chatID, threadID, fmt.Sprintf("✅ Файл <code>%s</code> восстановлен из версии от %s\n🗂️ Прежнее содержимое сохранено как новая версия",
+		filePath,
+		version.Created.Format("02.01.2006 15:04:05"),
+	))
+}
@@ -0,0 +1,302 @@
+package handlersTelegramBot
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"telegramBot/models"
+	"telegramBot/yandexapi"
+	"telegramBot/zigbee"
+)
+
+const zigbeeSetTimeout = 5 * time.Second
+
+// StartZigbee подключает мост zigbee2mqtt поверх MQTT-клиента
+func (h *MessageHandler) StartZigbee() error {
+	if h.MQTT == nil {
+		return nil
+	}
+
+	h.Zigbee = zigbee.NewBridge(h.MQTT, h.Config().Zigbee.BaseTopic)
+	h.Zigbee.OnEvent(h.handleZigbeeEvent)
+	if err := h.Zigbee.Start(); err != nil {
+		return err
+	}
+
+	h.ZigbeeMonitor = zigbee.NewMonitor(h.Zigbee, h.Config().Zigbee, h.NotifyAdmin)
+	h.ZigbeeMonitor.Start()
+	return nil
+}
+
+// HandleZigbeeMapCommand запрашивает карту сети, отправляет ее картинкой и при необходимости архивирует
+func (h *MessageHandler) HandleZigbeeMapCommand(update models.Update) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if !h.requireAdmin(message) {
+		return
+	}
+	if h.Zigbee == nil {
+		h.SendMessage(chatID, threadID, "❌ Zigbee недоступен: MQTT не настроен")
+		return
+	}
+
+	h.SendMessage(chatID, threadID, "🗺️ Сканирую Zigbee-сеть, это может занять пару минут...")
+
+	h.goSafe(update, func() {
+		networkMap, raw, err := h.Zigbee.NetworkMap()
+		if err != nil {
+			updateLogger(update).Error("❌ Zigbee: ошибка получения карты сети", "error", err)
+			h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка получения карты сети: %s", html.EscapeString(err.Error())))
+			return
+		}
+
+		image, err := zigbee.RenderNetworkMap(networkMap)
+		if err != nil {
+			updateLogger(update).Error("❌ Zigbee: ошибка отрисовки карты сети", "error", err)
+			h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка отрисовки карты: %s", html.EscapeString(err.Error())))
+			return
+		}
+
+		now := time.Now()
+		caption := fmt.Sprintf("🗺️ Карта Zigbee-сети на %s\n🐝 Узлов: %d, связей: %d",
+			now.Format("02.01.2006 15:04"), len(networkMap.Nodes), len(networkMap.Links))
+
+		if archiveDir := h.Config().Zigbee.MapArchiveDir; archiveDir != "" {
+			baseName := "networkmap-" + now.Format("2006-01-02-1504")
+			err := yandexapi.EnsureDirectory(archiveDir)
+			if err == nil {
+				err = yandexapi.UploadFile(archiveDir, baseName+".png", image)
+			}
+			if err == nil {
+				err = yandexapi.UploadFile(archiveDir, baseName+".json", raw)
+			}
+			if err != nil {
+				updateLogger(update).Error("❌ Zigbee: ошибка архивации карты сети", "error", err)
+				caption += "\n⚠️ Не удалось сохранить копию на Яндекс.Диск"
+			} else {
+				caption += fmt.Sprintf("\n💾 Копия: <code>%s/%s.png</code>", html.EscapeString(archiveDir), baseName)
+			}
+		}
+
+		if err := h.SendPhoto(chatID, threadID, image, "networkmap.png", caption); err != nil {
+			updateLogger(update).Error("❌ SendPhoto", "error", err)
+		}
+	})
+}
+
+// HandleHealthCommand выводит сводку о состоянии подсистемы: /health zigbee
+func (h *MessageHandler) HandleHealthCommand(update models.Update, args []string) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	subsystem := ""
+	if len(args) > 0 {
+		subsystem = strings.ToLower(args[0])
+	}
+
+	switch subsystem {
+	case "zigbee":
+		if h.ZigbeeMonitor == nil {
+			h.SendMessage(chatID, threadID, "❌ Zigbee недоступен: MQTT не настроен")
+			return
+		}
+		h.SendMessage(chatID, threadID, h.ZigbeeMonitor.Report())
+	case "server":
+		h.HandleServerCommand(update)
+	default:
+		h.SendMessage(chatID, threadID, "ℹ️ Использование: <code>/health zigbee|server</code>")
+	}
+}
+
+// HandleDevicesCommand выводит список Zigbee-устройств
+func (h *MessageHandler) HandleDevicesCommand(update models.Update) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if h.Zigbee == nil {
+		h.SendMessage(chatID, threadID, "❌ Zigbee недоступен: MQTT не настроен")
+		return
+	}
+
+	devices := h.Zigbee.Devices()
+	if len(devices) == 0 {
+		h.SendMessage(chatID, threadID, "📭 Список устройств пуст или еще не получен от zigbee2mqtt")
+		return
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "🐝 <b>Zigbee-устройства</b> (%d):\n", len(devices))
+	fmt.Fprintln(&builder, strings.Repeat("─", 20))
+	for _, device := range devices {
+		fmt.Fprintf(&builder, "%s <b>%s</b>", deviceIcon(device), html.EscapeString(device.FriendlyName))
+		if model := device.Model(); model != "" {
+			fmt.Fprintf(&builder, " — %s", html.EscapeString(model))
+		}
+		fmt.Fprintln(&builder)
+
+		state, ok := h.Zigbee.State(device.FriendlyName)
+		if !ok {
+			fmt.Fprintln(&builder, "      ❔ нет данных")
+			continue
+		}
+		linkQuality := "—"
+		if lqi := state.LinkQuality(); lqi >= 0 {
+			linkQuality = fmt.Sprint(lqi)
+		}
+		fmt.Fprintf(&builder, "      📶 %s · 🕒 %s\n", linkQuality, formatAgo(state.LastSeen()))
+	}
+	fmt.Fprint(&builder, "\nПодробнее: <code>/device &lt;имя&gt;</code>")
+
+	h.SendMessage(chatID, threadID, builder.String())
+}
+
+// HandleDeviceCommand показывает состояние устройства с кнопками управления
+func (h *MessageHandler) HandleDeviceCommand(update models.Update) {
+	message := update.Message
+	chatID := message.Chat.ID
+	threadID := message.MessageThreadID
+
+	if h.Zigbee == nil {
+		h.SendMessage(chatID, threadID, "❌ Zigbee недоступен: MQTT не настроен")
+		return
+	}
+
+	name := commandRest(message.Text, 1)
+	if name == "" {
+		h.SendMessage(chatID, threadID, "ℹ️ Использование: <code>/device &lt;имя&gt;</code>\nСписок устройств: /devices")
+		return
+	}
+
+	device, ok := h.Zigbee.Device(name)
+	if !ok {
+		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Устройство <b>%s</b> не найдено", html.EscapeString(name)))
+		return
+	}
+
+	text, markup := h.renderDevice(device, "")
+	if _, err := h.SendMessageWithKeyboard(chatID, threadID, text, markup); err != nil {
+		updateLogger(update).Error("❌ SendMessageWithKeyboard", "error", err)
+	}
+}
+
+// renderDevice готовит карточку устройства и клавиатуру управления
+func (h *MessageHandler) renderDevice(device zigbee.Device, note string) (string, models.InlineKeyboardMarkup) {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s <b>%s</b>\n", deviceIcon(device), html.EscapeString(device.FriendlyName))
+	if device.Definition != nil {
+		fmt.Fprintf(&builder, "🏷️ %s %s\n", html.EscapeString(device.Definition.Vendor), html.EscapeString(device.Definition.Model))
+		if device.Definition.Description != "" {
+			fmt.Fprintf(&builder, "<i>%s</i>\n", html.EscapeString(device.Definition.Description))
+		}
+	}
+
+	state, ok := h.Zigbee.State(device.FriendlyName)
+	if ok {
+		fmt.Fprintf(&builder, "🕒 Обновлено: %s\n\n", formatAgo(state.LastSeen()))
+		keys := make([]string, 0, len(state.Payload))
+		for key := range state.Payload {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			switch value := state.Payload[key].(type) {
+			case map[string]any, []any, nil:
+				// Вложенные объекты (update, color и т.п.) не показываем
+			default:
+				fmt.Fprintf(&builder, "• %s: <b>%s</b>\n", html.EscapeString(key), html.EscapeString(fmt.Sprint(value)))
+			}
+		}
+	} else {
+		fmt.Fprintln(&builder, "\n❔ Состояние еще не получено")
+	}
+
+	if note != "" {
+		fmt.Fprintf(&builder, "\n%s", note)
+	}
+
+	var rows [][]models.InlineKeyboardButton
+	if _, ok := device.Settable("state"); ok {
+		rows = append(rows, []models.InlineKeyboardButton{
+			h.deviceButton(device, "🟢 Вкл", map[string]any{"state": "ON"}),
+			h.deviceButton(device, "🔴 Выкл", map[string]any{"state": "OFF"}),
+			h.deviceButton(device, "🔁", map[string]any{"state": "TOGGLE"}),
+		})
+	}
+	if brightness, ok := device.Settable("brightness"); ok {
+		maxValue := 254.0
+		if brightness.ValueMax != nil {
+			maxValue = *brightness.ValueMax
+		}
+		var row []models.InlineKeyboardButton
+		for _, percent := range []int{10, 50, 100} {
+			value := int(math.Round(maxValue * float64(percent) / 100))
+			row = append(row, h.deviceButton(device, fmt.Sprintf("🔆 %d%%", percent), map[string]any{"brightness": value}))
+		}
+		rows = append(rows, row)
+	}
+	rows = append(rows, []models.InlineKeyboardButton{
+		h.callbackButton("🔄 Обновить", func(query *models.CallbackQuery) string {
+			text, markup := h.renderDevice(device, "")
+			h.EditMessageText(query.Message.Chat.ID, query.Message.MessageID, text, &markup)
+			return ""
+		}),
+	})
+
+	return builder.String(), keyboard(rows...)
+}
+
+// deviceButton отправляет команду устройству и обновляет карточку после подтверждения
+func (h *MessageHandler) deviceButton(device zigbee.Device, text string, command map[string]any) models.InlineKeyboardButton {
+	return h.callbackButton(text, func(query *models.CallbackQuery) string {
+		if !h.isAdmin(query.From.ID) {
+			return "⛔ Управление доступно только администраторам"
+		}
+
+		h.goSafe(models.Update{CallbackQuery: query}, func() {
+			note := "✅ Команда выполнена"
+			if _, err := h.Zigbee.Set(device.FriendlyName, command, zigbeeSetTimeout); err != nil {
+				logger.Error("❌ Zigbee", "error", err)
+				note = "⚠️ " + html.EscapeString(err.Error())
+			}
+			text, markup := h.renderDevice(device, note)
+			h.EditMessageText(query.Message.Chat.ID, query.Message.MessageID, text, &markup)
+		})
+		return "⏳ Команда отправлена"
+	})
+}
+
+func deviceIcon(device zigbee.Device) string {
+	switch device.Type {
+	case "Router":
+		return "🔌"
+	case "EndDevice":
+		return "🔋"
+	}
+	return "🐝"
+}
+
+// formatAgo описывает, сколько времени прошло с момента t
+func formatAgo(t time.Time) string {
+	if t.IsZero() {
+		return "никогда"
+	}
+
+	elapsed := time.Since(t)
+	switch {
+	case elapsed < time.Minute:
+		return "только что"
+	case elapsed < time.Hour:
+		return fmt.Sprintf("%d мин назад", int(elapsed.Minutes()))
+	case elapsed < 24*time.Hour:
+		return fmt.Sprintf("%d ч назад", int(elapsed.Hours()))
+	}
+	return fmt.Sprintf("%d дн назад", int(elapsed.Hours()/24))
+}
@@ -0,0 +1,250 @@
+package handlersTelegramBot
+
+import (
+	"fmt"
+	"html"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"telegramBot/logging"
+	"telegramBot/models"
+)
+
+const (
+	// Сколько групп ошибок перечислять в сообщении, остальные - только в файле
+	errorReportMaxGroups = 10
+
+	// Сколько помнить, какая команда и какой пользователь стоят за обновлением:
+	// обработчики часто продолжают работу в горутинах после ответа
+	errorUpdateTTL = 15 * time.Minute
+)
+
+// updateInfo - кто и чем вызвал обработку обновления
+type updateInfo struct {
+	command string
+	user    string
+	seen    time.Time
+}
+
+// errorGroup - одинаковые ошибки за интервал отчета
+type errorGroup struct {
+	first logging.Entry
+	last  time.Time
+	count int
+	info  *updateInfo
+	stack string
+}
+
+// errorReporter копит ошибки из лога и раз в интервал отправляет администраторам
+// сводку, а подробности со стеком вызовов - файлом
+type errorReporter struct {
+	enabled bool
+	mu      sync.Mutex
+	groups  map[string]*errorGroup
+	order   []string
+	updates sync.Map // ключ: corr_id, значение: *updateInfo
+}
+
+// trackUpdate запоминает команду и пользователя обновления для отчета об ошибках
+func (r *errorReporter) trackUpdate(update models.Update) {
+	if !r.enabled {
+		return
+	}
+
+	var info updateInfo
+	switch {
+	case update.CallbackQuery != nil:
+		query := update.CallbackQuery
+		info.command = "кнопка " + query.Data
+		info.user = formatUser(query.From)
+	case update.Message != nil:
+		info.command, _ = parseCommand(update.Message.Text)
+		if info.command == "" {
+			info.command = updateType(update)
+		}
+		info.user = formatUser(update.Message.From)
+	default:
+		return
+	}
+	info.seen = time.Now()
+	r.updates.Store(update.CorrelationID, &info)
+}
+
+func formatUser(user models.User) string {
+	name := user.FirstName
+	if user.Username != "" {
+		name += " @" + user.Username
+	}
+	return fmt.Sprintf("%s (%d)", name, user.ID)
+}
+
+// add - подписчик лога: вызывается синхронно в горутине, где произошла ошибка,
+// поэтому стек вызовов снимается здесь
+func (r *errorReporter) add(entry logging.Entry) {
+	stack := string(debug.Stack())
+	key := entry.Component + "\x00" + entry.Message
+	// Все паники пишутся одним сообщением: разные паники разделяются по значению и месту
+	if value := entry.Attr("panic"); value != "" {
+		key += "\x00" + value + "\x00" + panicFrame(stack)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if group, ok := r.groups[key]; ok {
+		group.count++
+		group.last = entry.Time
+		return
+	}
+
+	group := &errorGroup{first: entry, last: entry.Time, count: 1, stack: stack}
+	if infoI, ok := r.updates.Load(entry.Attr("corr_id")); ok {
+		group.info = infoI.(*updateInfo)
+	}
+	r.groups[key] = group
+	r.order = append(r.order, key)
+}
+
+// panicFrame возвращает место паники "файл:строка": первый кадр после panic(...),
+// не считая кадров runtime (runtime.sigpanic и т.п.)
+func panicFrame(stack string) string {
+	lines := strings.Split(stack, "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "panic(") {
+			continue
+		}
+		for j := i + 2; j+1 < len(lines); j += 2 {
+			if !strings.HasPrefix(lines[j], "runtime.") {
+				location, _, _ := strings.Cut(strings.TrimSpace(lines[j+1]), " +0x")
+				return location
+			}
+		}
+	}
+	return ""
+}
+
+// take забирает накопленные группы и забывает старые обновления
+func (r *errorReporter) take() []*errorGroup {
+	r.updates.Range(func(key, value any) bool {
+		if time.Since(value.(*updateInfo).seen) > errorUpdateTTL {
+			r.updates.Delete(key)
+		}
+		return true
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	groups := make([]*errorGroup, 0, len(r.order))
+	for _, key := range r.order {
+		groups = append(groups, r.groups[key])
+	}
+	r.groups = make(map[string]*errorGroup)
+	r.order = nil
+	return groups
+}
+
+// startErrorReports подписывается на ошибки в логе и пересылает их администраторам
+func (h *MessageHandler) startErrorReports() {
+	interval := h.Config().ErrorReportInterval
+	if interval <= 0 || h.Config().AdminChatID == 0 {
+		logger.Info("ℹ️ Пересылка ошибок администраторам выключена (ERROR_REPORT_INTERVAL, ADMIN_CHAT_ID)")
+		return
+	}
+
+	h.errors.groups = make(map[string]*errorGroup)
+	h.errors.enabled = true
+	logging.Subscribe(slog.LevelError, h.errors.add)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			h.sendErrorReport(interval)
+		}
+	}()
+	logger.Info("🚨 Ошибки пересылаются администраторам", "interval", interval)
+}
+
+func (h *MessageHandler) sendErrorReport(interval time.Duration) {
+	groups := h.errors.take()
+	if len(groups) == 0 {
+		return
+	}
+
+	total := 0
+	for _, group := range groups {
+		total += group.count
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "🚨 <b>Ошибки бота</b> за %s: %d\n", interval, total)
+	for i, group := range groups {
+		if i == errorReportMaxGroups {
+			fmt.Fprintf(&summary, "\n… и еще %d, см. файл", len(groups)-i)
+			break
+		}
+		fmt.Fprintf(&summary, "\n• <b>%s</b>", html.EscapeString(group.first.Message))
+		if group.first.Component != "" {
+			fmt.Fprintf(&summary, " [%s]", html.EscapeString(group.first.Component))
+		}
+		if group.count > 1 {
+			fmt.Fprintf(&summary, " ×%d", group.count)
+		}
+		if errText := group.first.Attr("error"); errText != "" {
+			fmt.Fprintf(&summary, "\n  <code>%s</code>", html.EscapeString(truncateRunes(errText, 200)))
+		}
+		if group.info != nil {
+			fmt.Fprintf(&summary, "\n  ⌨️ %s · 👤 %s", html.EscapeString(group.info.command), html.EscapeString(group.info.user))
+		}
+	}
+
+	chatID, threadID := h.Config().AdminChatID, h.Config().AdminThreadID
+	// Ошибки отправки отчета, в том числе ответ Bot API, пишем предупреждением: иначе
+	// неудачная отправка попадала бы в следующий отчет и повторялась каждый интервал
+	if _, err := h.sendMessageAt(slog.LevelWarn, chatID, threadID, summary.String(), nil); err != nil {
+		logger.Warn("⚠️ Ошибка отправки отчета об ошибках", "error", err)
+		return
+	}
+
+	var details strings.Builder
+	for _, group := range groups {
+		fmt.Fprintf(&details, "%s\n", group.first)
+		fmt.Fprintf(&details, "Повторов: %d, последний: %s\n", group.count, group.last.Format("02.01 15:04:05"))
+		if group.info != nil {
+			fmt.Fprintf(&details, "Команда: %s\nПользователь: %s\n", group.info.command, group.info.user)
+		}
+		fmt.Fprintf(&details, "\n%s\n%s\n\n", logging.Redact(group.stack), strings.Repeat("─", 60))
+	}
+	fileName := fmt.Sprintf("errors-%s.txt", time.Now().Format("20060102-150405"))
+	if err := h.sendFileAt(slog.LevelWarn, "sendDocument", "document", chatID, threadID, []byte(details.String()), fileName, "🧾 Подробности и стек вызовов"); err != nil {
+		logger.Warn("⚠️ Ошибка отправки отчета об ошибках", "error", err)
+	}
+}
+
+// recoverPanic не дает панике в обработчике остановить бота: паника пишется
+// в лог как ошибка и попадает в отчет администраторам вместе со стеком
+func (h *MessageHandler) recoverPanic(update models.Update) {
+	if r := recover(); r != nil {
+		updateLogger(update).Error("💥 Паника в обработчике", "panic", fmt.Sprint(r))
+	}
+}
+
+// goSafe запускает fn в отдельной горутине с той же защитой от паники, что и обработчики обновлений
+func (h *MessageHandler) goSafe(update models.Update, fn func()) {
+	go func() {
+		defer h.recoverPanic(update)
+		fn()
+	}()
+}
+
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "…"
+}
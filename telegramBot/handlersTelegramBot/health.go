@@ -0,0 +1,40 @@
+package handlersTelegramBot
+
+import (
+	"fmt"
+	"time"
+
+	"telegramBot/metrics"
+	"telegramBot/yandexapi/method"
+)
+
+// Long polling держит запрос до 60 секунд; дольше без ответа - цикл завис или Telegram недоступен
+const maxPollSilence = 3 * time.Minute
+
+// startMetrics запускает /metrics, /healthz и /readyz.
+// healthz: Telegram отвечает и цикл получения обновлений работает.
+// readyz: дополнительно токен Яндекс.Диска действителен
+func (b *Bot) startMetrics() {
+	server := metrics.NewServer(b.config.Metrics.Listen)
+
+	server.Liveness("telegram", func() error {
+		_, err := b.handler.callTelegram("getMe", nil)
+		return err
+	})
+	server.Liveness("polling", func() error {
+		last := b.lastPoll.Load()
+		if last == 0 {
+			return nil
+		}
+		if silence := time.Since(time.Unix(last, 0)); silence > maxPollSilence {
+			return fmt.Errorf("нет ответа getUpdates %s", silence.Round(time.Second))
+		}
+		return nil
+	})
+	server.Readiness("yandex_disk", func() error {
+		_, err := method.GetDiskInfo()
+		return err
+	})
+
+	server.Start()
+}
@@ -2,23 +2,70 @@ package handlersTelegramBot
 
 import (
 	"fmt"
-	"io"
-	"log"
-	"net/http"
-	"net/url"
-	"strconv"
+	"log/slog"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"telegramBot/backup"
+	"telegramBot/camera"
 	"telegramBot/config"
+	"telegramBot/dockerapi"
+	"telegramBot/energy"
+	"telegramBot/homeassistant"
+	"telegramBot/hostinfo"
+	"telegramBot/lan"
+	"telegramBot/logging"
+	"telegramBot/metrics"
 	"telegramBot/models"
+	"telegramBot/mqttclient"
+	"telegramBot/yandexapi/oauth"
+	"telegramBot/zigbee"
 )
 
 type MessageHandler struct {
 	Token          string
-	Config         *config.Config
+	config         atomic.Pointer[config.Config] // заменяется целиком при перезагрузке конфигурации
+	reloadMu       sync.Mutex
 	states         sync.Map // ключ: chatID (int64), значение: *UserState
 	uploadSessions sync.Map // ключ: chatID, значение: *UploadSession
+	callbacks      sync.Map // ключ: callback_data, значение: *callbackEntry
+	callbackSeq    atomic.Uint64
+	limiter        *rateLimiter
+
+	Backup *backup.Service
+
+	MQTT        *mqttclient.Client
+	mqttRoutes  *mqttclient.RouteStore
+	mqttCancels sync.Map // ключ: mqttclient.Route, значение: func() для отписки
+
+	Zigbee        *zigbee.Bridge
+	ZigbeeMonitor *zigbee.Monitor
+	pairMu        sync.Mutex
+	pairStop      chan struct{} // закрывается, чтобы остановить текущий обратный отсчет /pair
+
+	HA          *homeassistant.Client
+	haFavorites []homeassistant.Favorite
+	haRules     *homeassistant.Rules
+	haEvents    *homeassistant.EventStream
+
+	Cameras *camera.Service
+
+	EnergyMeter  *energy.Meter
+	energyStore  *energy.Store
+	energyTariff energy.Tariff
+
+	HostMonitor *hostinfo.Monitor
+
+	YandexOAuth *oauth.Manager
+
+	Docker        *dockerapi.Client
+	DockerMonitor *dockerapi.Monitor
+
+	lanDevices []*lan.Device
+
+	errors errorReporter
 }
 
 type InputHandler func(text string) (response string, next InputHandler, err error)
@@ -36,14 +83,45 @@ type UploadSession struct {
 }
 
 func NewMessageHandler(token string, config *config.Config) *MessageHandler {
-	return &MessageHandler{
-		Token:  token,
-		Config: config,
-		states: sync.Map{},
+	h := &MessageHandler{
+		Token:   token,
+		states:  sync.Map{},
+		limiter: newRateLimiter(),
 	}
+	h.config.Store(config)
+	metrics.Sessions("input", func() int { return countEntries(&h.states) })
+	metrics.Sessions("upload", func() int { return countEntries(&h.uploadSessions) })
+	return h
+}
+
+// Config возвращает текущую конфигурацию. Значение не меняется: при перезагрузке
+// подставляется новая конфигурация, поэтому ее можно читать без блокировок
+func (h *MessageHandler) Config() *config.Config {
+	return h.config.Load()
+}
+
+func countEntries(m *sync.Map) int {
+	count := 0
+	m.Range(func(_, _ any) bool {
+		count++
+		return true
+	})
+	return count
 }
 
 func (h *MessageHandler) HandleUpdate(update models.Update) {
+	metrics.Update(updateType(update))
+	update.CorrelationID = logging.NewCorrelationID()
+	logger := updateLogger(update)
+	h.errors.trackUpdate(update)
+	defer h.recoverPanic(update)
+
+	if query := update.CallbackQuery; query != nil {
+		logger.Info("🔘 Нажата кнопка", "data", query.Data, "user", query.From.FirstName, "user_id", query.From.ID)
+		h.HandleCallbackQuery(query)
+		return
+	}
+
 	if update.Message == nil {
 		return
 	}
@@ -57,31 +135,29 @@ func (h *MessageHandler) HandleUpdate(update models.Update) {
 		h.HandleUploadFile(update)
 		return
 	}
-	log.Printf("📩 Получено сообщение:")
-	log.Printf("   	👤 От: %s (@%s)", message.From.FirstName, message.From.Username)
-	log.Printf("   	🆔 Chat ID: %d", message.Chat.ID)
-	log.Printf("   	🏷️ Thread ID: %d", message.MessageThreadID)
-	log.Printf("   	📊 Тип чата: %s", message.Chat.Type)
-
-	if message.Chat.Title != "" {
-		log.Printf("   	🏷️ Название чата: %s", message.Chat.Title)
-	}
+	logger.Info("📩 Получено сообщение",
+		"user", message.From.FirstName,
+		"username", message.From.Username,
+		"thread_id", message.MessageThreadID,
+		"chat_type", message.Chat.Type,
+		"chat_title", message.Chat.Title,
+	)
 
 	// Определяем тип сообщения и передаем соответствующему обработчику
 	switch {
 	case len(message.Photo) > 0:
-		log.Printf("   	📸 Фото: %d вариантов размера", len(message.Photo))
+		logger.Debug("📸 Фото", "sizes", len(message.Photo))
 		// h.HandlePhoto(update)
 	case message.Document.FileID != "":
-		log.Printf("   	📎 Документ: %s", message.Document.FileName)
+		logger.Debug("📎 Документ", "file", message.Document.FileName)
 		// h.HandleDocument(update)
 	case message.Text == "":
-		log.Printf("   	💬 Текст: (пустое сообщение или другой тип)")
+		logger.Debug("💬 Пустое сообщение или другой тип")
 		h.HandleOtherMessage(update)
 	case message.MessageThreadID == 29:
-		log.Printf("   		💬 Это чат Наши фотографии")
+		logger.Debug("💬 Это чат Наши фотографии")
 	default:
-		log.Printf("   	💬 Текст: %s", message.Text)
+		logger.Debug("💬 Текст", "text", message.Text)
 		h.HandleTextMessage(update)
 	}
 }
@@ -95,7 +171,23 @@ func (h *MessageHandler) HandleTextMessage(update models.Update) {
 		return
 	}
 
-	switch message.Text {
+	command, args := parseCommand(message.Text)
+
+	started := time.Now()
+	known := true
+	defer func() {
+		if !strings.HasPrefix(command, "/") {
+			return
+		}
+		// Неизвестные команды собираются под одной меткой, чтобы не раздувать число рядов
+		if !known {
+			command = "unknown"
+		}
+		metrics.Command(command, time.Since(started))
+		updateLogger(update).Info("⌨️ Команда обработана", "command", command, "duration", time.Since(started))
+	}()
+
+	switch command {
 	case "/start":
 		h.HandleStartCommand(update)
 	case "/help":
@@ -116,10 +208,101 @@ func (h *MessageHandler) HandleTextMessage(update models.Update) {
 		h.HandleContentsDirectory(update)
 	case "/uploadFile":
 		h.HandleUploadFile(update)
+	case "/versions":
+		h.HandleVersionsCommand(update, args)
+	case "/restoreversion":
+		h.HandleRestoreVersionCommand(update, args)
+	case "/backupnow":
+		h.HandleBackupNowCommand(update)
+	case "/backups":
+		h.HandleBackupsCommand(update)
+	case "/restore":
+		h.HandleRestoreCommand(update, args)
+	case "/mqtt":
+		h.HandleMQTTCommand(update, args)
+	case "/devices":
+		h.HandleDevicesCommand(update)
+	case "/device":
+		h.HandleDeviceCommand(update)
+	case "/pair":
+		h.HandlePairCommand(update, args)
+	case "/zigbeemap":
+		h.HandleZigbeeMapCommand(update)
+	case "/ha":
+		h.HandleHACommand(update, args)
+	case "/snap":
+		h.HandleSnapCommand(update, args)
+	case "/chart":
+		h.HandleChartCommand(update, args)
+	case "/energy":
+		h.HandleEnergyCommand(update, args)
+	case "/server":
+		h.HandleServerCommand(update)
+	case "/wake":
+		h.HandleWakeCommand(update, args)
+	case "/whoishome":
+		h.HandleWhoIsHomeCommand(update)
+	case "/containers":
+		h.HandleContainersCommand(update)
+	case "/restart":
+		h.HandleRestartCommand(update, args)
+	case "/logs":
+		h.HandleLogsCommand(update, args)
+	case "/connectdisk":
+		h.HandleConnectDiskCommand(update, args)
+	case "/reloadconfig":
+		h.HandleReloadConfigCommand(update)
+	case "/health":
+		h.HandleHealthCommand(update, args)
+	default:
+		known = false
+		if h.Config().OfferLinks && !strings.HasPrefix(command, "/") {
+			h.HandleLinkMessage(update)
+		}
+	}
+}
+
+// updateLogger - логгер с идентификатором обновления для сквозной связи записей
+func updateLogger(update models.Update) *slog.Logger {
+	logger := logger.With("corr_id", update.CorrelationID, "update_id", update.UpdateID)
+	if update.Message != nil {
+		logger = logger.With("chat_id", update.Message.Chat.ID)
+	}
+	return logger
+}
+
+// updateType - тип обновления для метрик
+func updateType(update models.Update) string {
+	switch {
+	case update.CallbackQuery != nil:
+		return "callback_query"
+	case update.Message == nil:
+		return "other"
+	case len(update.Message.Photo) > 0:
+		return "photo"
+	case update.Message.Document.FileID != "":
+		return "document"
+	case update.Message.Text != "":
+		return "text"
 	default:
+		return "message"
 	}
 }
 
+// parseCommand разделяет текст на команду и аргументы, отбрасывая @имя_бота
+func parseCommand(text string) (string, []string) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	command := fields[0]
+	if at := strings.Index(command, "@"); at > 0 && strings.HasPrefix(command, "/") {
+		command = command[:at]
+	}
+	return command, fields[1:]
+}
+
 func (h *MessageHandler) HandleRegularMessage(update models.Update) {
 	message := update.Message
 	response := fmt.Sprintf(`✅ <b>Сообщение получено!</b>
@@ -132,7 +315,7 @@ func (h *MessageHandler) HandleRegularMessage(update models.Update) {
 📊 <b>Техническая информация:</b>
 • 💬 Чат ID: <code>%d</code>
 • 🏷️ Топик ID: <code>%d</code>
-• 📏 Макс. длина API: <b>%d символов</b>
+• 📏 Уровень логов: <b>%s</b>
 
 🎯 <i>Этот ответ отправлен в тот же топик!</i>`,
 		message.Text,
@@ -140,44 +323,12 @@ func (h *MessageHandler) HandleRegularMessage(update models.Update) {
 		message.From.Username,
 		message.Chat.ID,
 		message.MessageThreadID,
-		h.Config.MaxLengthAPIOutput,
+		logging.Describe(),
 	)
 
 	h.SendMessage(message.Chat.ID, message.MessageThreadID, response)
 }
 
-func (h *MessageHandler) SendMessage(chatID int64, threadID int, text string) error {
-	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", h.Token)
-
-	params := url.Values{}
-	params.Add("chat_id", strconv.FormatInt(chatID, 10))
-	params.Add("text", text)
-	params.Add("parse_mode", "HTML")
-
-	if threadID != 0 {
-		params.Add("message_thread_id", strconv.Itoa(threadID))
-		log.Printf("📤 Отправка сообщения в топик %d", threadID)
-	} else {
-		log.Printf("📤 Отправка сообщения в основной чат")
-	}
-
-	resp, err := http.PostForm(apiURL, params)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("❌ Ошибка API: %s - %s", resp.Status, string(body))
-		return fmt.Errorf("API error: %s - %s", resp.Status, string(body))
-	}
-
-	log.Printf("✅ Сообщение успешно отправлено!")
-	return nil
-}
-
 func (h *MessageHandler) handleUserInput(update models.Update, state *UserState) {
 	message := update.Message
 	chatID := message.Chat.ID
@@ -194,6 +345,7 @@ func (h *MessageHandler) handleUserInput(update models.Update, state *UserState)
 	// Вызываем текущий обработчик
 	response, nextHandler, err := state.handler(text)
 	if err != nil {
+		updateLogger(update).Error("❌ Ошибка обработки ввода", "error", err)
 		h.states.Delete(chatID)
 		h.SendMessage(chatID, threadID, fmt.Sprintf("❌ Ошибка: %v", err))
 		return
@@ -213,6 +365,52 @@ func (h *MessageHandler) handleUserInput(update models.Update, state *UserState)
 	}
 }
 
+// commandRest возвращает исходный текст после первых skip слов (с сохранением пробелов)
+func commandRest(text string, skip int) string {
+	rest := strings.TrimSpace(text)
+	for i := 0; i < skip && rest != ""; i++ {
+		end := strings.IndexAny(rest, " \t\n")
+		if end < 0 {
+			return ""
+		}
+		rest = strings.TrimSpace(rest[end:])
+	}
+	return rest
+}
+
+// NotifyAdmin отправляет служебное сообщение в чат администраторов
+func (h *MessageHandler) NotifyAdmin(text string) {
+	if h.Config().AdminChatID == 0 {
+		logger.Warn("📢 ADMIN_CHAT_ID не задан, уведомление только в лог", "text", text)
+		return
+	}
+
+	if err := h.SendMessage(h.Config().AdminChatID, h.Config().AdminThreadID, text); err != nil {
+		logger.Error("❌ Ошибка отправки уведомления администраторам", "error", err)
+	}
+}
+
+// isAdmin проверяет, входит ли пользователь в список ADMIN_IDS
+func (h *MessageHandler) isAdmin(userID int64) bool {
+	for _, adminID := range h.Config().AdminIDs {
+		if adminID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// requireAdmin отвечает отказом, если команду вызвал не администратор
+func (h *MessageHandler) requireAdmin(message *models.Message) bool {
+	if h.isAdmin(message.From.ID) {
+		return true
+	}
+
+	logger.Warn("⛔ Команда от пользователя без прав", "command", message.Text, "user_id", message.From.ID)
+	h.SendMessage(message.Chat.ID, message.MessageThreadID, "⛔ Команда доступна только администраторам")
+	return false
+}
+
 // Вспомогательные методы
 func (h *MessageHandler) getCaptionText(caption string) string {
 	if caption == "" {
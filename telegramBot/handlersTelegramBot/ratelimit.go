@@ -0,0 +1,78 @@
+package handlersTelegramBot
+
+import (
+	"sync"
+	"time"
+
+	"telegramBot/metrics"
+)
+
+// Ограничения Telegram: ~30 сообщений в секунду всего, 1 в секунду в личный чат, 20 в минуту в группу.
+// Небольшой запас (burst) позволяет отправить несколько сообщений подряд без задержки.
+var (
+	globalLimit  = rateLimit{burst: 30, perSecond: 30}
+	privateLimit = rateLimit{burst: 3, perSecond: 1}
+	groupLimit   = rateLimit{burst: 5, perSecond: 20.0 / 60}
+)
+
+type rateLimit struct {
+	burst     float64
+	perSecond float64
+}
+
+// bucket - "ведро с токенами". Токены могут уходить в минус: это очередь ожидающих отправки.
+type bucket struct {
+	limit   rateLimit
+	tokens  float64
+	updated time.Time
+}
+
+// reserve забирает токен и возвращает, сколько нужно подождать до отправки
+func (b *bucket) reserve(now time.Time) time.Duration {
+	b.tokens = min(b.limit.burst, b.tokens+now.Sub(b.updated).Seconds()*b.limit.perSecond)
+	b.updated = now
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.limit.perSecond * float64(time.Second))
+}
+
+// rateLimiter выравнивает поток исходящих сообщений, чтобы не получать 429 от Telegram
+type rateLimiter struct {
+	mu     sync.Mutex
+	global *bucket
+	chats  map[int64]*bucket
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		global: &bucket{limit: globalLimit, tokens: globalLimit.burst, updated: time.Now()},
+		chats:  make(map[int64]*bucket),
+	}
+}
+
+// Wait блокирует вызывающего, пока отправка в чат не уложится в лимиты
+func (l *rateLimiter) Wait(chatID int64) {
+	now := time.Now()
+
+	l.mu.Lock()
+	chat, ok := l.chats[chatID]
+	if !ok {
+		limit := privateLimit
+		if chatID < 0 {
+			limit = groupLimit
+		}
+		chat = &bucket{limit: limit, tokens: limit.burst, updated: now}
+		l.chats[chatID] = chat
+	}
+	wait := max(l.global.reserve(now), chat.reserve(now))
+	l.mu.Unlock()
+
+	if wait > 0 {
+		done := metrics.QueueWait()
+		time.Sleep(wait)
+		done()
+	}
+}
@@ -0,0 +1,38 @@
+package handlersTelegramBot
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"telegramBot/stack"
+)
+
+// Как часто проверять, не оставил ли telegram-bot stack up отчет
+const stackReportInterval = 15 * time.Second
+
+// startStackReports отправляет администраторам отчеты telegram-bot stack up.
+// Отчет пишется в DATA_DIR/stack_report.json и удаляется после отправки
+func (h *MessageHandler) startStackReports() {
+	path := filepath.Join(h.Config().DataDir, "stack_report.json")
+
+	go func() {
+		var delivered time.Time
+		for {
+			report, err := stack.ReadReport(path)
+			if err != nil {
+				logger.Error("❌ Отчет запуска стека", "error", err)
+			}
+
+			// Если файл не удалось удалить, тот же отчет не отправляется повторно
+			if report != nil && !report.Finished.Equal(delivered) {
+				h.NotifyAdmin(report.Format())
+				delivered = report.Finished
+				if err := os.Remove(path); err != nil {
+					logger.Warn("⚠️ Не удалось удалить отчет запуска стека", "path", path, "error", err)
+				}
+			}
+			time.Sleep(stackReportInterval)
+		}
+	}()
+}
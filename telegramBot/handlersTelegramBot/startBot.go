@@ -4,11 +4,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
 
+	"telegramBot/backup"
 	"telegramBot/config"
+	"telegramBot/gateway"
+	"telegramBot/logging"
+	"telegramBot/metrics"
 	"telegramBot/models"
+	"telegramBot/scheduler"
 	// "telegramBot/yandexapi/init"
 )
 
@@ -16,6 +23,8 @@ type Bot struct {
 	token   string
 	config  *config.Config
 	handler *MessageHandler
+
+	lastPoll atomic.Int64 // время последнего успешного getUpdates (unix)
 }
 
 func NewBot(config *config.Config) *Bot {
@@ -27,17 +36,20 @@ func NewBot(config *config.Config) *Bot {
 	}
 }
 
+var logger = logging.For("bot")
+
 func (b *Bot) startPolling() {
-	log.Println("🚀 Бот запущен с прямым polling...")
-	log.Printf("📏 Максимальная длина вывода API: %d символов", b.config.MaxLengthAPIOutput)
+	logger.Info("🚀 Бот запущен с прямым polling")
 
 	offset := 0
+	b.lastPoll.Store(time.Now().Unix())
 	for {
 		updates, err := b.getUpdates(offset)
 		if err != nil {
-			log.Printf("❌ Ошибка получения updates: %v", err)
+			telegramLog.Error("❌ Ошибка получения updates", "error", err)
 			continue
 		}
+		b.lastPoll.Store(time.Now().Unix())
 
 		for _, update := range updates {
 			b.handler.HandleUpdate(update)
@@ -49,26 +61,22 @@ func (b *Bot) startPolling() {
 func (b *Bot) getUpdates(offset int) ([]models.Update, error) {
 	url := fmt.Sprintf("https://api.telegram.org/bot%s/getUpdates?offset=%d&timeout=60", b.token, offset)
 
+	started := time.Now()
 	resp, err := http.Get(url)
 	if err != nil {
-		return nil, err
+		metrics.APIRequest("telegram", "getUpdates", 0, time.Since(started))
+		return nil, stripURL(err)
 	}
 	defer resp.Body.Close()
+	metrics.APIRequest("telegram", "getUpdates", resp.StatusCode, time.Since(started))
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
-	// Логируем сырой ответ для отладки
-	if len(body) > 0 {
-		maxLength := b.config.MaxLengthAPIOutput
-		output := string(body)
-		if len(output) > maxLength {
-			output = output[:maxLength] + "..."
-		}
-		log.Printf("📨 Получен ответ от API (%d/%d символов): %s", len(body), maxLength, output)
-	}
+	// Сырой ответ - только для отладки: LOG_LEVELS=telegram=debug
+	telegramLog.Debug("📨 Ответ getUpdates", "bytes", len(body), "body", string(body))
 
 	var response struct {
 		OK     bool            `json:"ok"`
@@ -76,7 +84,7 @@ func (b *Bot) getUpdates(offset int) ([]models.Update, error) {
 	}
 
 	if err := json.Unmarshal(body, &response); err != nil {
-		log.Printf("❌ Ошибка парсинга JSON: %v", err)
+		telegramLog.Error("❌ Ошибка парсинга ответа getUpdates", "error", err)
 		return nil, err
 	}
 
@@ -87,40 +95,91 @@ func (b *Bot) getUpdates(offset int) ([]models.Update, error) {
 	return response.Result, nil
 }
 
-func StartTelegramBot() {
-	log.Println("🔧 Загрузка конфигурации...")
-	config := config.LoadConfig()
-
-	if config.TelegramToken == "" {
-		log.Fatal("❌ TELEGRAM_BOT_TOKEN не установлен")
+func StartTelegramBot(config *config.Config) {
+	err := logging.Setup(logging.Options{Format: config.LogFormat, Debug: config.Debug, Levels: config.LogLevels, Buffer: config.LogBuffer})
+	if err != nil {
+		fatal("❌ Ошибка настройки логов", "error", err)
 	}
+	for _, secret := range config.Secrets() {
+		logging.AddSecret(secret)
+	}
+	logger.Info("🔧 Конфигурация загружена", "profile", config.Profile, "file", config.File, "log_levels", logging.Describe(), "log_format", config.LogFormat)
 
-	log.Println("🤖 Инициализация бота...")
+	logger.Info("🤖 Инициализация бота")
 	bot := NewBot(config)
 
 	// Проверка подключения
-	log.Println("🔌 Проверка подключения к Telegram API...")
+	logger.Info("🔌 Проверка подключения к Telegram API")
 	testURL := fmt.Sprintf("https://api.telegram.org/bot%s/getMe", config.TelegramToken)
 	resp, err := http.Get(testURL)
 	if err != nil {
-		log.Fatalf("❌ Ошибка подключения: %v", err)
+		fatal("❌ Ошибка подключения", "error", stripURL(err))
 	}
 	defer resp.Body.Close()
 
 	var result map[string]interface{}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		log.Fatalf("❌ Ошибка парсинга ответа: %v", err)
+		fatal("❌ Ошибка парсинга ответа", "error", err)
 	}
 
 	if result["ok"].(bool) {
 		botInfo := result["result"].(map[string]interface{})
-		log.Printf("✅ Бот @%s готов к работе!", botInfo["username"])
+		logger.Info("✅ Бот готов к работе", "username", botInfo["username"])
 	} else {
-		log.Fatal("❌ Неверный токен бота")
+		fatal("❌ Неверный токен бота")
 	}
 
 	// yandexinit.InitYandexDisk()
 
-	log.Println("✨ Бот запущен!")
+	bot.handler.startErrorReports()
+
+	if config.Metrics.Listen != "" {
+		bot.startMetrics()
+	}
+
+	bot.handler.Backup = backup.NewService(config, bot.handler.NotifyAdmin)
+	if err := bot.handler.Backup.Start(); err != nil {
+		logger.Error("❌ Ошибка запуска резервного копирования", "error", err)
+	}
+	scheduler.Start()
+
+	bot.handler.StartHostMonitor()
+	bot.handler.StartYandexOAuth()
+	if err := bot.handler.StartDocker(); err != nil {
+		logger.Error("❌ Ошибка подключения к Docker", "error", err)
+	}
+
+	if err := bot.handler.StartMQTT(); err != nil {
+		logger.Error("❌ Ошибка запуска MQTT", "error", err)
+	}
+	if err := bot.handler.StartZigbee(); err != nil {
+		logger.Error("❌ Ошибка запуска Zigbee", "error", err)
+	}
+	if err := bot.handler.StartHomeAssistant(); err != nil {
+		logger.Error("❌ Ошибка запуска Home Assistant", "error", err)
+	}
+	if err := bot.handler.StartEnergy(); err != nil {
+		logger.Error("❌ Ошибка запуска учета электроэнергии", "error", err)
+	}
+	if err := bot.handler.StartLAN(); err != nil {
+		logger.Error("❌ Ошибка загрузки устройств локальной сети", "error", err)
+	}
+
+	if config.Gateway.Token != "" {
+		gateway.NewServer(config.Gateway, bot.handler).Start()
+	} else {
+		logger.Info("ℹ️ NOTIFY_TOKEN не задан, HTTP API уведомлений выключен")
+	}
+
+	bot.handler.startStackReports()
+	bot.handler.startConfigWatch()
+
+	logger.Info("✨ Бот запущен")
 	bot.startPolling()
 }
+
+// fatal записывает ошибку и завершает процесс
+func fatal(msg string, args ...any) {
+	logger.Error(msg, args...)
+	os.Exit(1)
+}
@@ -0,0 +1,224 @@
+package handlersTelegramBot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"telegramBot/logging"
+	"telegramBot/metrics"
+	"telegramBot/models"
+)
+
+// Запросы к Bot API: тексты сообщений и ответы пишутся на уровне debug
+var telegramLog = logging.For("telegram")
+
+// Сколько раз повторять запрос после ответа 429 Too Many Requests
+const maxTelegramRetries = 3
+
+// stripURL убирает из ошибки http-клиента URL с токеном бота: такие ошибки
+// показываются пользователям, а не только пишутся в лог
+func stripURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s: %w", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
+
+// callTelegram вызывает метод Telegram Bot API и возвращает содержимое поля result
+func (h *MessageHandler) callTelegram(method string, params url.Values) (json.RawMessage, error) {
+	return h.postTelegram(method, "application/x-www-form-urlencoded", []byte(params.Encode()))
+}
+
+// postTelegram отправляет тело запроса в метод Bot API. При превышении лимитов
+// Telegram сообщает retry_after - ждем и повторяем запрос.
+func (h *MessageHandler) postTelegram(method string, contentType string, payload []byte) (json.RawMessage, error) {
+	return h.postTelegramAt(slog.LevelError, method, contentType, payload)
+}
+
+// postTelegramAt - postTelegram, который пишет ошибку API с заданным уровнем
+func (h *MessageHandler) postTelegramAt(level slog.Level, method string, contentType string, payload []byte) (json.RawMessage, error) {
+	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/%s", h.Token, method)
+
+	for attempt := 0; ; attempt++ {
+		started := time.Now()
+		resp, err := http.Post(apiURL, contentType, bytes.NewReader(payload))
+		if err != nil {
+			metrics.APIRequest("telegram", method, 0, time.Since(started))
+			return nil, stripURL(err)
+		}
+		metrics.APIRequest("telegram", method, resp.StatusCode, time.Since(started))
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		var response struct {
+			OK          bool            `json:"ok"`
+			Result      json.RawMessage `json:"result"`
+			Description string          `json:"description"`
+			Parameters  struct {
+				RetryAfter int `json:"retry_after"`
+			} `json:"parameters"`
+		}
+		json.Unmarshal(body, &response)
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxTelegramRetries {
+			retryAfter := max(response.Parameters.RetryAfter, 1)
+			telegramLog.Warn("⏳ Лимит запросов Telegram, повтор", "method", method, "retry_after", retryAfter)
+			time.Sleep(time.Duration(retryAfter) * time.Second)
+			continue
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			telegramLog.Log(context.Background(), level, "❌ Ошибка API", "method", method, "status", resp.StatusCode, "body", string(body))
+			return nil, fmt.Errorf("API error: %s - %s", resp.Status, string(body))
+		}
+		if !response.OK {
+			return nil, fmt.Errorf("API error: %s", response.Description)
+		}
+		return response.Result, nil
+	}
+}
+
+func (h *MessageHandler) SendMessage(chatID int64, threadID int, text string) error {
+	_, err := h.sendMessage(chatID, threadID, text, nil)
+	return err
+}
+
+// SendPhoto отправляет изображение с подписью
+func (h *MessageHandler) SendPhoto(chatID int64, threadID int, photo []byte, fileName string, caption string) error {
+	return h.sendFile("sendPhoto", "photo", chatID, threadID, photo, fileName, caption)
+}
+
+// SendDocument отправляет файл документом
+func (h *MessageHandler) SendDocument(chatID int64, threadID int, data []byte, fileName string, caption string) error {
+	return h.sendFile("sendDocument", "document", chatID, threadID, data, fileName, caption)
+}
+
+// sendFile загружает файл в Telegram через multipart/form-data
+func (h *MessageHandler) sendFile(method string, field string, chatID int64, threadID int, data []byte, fileName string, caption string) error {
+	return h.sendFileAt(slog.LevelError, method, field, chatID, threadID, data, fileName, caption)
+}
+
+// sendFileAt - sendFile с уровнем, которым пишется ошибка API
+func (h *MessageHandler) sendFileAt(level slog.Level, method string, field string, chatID int64, threadID int, data []byte, fileName string, caption string) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	writer.WriteField("chat_id", strconv.FormatInt(chatID, 10))
+	if threadID != 0 {
+		writer.WriteField("message_thread_id", strconv.Itoa(threadID))
+	}
+	if caption != "" {
+		writer.WriteField("caption", caption)
+		writer.WriteField("parse_mode", "HTML")
+	}
+
+	part, err := writer.CreateFormFile(field, fileName)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	telegramLog.Debug("📤 Отправка файла", "method", method, "file", fileName, "bytes", len(data), "chat_id", chatID)
+
+	h.limiter.Wait(chatID)
+	if _, err := h.postTelegramAt(level, method, writer.FormDataContentType(), body.Bytes()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SendMessageWithKeyboard отправляет сообщение с inline-кнопками и возвращает его ID
+func (h *MessageHandler) SendMessageWithKeyboard(chatID int64, threadID int, text string, keyboard models.InlineKeyboardMarkup) (int, error) {
+	return h.sendMessage(chatID, threadID, text, &keyboard)
+}
+
+func (h *MessageHandler) sendMessage(chatID int64, threadID int, text string, keyboard *models.InlineKeyboardMarkup) (int, error) {
+	return h.sendMessageAt(slog.LevelError, chatID, threadID, text, keyboard)
+}
+
+// sendMessageAt - sendMessage с уровнем, которым пишется ошибка API
+func (h *MessageHandler) sendMessageAt(level slog.Level, chatID int64, threadID int, text string, keyboard *models.InlineKeyboardMarkup) (int, error) {
+	params := url.Values{}
+	params.Add("chat_id", strconv.FormatInt(chatID, 10))
+	params.Add("text", text)
+	params.Add("parse_mode", "HTML")
+
+	if threadID != 0 {
+		params.Add("message_thread_id", strconv.Itoa(threadID))
+	}
+	telegramLog.Debug("📤 Отправка сообщения", "chat_id", chatID, "thread_id", threadID, "text", text)
+
+	if keyboard != nil {
+		markup, err := json.Marshal(keyboard)
+		if err != nil {
+			return 0, err
+		}
+		params.Add("reply_markup", string(markup))
+	}
+
+	h.limiter.Wait(chatID)
+	result, err := h.postTelegramAt(level, "sendMessage", "application/x-www-form-urlencoded", []byte(params.Encode()))
+	if err != nil {
+		return 0, err
+	}
+
+	var sent models.Message
+	if err := json.Unmarshal(result, &sent); err != nil {
+		return 0, err
+	}
+
+	return sent.MessageID, nil
+}
+
+// EditMessageText заменяет текст сообщения. keyboard == nil убирает кнопки.
+func (h *MessageHandler) EditMessageText(chatID int64, messageID int, text string, keyboard *models.InlineKeyboardMarkup) error {
+	params := url.Values{}
+	params.Add("chat_id", strconv.FormatInt(chatID, 10))
+	params.Add("message_id", strconv.Itoa(messageID))
+	params.Add("text", text)
+	params.Add("parse_mode", "HTML")
+
+	if keyboard != nil {
+		markup, err := json.Marshal(keyboard)
+		if err != nil {
+			return err
+		}
+		params.Add("reply_markup", string(markup))
+	}
+
+	_, err := h.callTelegram("editMessageText", params)
+	return err
+}
+
+// AnswerCallbackQuery подтверждает нажатие кнопки, text показывается всплывающим уведомлением
+func (h *MessageHandler) AnswerCallbackQuery(callbackQueryID string, text string) error {
+	params := url.Values{}
+	params.Add("callback_query_id", callbackQueryID)
+	if text != "" {
+		params.Add("text", text)
+	}
+
+	_, err := h.callTelegram("answerCallbackQuery", params)
+	return err
+}
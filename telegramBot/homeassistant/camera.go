@@ -0,0 +1,18 @@
+package homeassistant
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// CameraSnapshot возвращает текущий кадр камеры через camera_proxy
+func (c *Client) CameraSnapshot(entityID string) ([]byte, error) {
+	data, err := c.do("GET", "/api/camera_proxy/"+url.PathEscape(entityID), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("камера %s вернула пустой кадр", entityID)
+	}
+	return data, nil
+}
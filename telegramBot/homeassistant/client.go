@@ -0,0 +1,143 @@
+package homeassistant
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"telegramBot/config"
+)
+
+const requestTimeout = 15 * time.Second
+
+// APIError - ответ Home Assistant с кодом ошибки
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Home Assistant API %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound сообщает, что сущность или сервис не найдены
+func IsNotFound(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// Client - REST API Home Assistant с авторизацией по долгосрочному токену
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func New(config config.HomeAssistantConfig) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(config.URL, "/"),
+		token:   config.Token,
+		http:    &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Ping проверяет доступность API и валидность токена
+func (c *Client) Ping() error {
+	var response struct {
+		Message string `json:"message"`
+	}
+	return c.request("GET", "/api/", nil, &response)
+}
+
+// States возвращает состояния всех сущностей
+func (c *Client) States() ([]State, error) {
+	var states []State
+	if err := c.request("GET", "/api/states", nil, &states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// State возвращает состояние одной сущности
+func (c *Client) State(entityID string) (*State, error) {
+	var state State
+	if err := c.request("GET", "/api/states/"+url.PathEscape(entityID), nil, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// CallService вызывает сервис domain.service и возвращает изменившиеся сущности
+func (c *Client) CallService(domain string, service string, data map[string]any) ([]State, error) {
+	if data == nil {
+		data = map[string]any{}
+	}
+
+	var changed []State
+	path := fmt.Sprintf("/api/services/%s/%s", url.PathEscape(domain), url.PathEscape(service))
+	if err := c.request("POST", path, data, &changed); err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+// request выполняет запрос к API и декодирует JSON-ответ в out
+func (c *Client) request(method string, path string, body any, out any) error {
+	data, err := c.do(method, path, body)
+	if err != nil {
+		return err
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("ошибка разбора ответа Home Assistant: %v", err)
+	}
+	return nil
+}
+
+// do выполняет запрос и возвращает тело ответа как есть
+func (c *Client) do(method string, path string, body any) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса к Home Assistant: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message := strings.TrimSpace(string(data))
+		if message == "" {
+			message = resp.Status
+		}
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: message}
+	}
+	return data, nil
+}
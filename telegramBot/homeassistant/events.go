@@ -0,0 +1,201 @@
+package homeassistant
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"telegramBot/logging"
+)
+
+var logger = logging.For("ha")
+
+const (
+	reconnectMin = 5 * time.Second
+	reconnectMax = 5 * time.Minute
+	pingInterval = 30 * time.Second
+)
+
+// Event - событие шины Home Assistant
+type Event struct {
+	EventType string          `json:"event_type"`
+	Data      json.RawMessage `json:"data"`
+	TimeFired time.Time       `json:"time_fired"`
+}
+
+// StateChange - данные события state_changed
+type StateChange struct {
+	EntityID string `json:"entity_id"`
+	OldState *State `json:"old_state"`
+	NewState *State `json:"new_state"`
+}
+
+// EventHandler вызывается для каждого полученного события
+type EventHandler func(event Event)
+
+// EventStream - подписка на шину событий через WebSocket API с автоматическим переподключением
+type EventStream struct {
+	url        string
+	token      string
+	eventTypes []string
+
+	mu       sync.RWMutex
+	handlers []EventHandler
+}
+
+// Events создает подписку на события указанных типов
+func (c *Client) Events(eventTypes []string) *EventStream {
+	wsURL := c.baseURL + "/api/websocket"
+	if parsed, err := url.Parse(wsURL); err == nil {
+		switch parsed.Scheme {
+		case "https":
+			parsed.Scheme = "wss"
+		default:
+			parsed.Scheme = "ws"
+		}
+		wsURL = parsed.String()
+	}
+
+	return &EventStream{url: wsURL, token: c.token, eventTypes: eventTypes}
+}
+
+// OnEvent регистрирует обработчик событий
+func (s *EventStream) OnEvent(handler EventHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers = append(s.handlers, handler)
+}
+
+// Start запускает фоновое подключение. При обрыве соединение восстанавливается с нарастающей паузой.
+func (s *EventStream) Start() {
+	go func() {
+		delay := reconnectMin
+		for {
+			connected, err := s.run()
+			if connected {
+				delay = reconnectMin
+			}
+			logger.Warn("⚠️ WebSocket: переподключение", "error", err, "delay", delay)
+			time.Sleep(delay)
+			delay = min(delay*2, reconnectMax)
+		}
+	}()
+}
+
+type wsMessage struct {
+	ID      int             `json:"id"`
+	Type    string          `json:"type"`
+	Success bool            `json:"success"`
+	Event   *Event          `json:"event"`
+	Error   json.RawMessage `json:"error"`
+	Message string          `json:"message"`
+}
+
+// run выполняет одно подключение: авторизацию, подписку и чтение событий до обрыва
+func (s *EventStream) run() (connected bool, err error) {
+	conn, _, err := websocket.DefaultDialer.Dial(s.url, nil)
+	if err != nil {
+		return false, fmt.Errorf("ошибка подключения: %v", err)
+	}
+	defer conn.Close()
+
+	if err := s.authenticate(conn); err != nil {
+		return false, err
+	}
+
+	var writeMu sync.Mutex
+	send := func(message map[string]any) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return conn.WriteJSON(message)
+	}
+
+	id := 0
+	for _, eventType := range s.eventTypes {
+		id++
+		if err := send(map[string]any{"id": id, "type": "subscribe_events", "event_type": eventType}); err != nil {
+			return false, err
+		}
+	}
+	logger.Info("✅ WebSocket: подписка на события", "events", strings.Join(s.eventTypes, ", "))
+
+	// Периодический ping, чтобы вовремя заметить разрыв соединения
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		pingID := 1000000
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				pingID++
+				if err := send(map[string]any{"id": pingID, "type": "ping"}); err != nil {
+					conn.Close()
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(2 * pingInterval))
+
+		var message wsMessage
+		if err := conn.ReadJSON(&message); err != nil {
+			return true, fmt.Errorf("соединение закрыто: %v", err)
+		}
+
+		switch message.Type {
+		case "event":
+			if message.Event != nil {
+				s.dispatch(*message.Event)
+			}
+		case "result":
+			if !message.Success {
+				logger.Error("❌ WebSocket: ошибка команды", "id", message.ID, "error", string(message.Error))
+			}
+		}
+	}
+}
+
+func (s *EventStream) authenticate(conn *websocket.Conn) error {
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	defer conn.SetReadDeadline(time.Time{})
+
+	var message wsMessage
+	if err := conn.ReadJSON(&message); err != nil {
+		return fmt.Errorf("ошибка чтения приветствия: %v", err)
+	}
+	if message.Type != "auth_required" {
+		return fmt.Errorf("неожиданное сообщение %q вместо auth_required", message.Type)
+	}
+
+	if err := conn.WriteJSON(map[string]any{"type": "auth", "access_token": s.token}); err != nil {
+		return err
+	}
+	if err := conn.ReadJSON(&message); err != nil {
+		return fmt.Errorf("ошибка чтения ответа авторизации: %v", err)
+	}
+	if message.Type != "auth_ok" {
+		return fmt.Errorf("ошибка авторизации: %s", message.Message)
+	}
+	return nil
+}
+
+func (s *EventStream) dispatch(event Event) {
+	s.mu.RLock()
+	handlers := s.handlers
+	s.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
@@ -0,0 +1,51 @@
+package homeassistant
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Favorite - скрипт или сцена, запускаемые кнопкой из Telegram
+type Favorite struct {
+	Name     string
+	EntityID string
+}
+
+// ParseFavorites разбирает элементы вида "Название=script.id" или просто "scene.id"
+func ParseFavorites(items []string) ([]Favorite, error) {
+	var favorites []Favorite
+	for _, item := range items {
+		name, entityID, ok := strings.Cut(item, "=")
+		if !ok {
+			name, entityID = "", item
+		}
+		name, entityID = strings.TrimSpace(name), strings.TrimSpace(entityID)
+
+		if !strings.Contains(entityID, ".") {
+			return nil, fmt.Errorf("некорректная сущность в избранном: %q", item)
+		}
+		if name == "" {
+			name = entityID
+		}
+		favorites = append(favorites, Favorite{Name: name, EntityID: entityID})
+	}
+	return favorites, nil
+}
+
+// Run запускает избранное: скрипты и сцены включаются, остальные сущности переключаются
+func (c *Client) Run(favorite Favorite) error {
+	data := map[string]any{"entity_id": favorite.EntityID}
+
+	var err error
+	switch domain := Domain(favorite.EntityID); domain {
+	case "script", "scene":
+		_, err = c.CallService(domain, "turn_on", data)
+	case "automation":
+		_, err = c.CallService(domain, "trigger", data)
+	case "button", "input_button":
+		_, err = c.CallService(domain, "press", data)
+	default:
+		_, err = c.CallService("homeassistant", "toggle", data)
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+package homeassistant
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HistoryState - точка истории: состояние и время его установки
+type HistoryState struct {
+	State       string    `json:"state"`
+	LastChanged time.Time `json:"last_changed"`
+}
+
+// EntityHistory - история одной сущности за период
+type EntityHistory struct {
+	EntityID string
+	Name     string
+	Unit     string
+	States   []HistoryState
+}
+
+// History возвращает историю состояний сущностей за период [start, end]
+func (c *Client) History(entityIDs []string, start time.Time, end time.Time) ([]EntityHistory, error) {
+	query := url.Values{}
+	query.Set("filter_entity_id", strings.Join(entityIDs, ","))
+	query.Set("end_time", end.UTC().Format(time.RFC3339))
+	// minimal_response: атрибуты только у первой записи, этого достаточно для имени и единиц измерения
+	query.Set("minimal_response", "")
+
+	path := fmt.Sprintf("/api/history/period/%s?%s", url.PathEscape(start.UTC().Format(time.RFC3339)), query.Encode())
+
+	var raw [][]State
+	if err := c.request("GET", path, nil, &raw); err != nil {
+		return nil, err
+	}
+
+	var history []EntityHistory
+	for _, states := range raw {
+		if len(states) == 0 {
+			continue
+		}
+		first := states[0]
+		entity := EntityHistory{EntityID: first.EntityID, Name: first.Name(), Unit: first.Unit()}
+		for _, state := range states {
+			entity.States = append(entity.States, HistoryState{State: state.State, LastChanged: state.LastChanged})
+		}
+		history = append(history, entity)
+	}
+	return history, nil
+}
@@ -0,0 +1,272 @@
+package homeassistant
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Rule описывает, какие события пересылать в Telegram и как их оформлять.
+//
+// Пример правила в JSON:
+//
+//	{
+//	  "name": "door",
+//	  "entity": "binary_sensor.*_door",
+//	  "to": "on",
+//	  "chat": "family",
+//	  "template": "🚪 Открыта дверь: <b>{{.Name}}</b>",
+//	  "actions": [{"text": "🔕 1 час", "snooze": "1h"}]
+//	}
+type Rule struct {
+	Name      string       `json:"name"`
+	Event     string       `json:"event,omitempty"`  // тип события (по умолчанию state_changed)
+	Entity    string       `json:"entity,omitempty"` // шаблон entity_id (path.Match), пусто - любая
+	From      string       `json:"from,omitempty"`   // прежнее состояние
+	To        string       `json:"to,omitempty"`     // новое состояние
+	Above     *float64     `json:"above,omitempty"`  // новое числовое состояние больше
+	Below     *float64     `json:"below,omitempty"`  // новое числовое состояние меньше
+	Chat      string       `json:"chat"`             // псевдоним или chat_id[:thread_id]
+	Template  string       `json:"template"`
+	Actions   []RuleAction `json:"actions,omitempty"`
+	Cooldown  Duration     `json:"cooldown,omitempty"`   // минимальный интервал между уведомлениями по одной сущности
+	AdminOnly bool         `json:"admin_only,omitempty"` // кнопки доступны только администраторам
+
+	template *template.Template
+}
+
+// RuleAction - кнопка под уведомлением: вызов сервиса HA или отключение правила на время
+type RuleAction struct {
+	Text    string         `json:"text"`
+	Service string         `json:"service,omitempty"` // домен.сервис
+	Data    map[string]any `json:"data,omitempty"`    // entity_id по умолчанию - сущность события
+	Snooze  Duration       `json:"snooze,omitempty"`
+}
+
+// Duration - time.Duration, записываемая в JSON строкой ("30m", "1h")
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	value, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*d = Duration(value)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+const defaultTemplate = "🏠 <b>{{.Name}}</b>: {{.OldState}} → <b>{{.State}}</b> {{.Unit}}"
+
+// Notification - результат срабатывания правила
+type Notification struct {
+	Rule     *Rule
+	EntityID string
+	Text     string
+}
+
+// TemplateData - данные, доступные в шаблоне сообщения
+type TemplateData struct {
+	Event      string
+	EntityID   string
+	Name       string
+	State      string
+	OldState   string
+	Unit       string
+	Attributes map[string]any
+	Data       map[string]any // данные события (для событий, отличных от state_changed)
+}
+
+// Rules - набор правил с учетом отложенных (snooze) и недавно сработавших уведомлений
+type Rules struct {
+	rules []*Rule
+
+	mu       sync.Mutex
+	snoozed  map[string]time.Time // ключ: правило/сущность
+	lastSent map[string]time.Time
+}
+
+// LoadRules читает правила из JSON-файла. Отсутствующий файл - пустой набор правил.
+func LoadRules(file string) (*Rules, error) {
+	rules := &Rules{snoozed: make(map[string]time.Time), lastSent: make(map[string]time.Time)}
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return rules, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &rules.rules); err != nil {
+		return nil, fmt.Errorf("ошибка чтения %s: %w", file, err)
+	}
+
+	for i, rule := range rules.rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule%d", i+1)
+		}
+		if rule.Event == "" {
+			rule.Event = "state_changed"
+		}
+		if rule.Chat == "" {
+			return nil, fmt.Errorf("правило %s: не указан chat", rule.Name)
+		}
+		if rule.Entity != "" {
+			if _, err := path.Match(rule.Entity, ""); err != nil {
+				return nil, fmt.Errorf("правило %s: некорректный шаблон entity: %v", rule.Name, err)
+			}
+		}
+		if rule.Template == "" {
+			rule.Template = defaultTemplate
+		}
+		if rule.template, err = template.New(rule.Name).Parse(rule.Template); err != nil {
+			return nil, fmt.Errorf("правило %s: ошибка шаблона: %v", rule.Name, err)
+		}
+	}
+	return rules, nil
+}
+
+// List возвращает правила
+func (r *Rules) List() []*Rule {
+	return r.rules
+}
+
+// EventTypes возвращает типы событий, на которые нужно подписаться
+func (r *Rules) EventTypes() []string {
+	var types []string
+	seen := make(map[string]bool)
+	for _, rule := range r.rules {
+		if !seen[rule.Event] {
+			seen[rule.Event] = true
+			types = append(types, rule.Event)
+		}
+	}
+	return types
+}
+
+// Match проверяет событие по всем правилам и возвращает уведомления для отправки
+func (r *Rules) Match(event Event) []Notification {
+	data, ok := newTemplateData(event)
+	if !ok {
+		return nil
+	}
+
+	var notifications []Notification
+	for _, rule := range r.rules {
+		if !rule.matches(data) || !r.allow(rule, data.EntityID) {
+			continue
+		}
+
+		var text bytes.Buffer
+		if err := rule.template.Execute(&text, data); err != nil {
+			text.Reset()
+			fmt.Fprintf(&text, "⚠️ Ошибка шаблона правила %s: %s", template.HTMLEscapeString(rule.Name), template.HTMLEscapeString(err.Error()))
+		}
+		notifications = append(notifications, Notification{Rule: rule, EntityID: data.EntityID, Text: text.String()})
+	}
+	return notifications
+}
+
+// Snooze отключает уведомления правила по сущности на указанное время
+func (r *Rules) Snooze(rule *Rule, entityID string, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.snoozed[rule.Name+"/"+entityID] = time.Now().Add(duration)
+}
+
+// allow учитывает snooze и cooldown и отмечает время отправки
+func (r *Rules) allow(rule *Rule, entityID string) bool {
+	key := rule.Name + "/" + entityID
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if until, ok := r.snoozed[key]; ok {
+		if now.Before(until) {
+			return false
+		}
+		delete(r.snoozed, key)
+	}
+	if last, ok := r.lastSent[key]; ok && now.Sub(last) < time.Duration(rule.Cooldown) {
+		return false
+	}
+	r.lastSent[key] = now
+	return true
+}
+
+func (rule *Rule) matches(data TemplateData) bool {
+	if rule.Event != data.Event {
+		return false
+	}
+	if rule.Entity != "" {
+		if ok, _ := path.Match(rule.Entity, data.EntityID); !ok {
+			return false
+		}
+	}
+	if rule.From != "" && rule.From != data.OldState {
+		return false
+	}
+	if rule.To != "" && rule.To != data.State {
+		return false
+	}
+
+	if rule.Above != nil || rule.Below != nil {
+		value, err := strconv.ParseFloat(data.State, 64)
+		if err != nil {
+			return false
+		}
+		if rule.Above != nil && value <= *rule.Above {
+			return false
+		}
+		if rule.Below != nil && value >= *rule.Below {
+			return false
+		}
+	}
+	return true
+}
+
+// newTemplateData извлекает из события данные для условий и шаблона.
+// Для state_changed игнорируются обновления атрибутов без смены состояния.
+func newTemplateData(event Event) (TemplateData, bool) {
+	data := TemplateData{Event: event.EventType}
+
+	if event.EventType != "state_changed" {
+		json.Unmarshal(event.Data, &data.Data)
+		data.EntityID, _ = data.Data["entity_id"].(string)
+		return data, true
+	}
+
+	var change StateChange
+	if err := json.Unmarshal(event.Data, &change); err != nil || change.NewState == nil {
+		return data, false
+	}
+	if change.OldState != nil {
+		if change.OldState.State == change.NewState.State {
+			return data, false
+		}
+		data.OldState = change.OldState.State
+	}
+
+	data.EntityID = change.EntityID
+	data.Name = change.NewState.Name()
+	data.State = change.NewState.State
+	data.Unit = change.NewState.Unit()
+	data.Attributes = change.NewState.Attributes
+	return data, true
+}
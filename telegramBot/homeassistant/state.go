@@ -0,0 +1,59 @@
+package homeassistant
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// State - состояние сущности Home Assistant
+type State struct {
+	EntityID    string         `json:"entity_id"`
+	State       string         `json:"state"`
+	Attributes  map[string]any `json:"attributes"`
+	LastChanged time.Time      `json:"last_changed"`
+	LastUpdated time.Time      `json:"last_updated"`
+}
+
+// Domain возвращает домен сущности (light, switch, sensor...)
+func (s State) Domain() string {
+	return Domain(s.EntityID)
+}
+
+// Name возвращает friendly_name или entity_id, если имя не задано
+func (s State) Name() string {
+	if name, ok := s.Attributes["friendly_name"].(string); ok && name != "" {
+		return name
+	}
+	return s.EntityID
+}
+
+// Unit возвращает единицу измерения сенсора
+func (s State) Unit() string {
+	unit, _ := s.Attributes["unit_of_measurement"].(string)
+	return unit
+}
+
+// Value возвращает состояние вместе с единицей измерения
+func (s State) Value() string {
+	if unit := s.Unit(); unit != "" {
+		return fmt.Sprintf("%s %s", s.State, unit)
+	}
+	return s.State
+}
+
+// Matches проверяет, содержит ли entity_id или имя подстроку filter (без учета регистра)
+func (s State) Matches(filter string) bool {
+	if filter == "" {
+		return true
+	}
+	filter = strings.ToLower(filter)
+	return strings.Contains(strings.ToLower(s.EntityID), filter) ||
+		strings.Contains(strings.ToLower(s.Name()), filter)
+}
+
+// Domain возвращает часть entity_id до точки
+func Domain(entityID string) string {
+	domain, _, _ := strings.Cut(entityID, ".")
+	return domain
+}
@@ -0,0 +1,15 @@
+package hostinfo
+
+import "syscall"
+
+func diskUsage(path string) (Disk, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return Disk{}, err
+	}
+	return Disk{
+		Path:  path,
+		Total: stat.Blocks * uint64(stat.Bsize),
+		Free:  stat.Bavail * uint64(stat.Bsize),
+	}, nil
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package hostinfo
+
+import "errors"
+
+func diskUsage(path string) (Disk, error) {
+	return Disk{}, errors.New("использование диска поддерживается только в Linux")
+}
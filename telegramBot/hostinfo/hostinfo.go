@@ -0,0 +1,257 @@
+package hostinfo
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Пути можно переопределить, если /proc и /sys хоста смонтированы в контейнер в другое место
+type Paths struct {
+	Proc string
+	Sys  string
+}
+
+// Snapshot - состояние хоста в момент измерения
+type Snapshot struct {
+	Load1, Load5, Load15 float64
+	CPUs                 int
+	CPUUsage             float64 // %, по /proc/stat за короткий интервал
+
+	Temperatures []Temperature
+
+	MemTotal     uint64 // байты
+	MemAvailable uint64
+	SwapTotal    uint64
+	SwapFree     uint64
+
+	Disks      []Disk
+	Uptime     time.Duration
+	Interfaces []Interface
+}
+
+// Temperature - показание термозоны
+type Temperature struct {
+	Zone    string
+	Celsius float64
+}
+
+// Disk - использование файловой системы
+type Disk struct {
+	Path  string
+	Total uint64
+	Free  uint64
+}
+
+// UsedPercent возвращает процент занятого места
+func (d Disk) UsedPercent() float64 {
+	if d.Total == 0 {
+		return 0
+	}
+	return float64(d.Total-d.Free) / float64(d.Total) * 100
+}
+
+// Interface - статистика сетевого интерфейса
+type Interface struct {
+	Name     string
+	Up       bool
+	RxBytes  uint64
+	TxBytes  uint64
+	RxErrors uint64
+	TxErrors uint64
+}
+
+// MaxTemperature возвращает самую высокую температуру среди термозон
+func (s Snapshot) MaxTemperature() (Temperature, bool) {
+	if len(s.Temperatures) == 0 {
+		return Temperature{}, false
+	}
+	hottest := s.Temperatures[0]
+	for _, temperature := range s.Temperatures[1:] {
+		if temperature.Celsius > hottest.Celsius {
+			hottest = temperature
+		}
+	}
+	return hottest, true
+}
+
+// Collect собирает состояние хоста. Ошибки отдельных источников не прерывают сбор:
+// недоступные показатели остаются пустыми.
+func Collect(paths Paths, diskPaths []string) Snapshot {
+	snapshot := Snapshot{CPUs: runtime.NumCPU()}
+
+	if fields, err := readFields(filepath.Join(paths.Proc, "loadavg")); err == nil && len(fields) >= 3 {
+		snapshot.Load1, _ = strconv.ParseFloat(fields[0], 64)
+		snapshot.Load5, _ = strconv.ParseFloat(fields[1], 64)
+		snapshot.Load15, _ = strconv.ParseFloat(fields[2], 64)
+	}
+
+	snapshot.CPUUsage = cpuUsage(paths, 250*time.Millisecond)
+	snapshot.Temperatures = temperatures(paths)
+
+	if meminfo, err := readMeminfo(filepath.Join(paths.Proc, "meminfo")); err == nil {
+		snapshot.MemTotal = meminfo["MemTotal"]
+		snapshot.MemAvailable = meminfo["MemAvailable"]
+		snapshot.SwapTotal = meminfo["SwapTotal"]
+		snapshot.SwapFree = meminfo["SwapFree"]
+	}
+
+	for _, path := range diskPaths {
+		if disk, err := diskUsage(path); err == nil {
+			snapshot.Disks = append(snapshot.Disks, disk)
+		}
+	}
+
+	if fields, err := readFields(filepath.Join(paths.Proc, "uptime")); err == nil && len(fields) > 0 {
+		if seconds, err := strconv.ParseFloat(fields[0], 64); err == nil {
+			snapshot.Uptime = time.Duration(seconds) * time.Second
+		}
+	}
+
+	snapshot.Interfaces = interfaces(paths)
+	return snapshot
+}
+
+func readFields(file string) ([]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(data)), nil
+}
+
+func readUint(file string) uint64 {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return 0
+	}
+	value, _ := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	return value
+}
+
+// readMeminfo возвращает значения /proc/meminfo в байтах
+func readMeminfo(file string) (map[string]uint64, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 1 && fields[1] == "kB" {
+			value *= 1024
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
+
+// cpuUsage измеряет загрузку CPU по двум снимкам /proc/stat
+func cpuUsage(paths Paths, interval time.Duration) float64 {
+	idle1, total1, err := cpuTimes(paths)
+	if err != nil {
+		return 0
+	}
+	time.Sleep(interval)
+	idle2, total2, err := cpuTimes(paths)
+	if err != nil || total2 <= total1 {
+		return 0
+	}
+	return (1 - float64(idle2-idle1)/float64(total2-total1)) * 100
+}
+
+func cpuTimes(paths Paths) (idle uint64, total uint64, err error) {
+	f, err := os.Open(filepath.Join(paths.Proc, "stat"))
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return 0, 0, fmt.Errorf("пустой /proc/stat")
+	}
+	fields := strings.Fields(scanner.Text())
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return 0, 0, fmt.Errorf("неожиданный формат /proc/stat")
+	}
+
+	for i, field := range fields[1:] {
+		value, _ := strconv.ParseUint(field, 10, 64)
+		total += value
+		// idle и iowait
+		if i == 3 || i == 4 {
+			idle += value
+		}
+	}
+	return idle, total, nil
+}
+
+func temperatures(paths Paths) []Temperature {
+	zones, _ := filepath.Glob(filepath.Join(paths.Sys, "class/thermal/thermal_zone*"))
+
+	var result []Temperature
+	for _, zone := range zones {
+		data, err := os.ReadFile(filepath.Join(zone, "temp"))
+		if err != nil {
+			continue
+		}
+		milli, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+		if err != nil {
+			continue
+		}
+
+		name := filepath.Base(zone)
+		if zoneType, err := os.ReadFile(filepath.Join(zone, "type")); err == nil {
+			name = strings.TrimSpace(string(zoneType))
+		}
+		result = append(result, Temperature{Zone: name, Celsius: milli / 1000})
+	}
+	return result
+}
+
+func interfaces(paths Paths) []Interface {
+	dirs, _ := filepath.Glob(filepath.Join(paths.Sys, "class/net/*"))
+
+	var result []Interface
+	for _, dir := range dirs {
+		name := filepath.Base(dir)
+		if name == "lo" || strings.HasPrefix(name, "veth") {
+			continue
+		}
+
+		operstate, _ := os.ReadFile(filepath.Join(dir, "operstate"))
+		statistics := filepath.Join(dir, "statistics")
+		result = append(result, Interface{
+			Name:     name,
+			Up:       strings.TrimSpace(string(operstate)) == "up",
+			RxBytes:  readUint(filepath.Join(statistics, "rx_bytes")),
+			TxBytes:  readUint(filepath.Join(statistics, "tx_bytes")),
+			RxErrors: readUint(filepath.Join(statistics, "rx_errors")),
+			TxErrors: readUint(filepath.Join(statistics, "tx_errors")),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
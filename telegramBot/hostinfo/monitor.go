@@ -0,0 +1,174 @@
+package hostinfo
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"telegramBot/alerting"
+	"telegramBot/config"
+	"telegramBot/logging"
+	"telegramBot/yandexapi"
+)
+
+var logger = logging.For("host")
+
+// Monitor периодически проверяет перегрев, свободное место и длительную высокую нагрузку
+type Monitor struct {
+	config atomic.Pointer[config.HostConfig] // пороги меняются через Reconfigure
+	paths  Paths
+	alerts *alerting.Tracker
+
+	mu            sync.Mutex
+	highLoadSince time.Time // начало текущего периода высокой нагрузки
+}
+
+func NewMonitor(config config.HostConfig, notify func(text string)) *Monitor {
+	m := &Monitor{
+		paths:  Paths{Proc: config.ProcPath, Sys: config.SysPath},
+		alerts: alerting.NewTracker(notify),
+	}
+	m.config.Store(&config)
+	return m
+}
+
+// Reconfigure применяет новые пороги и список дисков без перезапуска. Пути /proc и /sys и интервал проверок не меняются
+func (m *Monitor) Reconfigure(config config.HostConfig) {
+	m.config.Store(&config)
+}
+
+// Start запускает проверки в фоне
+func (m *Monitor) Start() {
+	config := m.config.Load()
+	logger.Info("🩺 Мониторинг сервера", "interval", config.MonitorInterval)
+
+	go func() {
+		ticker := time.NewTicker(config.MonitorInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			m.check(m.Collect())
+		}
+	}()
+}
+
+// Collect снимает текущее состояние хоста
+func (m *Monitor) Collect() Snapshot {
+	config := m.config.Load()
+	return Collect(m.paths, config.DiskPaths)
+}
+
+func (m *Monitor) check(snapshot Snapshot) {
+	config := m.config.Load()
+	// Температура: оповещение выше порога, снятие - ниже порога на величину гистерезиса
+	if hottest, ok := snapshot.MaxTemperature(); ok {
+		switch {
+		case hottest.Celsius >= config.TempHigh:
+			m.alerts.Raise("temperature", fmt.Sprintf("Перегрев: <b>%.1f °C</b> (%s)", hottest.Celsius, html.EscapeString(hottest.Zone)))
+		case hottest.Celsius < config.TempHigh-config.TempHysteresis:
+			m.alerts.Clear("temperature", fmt.Sprintf("Температура в норме: <b>%.1f °C</b>", hottest.Celsius))
+		}
+	}
+
+	for _, disk := range snapshot.Disks {
+		key := "disk:" + disk.Path
+		freePercent := 100 - disk.UsedPercent()
+		path := html.EscapeString(disk.Path)
+		switch {
+		case freePercent < config.DiskFreeLow:
+			m.alerts.Raise(key, fmt.Sprintf("Мало места на <code>%s</code>: свободно <b>%s</b> (%.0f%%)", path, yandexapi.FormatBytes(int64(disk.Free)), freePercent))
+		case freePercent >= config.DiskFreeLow+config.DiskHysteresis:
+			m.alerts.Clear(key, fmt.Sprintf("Место на <code>%s</code> освободилось: <b>%s</b> (%.0f%%)", path, yandexapi.FormatBytes(int64(disk.Free)), freePercent))
+		}
+	}
+
+	// Нагрузка: оповещаем, только если load5 на ядро держится выше порога дольше LoadDuration
+	loadPerCPU := snapshot.Load5 / float64(max(snapshot.CPUs, 1))
+	m.mu.Lock()
+	switch {
+	case loadPerCPU >= config.LoadHigh:
+		if m.highLoadSince.IsZero() {
+			m.highLoadSince = time.Now()
+		}
+		if time.Since(m.highLoadSince) >= config.LoadDuration {
+			m.alerts.Raise("load", fmt.Sprintf("Высокая нагрузка уже %s: load average <b>%.2f %.2f %.2f</b> на %d ядрах",
+				time.Since(m.highLoadSince).Round(time.Minute), snapshot.Load1, snapshot.Load5, snapshot.Load15, snapshot.CPUs))
+		}
+	case loadPerCPU < config.LoadHigh*0.8:
+		m.highLoadSince = time.Time{}
+		m.alerts.Clear("load", fmt.Sprintf("Нагрузка снизилась: load average <b>%.2f %.2f %.2f</b>", snapshot.Load1, snapshot.Load5, snapshot.Load15))
+	}
+	m.mu.Unlock()
+}
+
+// Report возвращает сводку о состоянии сервера для /server
+func (m *Monitor) Report() string {
+	config := m.config.Load()
+	snapshot := m.Collect()
+
+	var builder strings.Builder
+	fmt.Fprintln(&builder, "🖥️ <b>Сервер</b>")
+	fmt.Fprintln(&builder, strings.Repeat("─", 20))
+	fmt.Fprintf(&builder, "⏱️ Аптайм: <b>%s</b>\n", formatUptime(snapshot.Uptime))
+	fmt.Fprintf(&builder, "⚙️ CPU: <b>%.0f%%</b> · load <b>%.2f %.2f %.2f</b> (%d ядер)\n",
+		snapshot.CPUUsage, snapshot.Load1, snapshot.Load5, snapshot.Load15, snapshot.CPUs)
+
+	for _, temperature := range snapshot.Temperatures {
+		icon := "🌡️"
+		if temperature.Celsius >= config.TempHigh {
+			icon = "🔥"
+		}
+		fmt.Fprintf(&builder, "%s %s: <b>%.1f °C</b>\n", icon, html.EscapeString(temperature.Zone), temperature.Celsius)
+	}
+
+	if snapshot.MemTotal > 0 {
+		used := snapshot.MemTotal - snapshot.MemAvailable
+		fmt.Fprintf(&builder, "🧠 Память: <b>%s</b> из %s (%.0f%%)\n", yandexapi.FormatBytes(int64(used)),
+			yandexapi.FormatBytes(int64(snapshot.MemTotal)), float64(used)/float64(snapshot.MemTotal)*100)
+	}
+	if snapshot.SwapTotal > 0 {
+		fmt.Fprintf(&builder, "💱 Swap: <b>%s</b> из %s\n", yandexapi.FormatBytes(int64(snapshot.SwapTotal-snapshot.SwapFree)),
+			yandexapi.FormatBytes(int64(snapshot.SwapTotal)))
+	}
+
+	for _, disk := range snapshot.Disks {
+		fmt.Fprintf(&builder, "💾 <code>%s</code>: свободно <b>%s</b> из %s (занято %.0f%%)\n", html.EscapeString(disk.Path),
+			yandexapi.FormatBytes(int64(disk.Free)), yandexapi.FormatBytes(int64(disk.Total)), disk.UsedPercent())
+	}
+
+	if len(snapshot.Interfaces) > 0 {
+		fmt.Fprintln(&builder, "\n🌐 <b>Сеть:</b>")
+		for _, iface := range snapshot.Interfaces {
+			status := "🟢"
+			if !iface.Up {
+				status = "⚪"
+			}
+			fmt.Fprintf(&builder, "%s %s: ⬇️ %s ⬆️ %s", status, html.EscapeString(iface.Name),
+				yandexapi.FormatBytes(int64(iface.RxBytes)), yandexapi.FormatBytes(int64(iface.TxBytes)))
+			if errors := iface.RxErrors + iface.TxErrors; errors > 0 {
+				fmt.Fprintf(&builder, " ⚠️ ошибок: %d", errors)
+			}
+			fmt.Fprintln(&builder)
+		}
+	}
+
+	if alerts := m.alerts.Active(); len(alerts) > 0 {
+		fmt.Fprintln(&builder, "\n🚨 <b>Активные оповещения:</b>")
+		for _, alert := range alerts {
+			fmt.Fprintf(&builder, "• %s (с %s)\n", alert.Message, alert.Since.Format("02.01 15:04"))
+		}
+	}
+	return builder.String()
+}
+
+func formatUptime(uptime time.Duration) string {
+	days := int(uptime.Hours()) / 24
+	hours := int(uptime.Hours()) % 24
+	minutes := int(uptime.Minutes()) % 60
+	if days > 0 {
+		return fmt.Sprintf("%d дн. %d ч %d мин", days, hours, minutes)
+	}
+	return fmt.Sprintf("%d ч %d мин", hours, minutes)
+}
@@ -0,0 +1,52 @@
+package lan
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Признак завершенной записи в /proc/net/arp (ATF_COM)
+const arpComplete = 0x2
+
+// Neighbor - запись таблицы соседей
+type Neighbor struct {
+	IP        string
+	MAC       string
+	Interface string
+}
+
+// ReadARP читает /proc/net/arp и возвращает завершенные записи по MAC-адресу:
+//
+//	IP address       HW type     Flags       HW address            Mask     Device
+//	192.168.1.1      0x1         0x2         aa:bb:cc:dd:ee:ff     *        eth0
+func ReadARP(path string) (map[string]Neighbor, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	neighbors := make(map[string]Neighbor)
+	scanner := bufio.NewScanner(file)
+	scanner.Scan() // заголовок
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 {
+			continue
+		}
+
+		flags, err := strconv.ParseInt(fields[2], 0, 64)
+		if err != nil || flags&arpComplete == 0 {
+			continue
+		}
+		mac, err := net.ParseMAC(fields[3])
+		if err != nil {
+			continue
+		}
+		neighbors[mac.String()] = Neighbor{IP: fields[0], MAC: mac.String(), Interface: fields[5]}
+	}
+	return neighbors, scanner.Err()
+}
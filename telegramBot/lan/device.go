@@ -0,0 +1,94 @@
+package lan
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// Device - известное устройство в локальной сети
+type Device struct {
+	Name      string   `json:"name"`
+	MAC       string   `json:"mac"`
+	IP        string   `json:"ip,omitempty"`        // если не задан, берется из таблицы соседей
+	Broadcast string   `json:"broadcast,omitempty"` // адрес для magic packet, например 192.168.1.255:9
+	SecureOn  string   `json:"secureon,omitempty"`  // пароль SecureOn: 6 байт как MAC или 4 байта как IPv4
+	Probes    []string `json:"probes,omitempty"`    // "icmp", "tcp:445"
+	Wake      bool     `json:"wake,omitempty"`      // доступно для /wake
+	Presence  bool     `json:"presence,omitempty"`  // уведомлять о приходе и уходе
+	AdminOnly bool     `json:"admin_only,omitempty"`
+
+	hardwareAddr net.HardwareAddr
+	password     []byte
+}
+
+// HardwareAddr возвращает разобранный MAC-адрес
+func (d *Device) HardwareAddr() net.HardwareAddr {
+	return d.hardwareAddr
+}
+
+// LoadDevices читает список устройств; если файла нет, список пуст
+func LoadDevices(path string) ([]*Device, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []*Device
+	if err := json.Unmarshal(data, &devices); err != nil {
+		return nil, fmt.Errorf("ошибка разбора %s: %v", path, err)
+	}
+
+	names := make(map[string]bool)
+	for _, device := range devices {
+		if device.Name == "" {
+			return nil, fmt.Errorf("%s: устройство без имени", path)
+		}
+		key := strings.ToLower(device.Name)
+		if names[key] {
+			return nil, fmt.Errorf("%s: устройство %s описано дважды", path, device.Name)
+		}
+		names[key] = true
+
+		if device.hardwareAddr, err = net.ParseMAC(device.MAC); err != nil || len(device.hardwareAddr) != 6 {
+			return nil, fmt.Errorf("устройство %s: некорректный MAC %q", device.Name, device.MAC)
+		}
+		if device.SecureOn != "" {
+			if device.password, err = parseSecureOn(device.SecureOn); err != nil {
+				return nil, fmt.Errorf("устройство %s: %v", device.Name, err)
+			}
+		}
+		for _, probe := range device.Probes {
+			if err := validateProbe(probe); err != nil {
+				return nil, fmt.Errorf("устройство %s: %v", device.Name, err)
+			}
+		}
+	}
+	return devices, nil
+}
+
+// Find ищет устройство по имени без учета регистра
+func Find(devices []*Device, name string) (*Device, bool) {
+	for _, device := range devices {
+		if strings.EqualFold(device.Name, name) {
+			return device, true
+		}
+	}
+	return nil, false
+}
+
+// parseSecureOn принимает пароль в виде "aa:bb:cc:dd:ee:ff" или "192.168.0.1"
+func parseSecureOn(value string) ([]byte, error) {
+	if mac, err := net.ParseMAC(value); err == nil && len(mac) == 6 {
+		return mac, nil
+	}
+	if ip := net.ParseIP(value).To4(); ip != nil {
+		return ip, nil
+	}
+	return nil, fmt.Errorf("некорректный пароль SecureOn %q", value)
+}
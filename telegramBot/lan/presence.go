@@ -0,0 +1,148 @@
+package lan
+
+import (
+	"fmt"
+	"html"
+	"sync"
+	"time"
+
+	"telegramBot/logging"
+)
+
+var logger = logging.For("lan")
+
+// Status - результат проверки устройства
+type Status struct {
+	Device  *Device
+	IP      string
+	Present bool
+	Via     string // чем подтверждено присутствие: arp, icmp, tcp:порт
+}
+
+// Check определяет, какие устройства сейчас в сети. Если у устройства заданы
+// проверки, решают они (запись ARP может устареть), иначе - таблица соседей
+func Check(devices []*Device, arpPath string, timeout time.Duration) ([]Status, error) {
+	neighbors, err := ReadARP(arpPath)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(devices))
+	var wg sync.WaitGroup
+	for i, device := range devices {
+		neighbor, inARP := neighbors[device.hardwareAddr.String()]
+		status := Status{Device: device, IP: device.IP}
+		if status.IP == "" {
+			status.IP = neighbor.IP
+		}
+
+		if len(device.Probes) == 0 || status.IP == "" {
+			status.Present = inARP
+			if inARP {
+				status.Via = "arp"
+			}
+			statuses[i] = status
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, probe := range device.Probes {
+				if Probe(status.IP, probe, timeout) == nil {
+					status.Present = true
+					status.Via = probe
+					break
+				}
+			}
+			statuses[i] = status
+		}()
+	}
+	wg.Wait()
+	return statuses, nil
+}
+
+// Monitor уведомляет о появлении и уходе устройств с флагом presence
+type Monitor struct {
+	devices   []*Device
+	arpPath   string
+	timeout   time.Duration
+	awayAfter time.Duration
+	notify    func(text string)
+
+	mu       sync.Mutex
+	home     map[string]bool
+	lastSeen map[string]time.Time
+}
+
+func NewMonitor(devices []*Device, arpPath string, timeout time.Duration, awayAfter time.Duration, notify func(text string)) *Monitor {
+	var watched []*Device
+	for _, device := range devices {
+		if device.Presence {
+			watched = append(watched, device)
+		}
+	}
+	return &Monitor{
+		devices:   watched,
+		arpPath:   arpPath,
+		timeout:   timeout,
+		awayAfter: awayAfter,
+		notify:    notify,
+		home:      make(map[string]bool),
+		lastSeen:  make(map[string]time.Time),
+	}
+}
+
+// Watched возвращает число отслеживаемых устройств
+func (m *Monitor) Watched() int {
+	return len(m.devices)
+}
+
+// Start запускает проверки в фоне. Первая проверка только запоминает состояние
+func (m *Monitor) Start(interval time.Duration) {
+	go func() {
+		m.check(true)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			m.check(false)
+		}
+	}()
+}
+
+func (m *Monitor) check(initial bool) {
+	statuses, err := Check(m.devices, m.arpPath, m.timeout)
+	if err != nil {
+		logger.Error("❌ Проверка присутствия", "error", err)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for _, status := range statuses {
+		name := status.Device.Name
+		escaped := html.EscapeString(name)
+
+		if status.Present {
+			m.lastSeen[name] = now
+			if !m.home[name] {
+				m.home[name] = true
+				if !initial {
+					logger.Info("🏠 В сети", "device", name, "via", status.Via)
+					m.notify(fmt.Sprintf("🏠 <b>%s</b> в сети", escaped))
+				}
+			}
+			continue
+		}
+
+		// Телефоны засыпают и пропадают из сети, поэтому уход фиксируется с задержкой
+		if initial || !m.home[name] || now.Sub(m.lastSeen[name]) < m.awayAfter {
+			continue
+		}
+		m.home[name] = false
+		logger.Info("🚪 Не в сети", "device", name)
+		m.notify(fmt.Sprintf("🚪 <b>%s</b> не в сети (последний раз %s)", escaped, m.lastSeen[name].Format("15:04")))
+	}
+}
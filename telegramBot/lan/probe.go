@@ -0,0 +1,103 @@
+package lan
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+var echoSeq atomic.Uint32
+
+func validateProbe(probe string) error {
+	if probe == "icmp" {
+		return nil
+	}
+	if port, ok := strings.CutPrefix(probe, "tcp:"); ok {
+		if n, err := strconv.Atoi(port); err == nil && n > 0 && n < 65536 {
+			return nil
+		}
+	}
+	return fmt.Errorf("некорректная проверка %q (ожидается icmp или tcp:порт)", probe)
+}
+
+// Probe проверяет доступность адреса: "icmp" - эхо-запрос, "tcp:порт" - подключение к порту
+func Probe(ip string, probe string, timeout time.Duration) error {
+	if port, ok := strings.CutPrefix(probe, "tcp:"); ok {
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(ip, port), timeout)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+	return ping(ip, timeout)
+}
+
+// ping отправляет ICMP echo. Сначала пробует непривилегированный сокет
+// (net.ipv4.ping_group_range), затем raw-сокет (нужен CAP_NET_RAW)
+func ping(ip string, timeout time.Duration) error {
+	target := net.ParseIP(ip).To4()
+	if target == nil {
+		return fmt.Errorf("некорректный IPv4-адрес %s", ip)
+	}
+
+	var destination net.Addr = &net.UDPAddr{IP: target}
+	conn, err := icmp.ListenPacket("udp4", "0.0.0.0")
+	if err != nil {
+		destination = &net.IPAddr{IP: target}
+		if conn, err = icmp.ListenPacket("ip4:icmp", "0.0.0.0"); err != nil {
+			return fmt.Errorf("ICMP недоступен: %v", err)
+		}
+	}
+	defer conn.Close()
+
+	seq := int(echoSeq.Add(1) & 0xffff)
+	request := icmp.Message{
+		Type: ipv4.ICMPTypeEcho,
+		Body: &icmp.Echo{ID: os.Getpid() & 0xffff, Seq: seq, Data: []byte("telegram-bot")},
+	}
+	data, err := request.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := conn.WriteTo(data, destination); err != nil {
+		return err
+	}
+
+	reply := make([]byte, 1500)
+	for {
+		n, peer, err := conn.ReadFrom(reply)
+		if err != nil {
+			return err
+		}
+		if !peerIP(peer).Equal(target) {
+			continue
+		}
+		message, err := icmp.ParseMessage(1, reply[:n])
+		if err != nil || message.Type != ipv4.ICMPTypeEchoReply {
+			continue
+		}
+		// Для непривилегированного сокета ID подменяет ядро, поэтому сверяем только Seq
+		if echo, ok := message.Body.(*icmp.Echo); ok && echo.Seq == seq {
+			return nil
+		}
+	}
+}
+
+func peerIP(addr net.Addr) net.IP {
+	switch addr := addr.(type) {
+	case *net.UDPAddr:
+		return addr.IP
+	case *net.IPAddr:
+		return addr.IP
+	}
+	return nil
+}
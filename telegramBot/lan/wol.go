@@ -0,0 +1,44 @@
+package lan
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+)
+
+// MagicPacket: 6 байт 0xFF, 16 повторов MAC и необязательный пароль SecureOn
+func MagicPacket(mac net.HardwareAddr, password []byte) []byte {
+	packet := bytes.Repeat([]byte{0xff}, 6)
+	for range 16 {
+		packet = append(packet, mac...)
+	}
+	return append(packet, password...)
+}
+
+// Wake отправляет magic packet на широковещательный адрес устройства или defaultBroadcast
+func Wake(device *Device, defaultBroadcast string) error {
+	broadcast := device.Broadcast
+	if broadcast == "" {
+		broadcast = defaultBroadcast
+	}
+	if _, _, err := net.SplitHostPort(broadcast); err != nil {
+		broadcast = net.JoinHostPort(broadcast, "9")
+	}
+
+	address, err := net.ResolveUDPAddr("udp4", broadcast)
+	if err != nil {
+		return fmt.Errorf("некорректный адрес %s: %v", broadcast, err)
+	}
+
+	// UDP-сокеты в Go создаются с SO_BROADCAST
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.WriteToUDP(MagicPacket(device.hardwareAddr, device.password), address); err != nil {
+		return fmt.Errorf("ошибка отправки на %s: %v", broadcast, err)
+	}
+	return nil
+}
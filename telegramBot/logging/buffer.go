@@ -0,0 +1,145 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Сколько последних записей хранится в памяти по умолчанию
+const defaultBufferSize = 1000
+
+// Entry - запись лога в буфере. Секреты в ней уже замаскированы
+type Entry struct {
+	Time      time.Time
+	Level     slog.Level
+	Component string
+	Message   string
+	Attrs     []slog.Attr
+}
+
+// Attr возвращает значение атрибута или пустую строку
+func (e Entry) Attr(key string) string {
+	for _, attr := range e.Attrs {
+		if attr.Key == key {
+			return attr.Value.String()
+		}
+	}
+	return ""
+}
+
+// String - запись одной строкой: "02.01 15:04:05 ERROR [bot] сообщение key=value"
+func (e Entry) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s %-5s", e.Time.Format("02.01 15:04:05"), e.Level)
+	if e.Component != "" {
+		fmt.Fprintf(&builder, " [%s]", e.Component)
+	}
+	builder.WriteString(" " + e.Message)
+	for _, attr := range e.Attrs {
+		value := attr.Value.String()
+		if strings.ContainsAny(value, " \t\n\"=") {
+			value = fmt.Sprintf("%q", value)
+		}
+		fmt.Fprintf(&builder, " %s=%s", attr.Key, value)
+	}
+	return builder.String()
+}
+
+// ring - кольцевой буфер последних записей
+type ring struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+func newRing(size int) *ring {
+	return &ring{entries: make([]Entry, max(size, 1))}
+}
+
+func (r *ring) add(entry Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// all возвращает записи от старых к новым
+func (r *ring) all() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]Entry(nil), r.entries[:r.next]...)
+	}
+	return append(append([]Entry(nil), r.entries[r.next:]...), r.entries[:r.next]...)
+}
+
+type subscriber struct {
+	level slog.Level
+	fn    func(Entry)
+}
+
+var (
+	buffer atomic.Pointer[ring]
+
+	subscribersMu sync.RWMutex
+	subscribers   []subscriber
+)
+
+func init() {
+	buffer.Store(newRing(defaultBufferSize))
+}
+
+// resizeBuffer меняет размер буфера, сохраняя последние записи
+func resizeBuffer(size int) {
+	if size <= 0 {
+		size = defaultBufferSize
+	}
+	resized := newRing(size)
+	for _, entry := range buffer.Load().all() {
+		resized.add(entry)
+	}
+	buffer.Store(resized)
+}
+
+// Recent возвращает до n последних записей уровня level и выше, от старых к новым
+func Recent(level slog.Level, n int) []Entry {
+	var matched []Entry
+	for _, entry := range buffer.Load().all() {
+		if entry.Level >= level {
+			matched = append(matched, entry)
+		}
+	}
+	if n > 0 && len(matched) > n {
+		matched = matched[len(matched)-n:]
+	}
+	return matched
+}
+
+// Subscribe вызывает fn для каждой записи уровня level и выше. fn вызывается
+// синхронно в горутине, которая пишет в лог, поэтому должна быстро возвращаться
+func Subscribe(level slog.Level, fn func(Entry)) {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+	subscribers = append(subscribers, subscriber{level: level, fn: fn})
+}
+
+// recordEntry сохраняет запись в буфер и передает подписчикам
+func recordEntry(entry Entry) {
+	buffer.Load().add(entry)
+
+	subscribersMu.RLock()
+	defer subscribersMu.RUnlock()
+	for _, subscriber := range subscribers {
+		if entry.Level >= subscriber.level {
+			subscriber.fn(entry)
+		}
+	}
+}
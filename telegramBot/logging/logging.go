@@ -0,0 +1,196 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// settings - текущие вывод и уровни; меняются через Setup, логгеры подхватывают их сразу
+type settings struct {
+	output     slog.Handler
+	level      slog.Level
+	components map[string]slog.Level
+}
+
+var current atomic.Pointer[settings]
+
+func init() {
+	current.Store(&settings{output: newOutput(os.Stderr, "text"), level: slog.LevelInfo})
+	slog.SetDefault(slog.New(&handler{}))
+}
+
+// Options - формат и уровни логирования
+type Options struct {
+	Format string   // text или json
+	Debug  bool     // общий уровень debug вместо info
+	Levels []string // уровни компонентов: "telegram=debug", "yandex=warn"
+	Buffer int      // сколько последних записей хранить в памяти для /logs
+}
+
+// Setup настраивает вывод и уровни и делает slog.Default (а через него и пакет log)
+// логгером с маскированием секретов
+func Setup(options Options) error {
+	state := &settings{
+		output:     newOutput(os.Stderr, options.Format),
+		level:      slog.LevelInfo,
+		components: make(map[string]slog.Level),
+	}
+	if options.Debug {
+		state.level = slog.LevelDebug
+	}
+
+	for _, item := range options.Levels {
+		component, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return fmt.Errorf("LOG_LEVELS: ожидается компонент=уровень, получено %q", item)
+		}
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(strings.TrimSpace(value))); err != nil {
+			return fmt.Errorf("LOG_LEVELS: %s: %v", component, err)
+		}
+		state.components[strings.TrimSpace(component)] = level
+	}
+
+	resizeBuffer(options.Buffer)
+	current.Store(state)
+	slog.SetDefault(slog.New(&handler{}))
+	return nil
+}
+
+func newOutput(w io.Writer, format string) slog.Handler {
+	// Уровень проверяет handler, внешний вывод пропускает все
+	options := &slog.HandlerOptions{Level: slog.LevelDebug}
+	if format == "json" {
+		return slog.NewJSONHandler(w, options)
+	}
+	return slog.NewTextHandler(w, options)
+}
+
+// For возвращает логгер компонента: его уровень задается в LOG_LEVELS
+func For(component string) *slog.Logger {
+	return slog.New(&handler{component: component}).With("component", component)
+}
+
+// Describe описывает текущие уровни: "info, telegram=debug"
+func Describe() string {
+	state := current.Load()
+	parts := []string{strings.ToLower(state.level.String())}
+
+	var components []string
+	for component := range state.components {
+		components = append(components, component)
+	}
+	slices.Sort(components)
+	for _, component := range components {
+		parts = append(parts, component+"="+strings.ToLower(state.components[component].String()))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// NewCorrelationID создает идентификатор для сквозной связи записей одного обновления
+func NewCorrelationID() string {
+	buf := make([]byte, 4)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// handler маскирует секреты и применяет уровень компонента. Атрибуты и группы
+// накапливаются и применяются к выводу при записи, чтобы логгеры, созданные
+// до Setup, писали уже в настроенный вывод
+type handler struct {
+	component string
+	ops       []op
+}
+
+type op struct {
+	group string
+	attrs []slog.Attr
+}
+
+func (h *handler) Enabled(_ context.Context, level slog.Level) bool {
+	state := current.Load()
+	if componentLevel, ok := state.components[h.component]; ok {
+		return level >= componentLevel
+	}
+	return level >= state.level
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	output := current.Load().output
+	for _, op := range h.ops {
+		if op.group != "" {
+			output = output.WithGroup(op.group)
+		} else {
+			output = output.WithAttrs(op.attrs)
+		}
+	}
+
+	redacted := slog.NewRecord(record.Time, record.Level, Redact(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(redactAttr(attr))
+		return true
+	})
+	h.record(redacted)
+	return output.Handle(ctx, redacted)
+}
+
+// record сохраняет запись в буфер: атрибуты логгера и записи разворачиваются
+// в плоский список, ключи внутри групп получают префикс "группа."
+func (h *handler) record(record slog.Record) {
+	entry := Entry{Time: record.Time, Level: record.Level, Component: h.component, Message: record.Message}
+
+	prefix := ""
+	add := func(attr slog.Attr) {
+		if attr.Key == "component" && prefix == "" {
+			return
+		}
+		entry.Attrs = appendFlat(entry.Attrs, prefix, attr)
+	}
+	for _, op := range h.ops {
+		if op.group != "" {
+			prefix += op.group + "."
+			continue
+		}
+		for _, attr := range op.attrs {
+			add(attr)
+		}
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		add(attr)
+		return true
+	})
+	recordEntry(entry)
+}
+
+func appendFlat(attrs []slog.Attr, prefix string, attr slog.Attr) []slog.Attr {
+	if attr.Value.Kind() == slog.KindGroup {
+		for _, item := range attr.Value.Group() {
+			attrs = appendFlat(attrs, prefix+attr.Key+".", item)
+		}
+		return attrs
+	}
+	return append(attrs, slog.Attr{Key: prefix + attr.Key, Value: attr.Value})
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = redactAttr(attr)
+	}
+	return &handler{component: h.component, ops: append(slices.Clip(h.ops), op{attrs: redacted})}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &handler{component: h.component, ops: append(slices.Clip(h.ops), op{group: name})}
+}
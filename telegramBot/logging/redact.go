@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	secretsMu sync.RWMutex
+	secrets   []string
+
+	patterns = []struct {
+		re      *regexp.Regexp
+		replace string
+	}{
+		// Токен бота: 123456789:AA... (в URL Bot API - после "bot")
+		{regexp.MustCompile(`(\b|bot)\d{6,}:[A-Za-z0-9_-]{30,}`), "${1}" + redacted},
+		// Заголовки авторизации
+		{regexp.MustCompile(`(?i)\b(OAuth|Bearer)\s+[A-Za-z0-9._~+/=-]+`), "$1 " + redacted},
+		// Токены в параметрах запроса и JSON
+		{regexp.MustCompile(`(?i)\b((?:access_|refresh_)?token|client_secret|password)=[^&\s"']+`), "$1=" + redacted},
+		{regexp.MustCompile(`(?i)"((?:access_|refresh_)?token|client_secret|password)"\s*:\s*"[^"]*"`), `"$1":"` + redacted + `"`},
+		// OAuth-токены Яндекса
+		{regexp.MustCompile(`\by0_[A-Za-z0-9_-]{20,}`), redacted},
+		// Одноразовые ссылки загрузки и скачивания Яндекс.Диска дают доступ к файлу без токена
+		{regexp.MustCompile(`(https?://(?:uploader|downloader)[A-Za-z0-9.-]*\.yandex\.(?:net|ru|com)(?::\d+)?)/[^\s"'<>]*`), "$1/" + redacted},
+	}
+)
+
+// AddSecret регистрирует значение, которое никогда не должно попадать в логи
+func AddSecret(secret string) {
+	// Короткие значения маскировать опасно: они совпадают с обычным текстом
+	if len(secret) < 8 {
+		return
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	secrets = append(secrets, secret)
+}
+
+// Redact маскирует токены, заголовки авторизации и ссылки загрузки
+func Redact(text string) string {
+	secretsMu.RLock()
+	for _, secret := range secrets {
+		text = strings.ReplaceAll(text, secret, redacted)
+	}
+	secretsMu.RUnlock()
+
+	for _, pattern := range patterns {
+		text = pattern.re.ReplaceAllString(text, pattern.replace)
+	}
+	return text
+}
+
+func redactAttr(attr slog.Attr) slog.Attr {
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, Redact(value.String()))
+	case slog.KindGroup:
+		group := value.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, item := range group {
+			attrs[i] = redactAttr(item)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(attrs...)}
+	case slog.KindAny:
+		switch any := value.Any().(type) {
+		case error:
+			return slog.String(attr.Key, Redact(any.Error()))
+		case fmt.Stringer:
+			return slog.String(attr.Key, Redact(any.String()))
+		case []byte:
+			return slog.String(attr.Key, Redact(string(any)))
+		}
+	}
+	return slog.Attr{Key: attr.Key, Value: value}
+}
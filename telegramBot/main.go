@@ -1,11 +1,39 @@
 package main
 
 import (
+	"errors"
+	"log/slog"
+	"os"
+
+	"telegramBot/config"
 	"telegramBot/handlersTelegramBot"
+	"telegramBot/stack"
 	"telegramBot/yandexapi/initYD"
 )
 
 func main() {
-	initYD.InitYandexDisk()
-	handlersTelegramBot.StartTelegramBot()
+	// telegram-bot stack up|down|status - управление стеком сервера
+	if len(os.Args) > 1 && os.Args[1] == "stack" {
+		os.Exit(stack.Run(os.Args[2:]))
+	}
+	// telegram-bot config check|keygen|encrypt|decrypt - проверка конфигурации и секреты
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(config.Run(os.Args[2:]))
+	}
+
+	// Конфигурация читается один раз и передается всем, кому она нужна
+	cfg, err := config.Load()
+	if err != nil {
+		var validation *config.ValidationError
+		if errors.As(err, &validation) {
+			for _, problem := range validation.Problems {
+				slog.Error("❌ " + problem)
+			}
+		}
+		slog.Error("❌ Конфигурация содержит ошибки, бот не запущен", "file", cfg.File)
+		os.Exit(1)
+	}
+
+	initYD.InitYandexDisk(cfg)
+	handlersTelegramBot.StartTelegramBot(cfg)
 }
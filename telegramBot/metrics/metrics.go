@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "telegram_bot"
+
+// Registry - реестр метрик бота (без глобального реестра, чтобы не тянуть чужие метрики)
+var Registry = prometheus.NewRegistry()
+
+var (
+	updates = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "updates_total",
+		Help:      "Обработанные обновления Telegram по типу.",
+	}, []string{"type"})
+
+	commandDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "command_duration_seconds",
+		Help:      "Время обработки команд.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"command"})
+
+	apiRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "api_requests_total",
+		Help:      "Запросы к внешним API по коду ответа (network - ошибка соединения).",
+	}, []string{"api", "method", "code"})
+
+	apiErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "api_errors_total",
+		Help:      "Неуспешные запросы к внешним API по коду ответа.",
+	}, []string{"api", "method", "code"})
+
+	apiDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "api_request_duration_seconds",
+		Help:      "Длительность запросов к внешним API.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"api"})
+
+	uploadBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upload_bytes_total",
+		Help:      "Объем файлов, загруженных на Яндекс.Диск.",
+	})
+
+	uploadDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "upload_duration_seconds",
+		Help:      "Длительность загрузки файлов на Яндекс.Диск.",
+		Buckets:   []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	}, []string{"result"})
+
+	sendQueue = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "send_queue_depth",
+		Help:      "Сообщения, ожидающие отправки из-за ограничения частоты запросов к Telegram.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		updates, commandDuration, apiRequests, apiErrors, apiDuration,
+		uploadBytes, uploadDuration, sendQueue,
+	)
+}
+
+// Update учитывает полученное обновление: message, callback_query, document...
+func Update(updateType string) {
+	updates.WithLabelValues(updateType).Inc()
+}
+
+// Command учитывает время обработки команды
+func Command(command string, duration time.Duration) {
+	commandDuration.WithLabelValues(command).Observe(duration.Seconds())
+}
+
+// APIRequest учитывает запрос к внешнему API. statusCode 0 - ошибка соединения
+func APIRequest(api string, method string, statusCode int, duration time.Duration) {
+	code := "network"
+	if statusCode != 0 {
+		code = strconv.Itoa(statusCode)
+	}
+
+	apiRequests.WithLabelValues(api, method, code).Inc()
+	apiDuration.WithLabelValues(api).Observe(duration.Seconds())
+	if statusCode < 200 || statusCode >= 300 {
+		apiErrors.WithLabelValues(api, method, code).Inc()
+	}
+}
+
+// Upload учитывает загрузку файла на Яндекс.Диск
+func Upload(size int, duration time.Duration, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	} else {
+		uploadBytes.Add(float64(size))
+	}
+	uploadDuration.WithLabelValues(result).Observe(duration.Seconds())
+}
+
+// QueueWait отмечает сообщение, ожидающее отправки; возвращенную функцию нужно вызвать после ожидания
+func QueueWait() (done func()) {
+	sendQueue.Inc()
+	return sendQueue.Dec
+}
+
+// Sessions регистрирует число активных сессий, которое вычисляется при каждом сборе метрик
+func Sessions(kind string, count func() int) {
+	Registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Name:        "active_sessions",
+		Help:        "Активные диалоги с пользователями (ожидание ввода, загрузка файлов).",
+		ConstLabels: prometheus.Labels{"kind": kind},
+	}, func() float64 { return float64(count()) }))
+}
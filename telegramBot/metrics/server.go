@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"telegramBot/logging"
+)
+
+var logger = logging.For("metrics")
+
+// Результаты проверок кешируются, чтобы частый healthcheck не нагружал внешние API
+const checkCacheTTL = 15 * time.Second
+
+// Check - проверка зависимости; nil - все в порядке
+type Check func() error
+
+type check struct {
+	name string
+	run  Check
+
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+func (c *check) result() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.checked) > checkCacheTTL {
+		c.err = c.run()
+		c.checked = time.Now()
+	}
+	return c.err
+}
+
+// Server отдает метрики и состояние бота:
+//
+//	GET /metrics - метрики Prometheus
+//	GET /healthz - бот жив (проверки Liveness)
+//	GET /readyz  - бот готов к работе (проверки Liveness и Readiness)
+type Server struct {
+	listen   string
+	live     []*check
+	ready    []*check
+	server   *http.Server
+	checksMu sync.Mutex
+}
+
+func NewServer(listen string) *Server {
+	s := &Server{listen: listen}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		s.respond(w, s.checks(false))
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		s.respond(w, s.checks(true))
+	})
+
+	s.server = &http.Server{
+		Addr:              listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// Liveness добавляет проверку для /healthz и /readyz
+func (s *Server) Liveness(name string, run Check) {
+	s.checksMu.Lock()
+	defer s.checksMu.Unlock()
+	s.live = append(s.live, &check{name: name, run: run})
+}
+
+// Readiness добавляет проверку только для /readyz
+func (s *Server) Readiness(name string, run Check) {
+	s.checksMu.Lock()
+	defer s.checksMu.Unlock()
+	s.ready = append(s.ready, &check{name: name, run: run})
+}
+
+func (s *Server) checks(withReadiness bool) []*check {
+	s.checksMu.Lock()
+	defer s.checksMu.Unlock()
+
+	checks := append([]*check(nil), s.live...)
+	if withReadiness {
+		checks = append(checks, s.ready...)
+	}
+	return checks
+}
+
+// respond выполняет проверки параллельно: 200, если все успешны, иначе 503
+func (s *Server) respond(w http.ResponseWriter, checks []*check) {
+	results := make(map[string]string, len(checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	healthy := true
+
+	for _, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := c.result()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				healthy = false
+				results[c.name] = err.Error()
+			} else {
+				results[c.name] = "ok"
+			}
+		}()
+	}
+	wg.Wait()
+
+	status := "ok"
+	code := http.StatusOK
+	if !healthy {
+		status = "fail"
+		code = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]any{"status": status, "checks": results})
+}
+
+// Start запускает HTTP-сервер в фоне
+func (s *Server) Start() {
+	go func() {
+		logger.Info("📈 Метрики и проверки состояния", "url", "http://"+s.listen+"/metrics")
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("❌ HTTP-сервер метрик остановлен", "error", err)
+		}
+	}()
+}
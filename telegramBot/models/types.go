@@ -7,8 +7,12 @@ import (
 
 // Структуры для Telegram API
 type Update struct {
-	UpdateID int      `json:"update_id"`
-	Message  *Message `json:"message"`
+	UpdateID      int            `json:"update_id"`
+	Message       *Message       `json:"message"`
+	CallbackQuery *CallbackQuery `json:"callback_query"`
+
+	// CorrelationID связывает записи лога, относящиеся к одному обновлению
+	CorrelationID string `json:"-"`
 }
 
 type Message struct {
@@ -51,6 +55,24 @@ type Document struct {
 	FileSize     int       `json:"file_size"`
 }
 
+// CallbackQuery - нажатие на inline-кнопку
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	From    User     `json:"from"`
+	Message *Message `json:"message"`
+	Data    string   `json:"data"`
+}
+
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data,omitempty"`
+	URL          string `json:"url,omitempty"`
+}
+
 // Структура для APIYandexDisk
 // type YandexDiskAPI struct {
 // 	hostNameURL string
@@ -0,0 +1,236 @@
+package mqttclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"telegramBot/config"
+	"telegramBot/logging"
+)
+
+var logger = logging.For("mqtt")
+
+const (
+	qos            = 1
+	publishTimeout = 10 * time.Second
+)
+
+// Message - входящее MQTT-сообщение
+type Message struct {
+	Topic    string
+	Payload  []byte
+	Retained bool
+}
+
+// Handler обрабатывает сообщения по подписке
+type Handler func(message Message)
+
+// Client - подключение к брокеру с автоматическим переподключением.
+// Все подписки запоминаются и восстанавливаются после каждого переподключения.
+type Client struct {
+	client mqtt.Client
+
+	mu            sync.RWMutex
+	subscriptions map[string][]subscription // ключ: фильтр топика
+	nextID        uint64
+}
+
+type subscription struct {
+	id      uint64
+	handler Handler
+}
+
+func New(config config.MQTTConfig) (*Client, error) {
+	c := &Client{
+		subscriptions: make(map[string][]subscription),
+	}
+
+	options := mqtt.NewClientOptions().
+		AddBroker(config.Broker).
+		SetClientID(config.ClientID).
+		SetUsername(config.Username).
+		SetPassword(config.Password).
+		SetCleanSession(false).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(10 * time.Second).
+		SetMaxReconnectInterval(time.Minute).
+		SetOrderMatters(false).
+		SetOnConnectHandler(c.onConnect).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			logger.Warn("⚠️ Соединение потеряно", "error", err)
+		}).
+		SetReconnectingHandler(func(_ mqtt.Client, _ *mqtt.ClientOptions) {
+			logger.Info("🔄 Переподключение...")
+		})
+
+	if config.TLSCA != "" || config.TLSCert != "" || config.TLSInsecure {
+		tlsConfig, err := newTLSConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		options.SetTLSConfig(tlsConfig)
+	}
+
+	c.client = mqtt.NewClient(options)
+	return c, nil
+}
+
+// Connect запускает подключение. При недоступном брокере попытки продолжаются в фоне.
+func (c *Client) Connect() {
+	logger.Info("🔌 Подключение к брокеру...")
+	c.client.Connect()
+}
+
+func (c *Client) IsConnected() bool {
+	return c.client.IsConnectionOpen()
+}
+
+// Publish отправляет сообщение и ждет подтверждения брокера
+func (c *Client) Publish(topic string, payload []byte, retain bool) error {
+	token := c.client.Publish(topic, qos, retain, payload)
+	if !token.WaitTimeout(publishTimeout) {
+		return fmt.Errorf("брокер не подтвердил публикацию за %v", publishTimeout)
+	}
+	if err := token.Error(); err != nil {
+		return err
+	}
+
+	logger.Debug("📤 Опубликовано", "topic", topic, "bytes", len(payload))
+	return nil
+}
+
+// Subscribe добавляет обработчик для фильтра топика. На один фильтр можно повесить
+// несколько обработчиков - каждый получит все сообщения. Возвращает функцию отписки:
+// подписка у брокера снимается, когда у фильтра не остается обработчиков.
+func (c *Client) Subscribe(topic string, handler Handler) (func(), error) {
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	_, exists := c.subscriptions[topic]
+	c.subscriptions[topic] = append(c.subscriptions[topic], subscription{id: id, handler: handler})
+	c.mu.Unlock()
+
+	cancel := func() { c.unsubscribe(topic, id) }
+
+	if exists || !c.IsConnected() {
+		// Подписка у брокера уже есть или будет оформлена в onConnect
+		return cancel, nil
+	}
+	return cancel, c.subscribe(topic)
+}
+
+func (c *Client) unsubscribe(topic string, id uint64) {
+	c.mu.Lock()
+	var remaining []subscription
+	for _, sub := range c.subscriptions[topic] {
+		if sub.id != id {
+			remaining = append(remaining, sub)
+		}
+	}
+	if len(remaining) > 0 {
+		c.subscriptions[topic] = remaining
+	} else {
+		delete(c.subscriptions, topic)
+	}
+	c.mu.Unlock()
+
+	if len(remaining) > 0 || !c.IsConnected() {
+		return
+	}
+
+	token := c.client.Unsubscribe(topic)
+	if !token.WaitTimeout(publishTimeout) {
+		logger.Warn("⚠️ Брокер не подтвердил отписку", "topic", topic)
+		return
+	}
+	if err := token.Error(); err != nil {
+		logger.Error("❌ Ошибка отписки", "topic", topic, "error", err)
+		return
+	}
+	logger.Info("📭 Отписка", "topic", topic)
+}
+
+func (c *Client) subscribe(topic string) error {
+	token := c.client.Subscribe(topic, qos, func(_ mqtt.Client, message mqtt.Message) {
+		c.dispatch(topic, Message{
+			Topic:    message.Topic(),
+			Payload:  message.Payload(),
+			Retained: message.Retained(),
+		})
+	})
+	if !token.WaitTimeout(publishTimeout) {
+		return fmt.Errorf("брокер не подтвердил подписку на %s за %v", topic, publishTimeout)
+	}
+	if err := token.Error(); err != nil {
+		return err
+	}
+
+	logger.Info("📥 Подписка", "topic", topic)
+	return nil
+}
+
+func (c *Client) dispatch(topic string, message Message) {
+	c.mu.RLock()
+	subscriptions := append([]subscription(nil), c.subscriptions[topic]...)
+	c.mu.RUnlock()
+
+	for _, sub := range subscriptions {
+		sub.handler(message)
+	}
+}
+
+// onConnect восстанавливает подписки после (пере)подключения
+func (c *Client) onConnect(_ mqtt.Client) {
+	logger.Info("✅ Подключено к брокеру")
+
+	c.mu.RLock()
+	topics := make([]string, 0, len(c.subscriptions))
+	for topic := range c.subscriptions {
+		topics = append(topics, topic)
+	}
+	c.mu.RUnlock()
+
+	// Вызывается из горутины paho - ждать подтверждения подписки здесь нельзя
+	go func() {
+		for _, topic := range topics {
+			if err := c.subscribe(topic); err != nil {
+				logger.Error("❌ Ошибка подписки", "topic", topic, "error", err)
+			}
+		}
+	}()
+}
+
+func newTLSConfig(config config.MQTTConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: config.TLSInsecure,
+	}
+
+	if config.TLSCA != "" {
+		caData, err := os.ReadFile(config.TLSCA)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения сертификата CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("в файле %s нет PEM-сертификатов", config.TLSCA)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.TLSCert != "" {
+		certificate, err := tls.LoadX509KeyPair(config.TLSCert, config.TLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка загрузки клиентского сертификата: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,88 @@
+package mqttclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Route направляет сообщения из топика в чат (и, если задан, в топик форума)
+type Route struct {
+	Topic    string `json:"topic"`
+	ChatID   int64  `json:"chat_id"`
+	ThreadID int    `json:"thread_id,omitempty"`
+}
+
+// RouteStore хранит маршруты в JSON-файле, чтобы подписки переживали перезапуск бота
+type RouteStore struct {
+	file   string
+	mu     sync.Mutex
+	routes []Route
+}
+
+func LoadRoutes(file string) (*RouteStore, error) {
+	store := &RouteStore{file: file}
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &store.routes); err != nil {
+		return nil, fmt.Errorf("ошибка чтения %s: %w", file, err)
+	}
+	return store, nil
+}
+
+// Add сохраняет маршрут. Возвращает false, если такой маршрут уже есть.
+func (s *RouteStore) Add(route Route) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.routes {
+		if existing == route {
+			return false, nil
+		}
+	}
+	s.routes = append(s.routes, route)
+	return true, s.save()
+}
+
+// Remove удаляет маршрут. Возвращает false, если маршрута не было.
+func (s *RouteStore) Remove(route Route) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.routes {
+		if existing == route {
+			s.routes = append(s.routes[:i], s.routes[i+1:]...)
+			return true, s.save()
+		}
+	}
+	return false, nil
+}
+
+func (s *RouteStore) List() []Route {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Route(nil), s.routes...)
+}
+
+func (s *RouteStore) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.file), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s.routes, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.file, data, 0o644)
+}
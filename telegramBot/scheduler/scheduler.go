@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"telegramBot/logging"
+)
+
+var logger = logging.For("scheduler")
+
+// Общий планировщик задач бота. Расписания задаются в стандартном cron-формате
+// из пяти полей: "минуты часы день месяц день_недели", например "0 3 * * *".
+var runner = cron.New(
+	cron.WithLocation(time.Local),
+	cron.WithChain(
+		cron.Recover(cron.DefaultLogger),
+		cron.SkipIfStillRunning(cron.DefaultLogger),
+	),
+)
+
+// Add регистрирует задачу job с именем name по расписанию spec
+func Add(name string, spec string, job func()) error {
+	_, err := runner.AddFunc(spec, func() {
+		logger.Info("⏰ Запуск задачи по расписанию", "task", name)
+		job()
+	})
+	if err != nil {
+		return fmt.Errorf("неверное расписание %q для задачи %s: %w", spec, name, err)
+	}
+
+	logger.Info("🗓️ Задача запланирована", "task", name, "spec", spec)
+	return nil
+}
+
+// Start запускает планировщик в фоне
+func Start() {
+	runner.Start()
+}
@@ -0,0 +1,125 @@
+package stack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Ожидание готовности одного проекта по умолчанию
+const defaultTimeout = 3 * time.Minute
+
+// Project - шаг запуска: compose-проект целиком или часть его сервисов
+type Project struct {
+	Name      string        `yaml:"name"`
+	Dir       string        `yaml:"dir"`        // каталог проекта относительно манифеста
+	File      string        `yaml:"file"`       // compose-файл, если имя нестандартное
+	Services  []string      `yaml:"services"`   // пусто - все сервисы проекта
+	Build     bool          `yaml:"build"`      // up --build
+	DependsOn []string      `yaml:"depends_on"` // шаги, которые должны быть готовы раньше
+	Wait      []string      `yaml:"wait"`       // контейнеры: ждем healthy, а без healthcheck - running
+	Ports     []string      `yaml:"ports"`      // адреса host:port, которые должны принимать соединения
+	Timeout   time.Duration `yaml:"timeout"`
+}
+
+// Manifest - описание стека сервера
+type Manifest struct {
+	Report   string    `yaml:"report"` // файл отчета, который бот отправит в Telegram после запуска
+	Projects []Project `yaml:"projects"`
+
+	dir string
+}
+
+// LoadManifest читает манифест; относительные пути считаются от его каталога
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("ошибка разбора %s: %v", path, err)
+	}
+
+	manifest.dir, err = filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Report != "" {
+		manifest.Report = manifest.resolve(manifest.Report)
+	}
+	for i := range manifest.Projects {
+		project := &manifest.Projects[i]
+		if project.Name == "" {
+			return nil, fmt.Errorf("шаг %d: не указано имя", i+1)
+		}
+		if project.Dir == "" {
+			return nil, fmt.Errorf("шаг %s: не указан каталог", project.Name)
+		}
+		project.Dir = manifest.resolve(project.Dir)
+		if project.Timeout <= 0 {
+			project.Timeout = defaultTimeout
+		}
+	}
+	return &manifest, nil
+}
+
+func (m *Manifest) resolve(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(m.dir, path)
+}
+
+// Order возвращает шаги в порядке зависимостей, сохраняя порядок манифеста там, где он не важен
+func (m *Manifest) Order() ([]Project, error) {
+	byName := make(map[string]Project, len(m.Projects))
+	for _, project := range m.Projects {
+		if _, ok := byName[project.Name]; ok {
+			return nil, fmt.Errorf("шаг %s описан дважды", project.Name)
+		}
+		byName[project.Name] = project
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	var order []Project
+
+	var visit func(name string, from string) error
+	visit = func(name string, from string) error {
+		project, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("шаг %s зависит от неизвестного шага %s", from, name)
+		}
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("циклическая зависимость через шаг %s", name)
+		}
+
+		state[name] = visiting
+		for _, dependency := range project.DependsOn {
+			if err := visit(dependency, name); err != nil {
+				return err
+			}
+		}
+		state[name] = done
+		order = append(order, project)
+		return nil
+	}
+
+	for _, project := range m.Projects {
+		if err := visit(project.Name, ""); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
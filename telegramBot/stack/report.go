@@ -0,0 +1,100 @@
+package stack
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// StepResult - итог одного шага
+type StepResult struct {
+	Name     string        `json:"name"`
+	OK       bool          `json:"ok"`
+	Skipped  bool          `json:"skipped,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Report - итог stack up, который бот отправляет администраторам, когда выходит на связь
+type Report struct {
+	Action   string       `json:"action"`
+	Host     string       `json:"host"`
+	Started  time.Time    `json:"started"`
+	Finished time.Time    `json:"finished"`
+	Steps    []StepResult `json:"steps"`
+}
+
+// OK - все шаги выполнены успешно
+func (r *Report) OK() bool {
+	for _, step := range r.Steps {
+		if !step.OK {
+			return false
+		}
+	}
+	return true
+}
+
+// Format готовит HTML-сообщение для Telegram
+func (r *Report) Format() string {
+	var builder strings.Builder
+	if r.OK() {
+		fmt.Fprintf(&builder, "✅ <b>Стек запущен</b> (stack %s)\n", html.EscapeString(r.Action))
+	} else {
+		fmt.Fprintf(&builder, "❌ <b>Ошибка запуска стека</b> (stack %s)\n", html.EscapeString(r.Action))
+	}
+	if r.Host != "" {
+		fmt.Fprintf(&builder, "🖥️ %s · ", html.EscapeString(r.Host))
+	}
+	fmt.Fprintf(&builder, "%s, за %s\n", r.Finished.Format("02.01.2006 15:04:05"), r.Finished.Sub(r.Started).Round(time.Second))
+	fmt.Fprintln(&builder, strings.Repeat("─", 20))
+
+	for _, step := range r.Steps {
+		switch {
+		case step.OK:
+			fmt.Fprintf(&builder, "🟢 <b>%s</b> — %s\n", html.EscapeString(step.Name), step.Duration.Round(time.Second))
+		case step.Skipped:
+			fmt.Fprintf(&builder, "⚪ <b>%s</b> — пропущен\n", html.EscapeString(step.Name))
+		default:
+			fmt.Fprintf(&builder, "🔴 <b>%s</b> — %s\n", html.EscapeString(step.Name), html.EscapeString(step.Error))
+		}
+	}
+	return builder.String()
+}
+
+// WriteReport атомарно записывает отчет в файл
+func WriteReport(path string, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ReadReport читает отчет; если файла нет, возвращает nil без ошибки
+func ReadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("ошибка разбора %s: %v", path, err)
+	}
+	return &report, nil
+}
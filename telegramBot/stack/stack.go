@@ -0,0 +1,280 @@
+package stack
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"telegramBot/dockerapi"
+	"telegramBot/logging"
+)
+
+var logger = logging.For("stack")
+
+const (
+	pollInterval = 2 * time.Second
+
+	// Метки, которыми docker compose помечает контейнеры
+	labelWorkingDir = "com.docker.compose.project.working_dir"
+	labelService    = "com.docker.compose.service"
+)
+
+const usage = `Использование: telegram-bot stack up|down|status [-f stack.yaml] [-socket /var/run/docker.sock]
+
+  up      запустить проекты в порядке зависимостей, дожидаясь готовности каждого
+  down    остановить проекты в обратном порядке
+  status  показать состояние контейнеров стека
+`
+
+// Run выполняет подкоманду stack и возвращает код завершения процесса
+func Run(args []string) int {
+	flags := flag.NewFlagSet("stack", flag.ContinueOnError)
+	manifestPath := flags.String("f", envOr("STACK_MANIFEST", "stack.yaml"), "манифест стека")
+	socket := flags.String("socket", envOr("DOCKER_SOCKET", "/var/run/docker.sock"), "Docker-сокет")
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+
+	// Флаги допускаются и до, и после действия
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+	action := flags.Arg(0)
+	if err := flags.Parse(flags.Args()[1:]); err != nil {
+		return 2
+	}
+
+	manifest, err := LoadManifest(*manifestPath)
+	if err != nil {
+		logger.Error("❌ Ошибка загрузки манифеста", "error", err)
+		return 1
+	}
+	projects, err := manifest.Order()
+	if err != nil {
+		logger.Error("❌ Ошибка манифеста", "error", err)
+		return 1
+	}
+	client := dockerapi.New(*socket)
+
+	switch action {
+	case "up":
+		report := up(client, projects)
+		if manifest.Report != "" {
+			if err := WriteReport(manifest.Report, report); err != nil {
+				logger.Error("❌ Ошибка записи отчета", "error", err)
+			}
+		}
+		if !report.OK() {
+			return 1
+		}
+	case "down":
+		if !down(projects) {
+			return 1
+		}
+	case "status":
+		if !status(client, projects) {
+			return 1
+		}
+	default:
+		flags.Usage()
+		return 2
+	}
+	return 0
+}
+
+// up запускает шаги по порядку; после первой ошибки оставшиеся шаги пропускаются
+func up(client *dockerapi.Client, projects []Project) *Report {
+	host, _ := os.Hostname()
+	report := &Report{Action: "up", Host: host, Started: time.Now()}
+
+	failed := false
+	for _, project := range projects {
+		if failed {
+			logger.Warn("⏭️ Пропущен", "project", project.Name)
+			report.Steps = append(report.Steps, StepResult{Name: project.Name, Skipped: true})
+			continue
+		}
+
+		started := time.Now()
+		logger.Info("🚀 Запуск...", "project", project.Name)
+		err := startProject(client, project)
+		result := StepResult{Name: project.Name, OK: err == nil, Duration: time.Since(started)}
+		if err != nil {
+			failed = true
+			result.Error = err.Error()
+			logger.Error("❌ Ошибка запуска", "project", project.Name, "error", err)
+		} else {
+			logger.Info("✅ Готов", "project", project.Name, "duration", result.Duration.Round(time.Second))
+		}
+		report.Steps = append(report.Steps, result)
+	}
+
+	report.Finished = time.Now()
+	return report
+}
+
+func startProject(client *dockerapi.Client, project Project) error {
+	args := []string{"up", "-d"}
+	if project.Build {
+		args = append(args, "--build")
+	}
+	if err := compose(project, append(args, project.Services...)...); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(project.Timeout)
+	for _, name := range project.Wait {
+		if err := waitContainer(client, name, deadline); err != nil {
+			return err
+		}
+	}
+	for _, address := range project.Ports {
+		if err := waitPort(address, deadline); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitContainer ждет, пока контейнер станет healthy или, если healthcheck не настроен, running
+func waitContainer(client *dockerapi.Client, name string, deadline time.Time) error {
+	logger.Info("⏳ Ожидание контейнера...", "container", name)
+	state := "не найден"
+	for {
+		details, err := client.Inspect(name)
+		if err == nil {
+			health := details.HealthStatus()
+			switch {
+			case health == "unhealthy":
+				return fmt.Errorf("контейнер %s: unhealthy", name)
+			case details.State.Running && (health == "" || health == "healthy"):
+				return nil
+			}
+			state = details.State.Status
+			if health != "" {
+				state += ", " + health
+			}
+		} else {
+			var apiError *dockerapi.APIError
+			if !errors.As(err, &apiError) {
+				return err
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("контейнер %s не готов (%s)", name, state)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// waitPort ждет, пока адрес начнет принимать TCP-соединения
+func waitPort(address string, deadline time.Time) error {
+	logger.Info("⏳ Ожидание порта...", "address", address)
+	for {
+		conn, err := net.DialTimeout("tcp", address, pollInterval)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("порт %s недоступен: %v", address, err)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// down останавливает шаги в обратном порядке; ошибки не прерывают остановку остальных
+func down(projects []Project) bool {
+	ok := true
+	for _, project := range slices.Backward(projects) {
+		logger.Info("🛑 Остановка...", "project", project.Name)
+
+		var err error
+		if len(project.Services) > 0 {
+			// Остальные сервисы проекта могут относиться к другим шагам
+			err = compose(project, append([]string{"stop"}, project.Services...)...)
+			if err == nil {
+				err = compose(project, append([]string{"rm", "-f"}, project.Services...)...)
+			}
+		} else {
+			err = compose(project, "down")
+		}
+
+		if err != nil {
+			ok = false
+			logger.Error("❌ Ошибка остановки", "project", project.Name, "error", err)
+		}
+	}
+	return ok
+}
+
+// status выводит контейнеры каждого шага; false, если ожидаемые контейнеры не готовы
+func status(client *dockerapi.Client, projects []Project) bool {
+	containers, err := client.Containers()
+	if err != nil {
+		logger.Error("❌ Ошибка Docker", "error", err)
+		return false
+	}
+
+	ok := true
+	for _, project := range projects {
+		fmt.Printf("📦 %s (%s)\n", project.Name, project.Dir)
+
+		found := 0
+		for _, container := range containers {
+			if container.Labels[labelWorkingDir] != project.Dir {
+				continue
+			}
+			if len(project.Services) > 0 && !slices.Contains(project.Services, container.Labels[labelService]) {
+				continue
+			}
+			found++
+			fmt.Printf("   %-20s %-10s %s\n", container.Name(), container.State, container.Status)
+		}
+		if found == 0 {
+			fmt.Println("   контейнеров нет")
+		}
+
+		for _, name := range project.Wait {
+			details, err := client.Inspect(name)
+			if err != nil || !details.State.Running || details.HealthStatus() == "unhealthy" || details.HealthStatus() == "starting" {
+				ok = false
+			}
+		}
+	}
+	return ok
+}
+
+// compose запускает docker compose в каталоге проекта
+func compose(project Project, args ...string) error {
+	command := []string{"compose"}
+	if project.File != "" {
+		command = append(command, "-f", project.File)
+	}
+	command = append(command, args...)
+
+	cmd := exec.Command("docker", command...)
+	cmd.Dir = project.Dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("docker %s: %v", strings.Join(command, " "), err)
+	}
+	return nil
+}
+
+func envOr(key string, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -1,8 +1,8 @@
 package authenticated
 
 import (
-	"fmt"
 	"net/url"
+	"strings"
 
 	"telegramBot/yandexapi/initYD"
 )
@@ -11,15 +11,16 @@ import (
 func BuildURL(pathNameURl string, parametr map[string]string) string {
 	apiAuth := initYD.GetYandexDiskAPI()
 	uriRequest := apiAuth.HostNameURL + pathNameURl
+	// Ссылки для загрузки/скачивания приходят от API уже полными
+	if strings.HasPrefix(pathNameURl, "http://") || strings.HasPrefix(pathNameURl, "https://") {
+		uriRequest = pathNameURl
+	}
 	if len(parametr) > 0 {
 		query := url.Values{}
 		for key, value := range parametr {
-			fmt.Printf("\nkey = %s, value = %s\n", key, value)
 			query.Add(key, value)
 		}
-		fmt.Printf("\nURL = %s\n", uriRequest)
 		uriRequest += "?" + query.Encode()
 	}
-	fmt.Printf("\nURL = %s\n", uriRequest)
 	return uriRequest
 }
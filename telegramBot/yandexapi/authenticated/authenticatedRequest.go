@@ -2,14 +2,43 @@ package authenticated
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
+	"strings"
+	"time"
 
+	"telegramBot/logging"
+	"telegramBot/metrics"
 	"telegramBot/yandexapi/initYD"
 )
 
+// Запросы пишутся на уровне debug; ссылки загрузки и токены маскируются логгером
+var logger = logging.For("yandex")
+
+// APIError - ошибка, которую вернул API Яндекс.Диска
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound сообщает, что запрошенный ресурс не существует
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsConflict сообщает, что ресурс уже существует (например, при создании папки)
+func IsConflict(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict
+}
+
 // authenticatedRequest выполняет авторизованный запрос , statusCode uint8
 func AuthenticatedRequest(method, pathUrl string, parametr map[string]string, body io.Reader) ([]byte, error) {
 
@@ -23,7 +52,7 @@ func AuthenticatedRequest(method, pathUrl string, parametr map[string]string, bo
 	}
 
 	// Устанавливаем заголовки авторизации
-	requestApi.Header.Set("Authorization", "OAuth "+apiAuth.Token)
+	requestApi.Header.Set("Authorization", "OAuth "+apiAuth.Token())
 	requestApi.Header.Set("Accept", "application/json")
 	if body != nil && method == "PUT" {
 		requestApi.Header.Set("Content-Type", "application/octet-stream")
@@ -32,12 +61,21 @@ func AuthenticatedRequest(method, pathUrl string, parametr map[string]string, bo
 		requestApi.Header.Set("Content-Type", "application/json")
 	}
 
-	log.Printf("🔗 Making %s request to: %s", method, url)
+	logger.Debug("🔗 Запрос к Яндекс.Диску", "method", method, "url", url)
 
-	responseApi, err := apiAuth.Client.Do(requestApi)
+	// Ссылки загрузки и скачивания ведут на файлы: передача может идти дольше таймаута API
+	client := apiAuth.Client
+	if isHref(pathUrl) {
+		client = apiAuth.TransferClient
+	}
+
+	started := time.Now()
+	responseApi, err := client.Do(requestApi)
 	if err != nil {
+		metrics.APIRequest("yandex", metricsEndpoint(method, pathUrl), 0, time.Since(started))
 		return nil, err
 	}
+	metrics.APIRequest("yandex", metricsEndpoint(method, pathUrl), responseApi.StatusCode, time.Since(started))
 	defer responseApi.Body.Close()
 
 	responseBody, err := io.ReadAll(responseApi.Body)
@@ -45,7 +83,8 @@ func AuthenticatedRequest(method, pathUrl string, parametr map[string]string, bo
 		return nil, err
 	}
 
-	if responseApi.StatusCode != http.StatusOK {
+	// Успешными считаем все 2xx: 201 Created при загрузке, 202 Accepted для асинхронных операций
+	if responseApi.StatusCode < 200 || responseApi.StatusCode >= 300 {
 		// Пытаемся извлечь message из JSON тела ответа
 		var errorResponse struct {
 			Code    string `json:"code"`
@@ -61,13 +100,33 @@ func AuthenticatedRequest(method, pathUrl string, parametr map[string]string, bo
 			if errorMsg == "" {
 				errorMsg = string(responseBody)
 			}
-			return nil, fmt.Errorf("API error %d: %s", responseApi.StatusCode, errorMsg)
+			if responseApi.StatusCode == http.StatusUnauthorized {
+				errorMsg += " (токен недействителен, подключите Диск заново: /connectdisk)"
+			}
+			return nil, &APIError{StatusCode: responseApi.StatusCode, Message: errorMsg}
 		}
 
 		// Если не JSON, выводим тело как есть
-		return nil, fmt.Errorf("API error %d: %s", responseApi.StatusCode, string(responseBody))
+		return nil, &APIError{StatusCode: responseApi.StatusCode, Message: string(responseBody)}
 	}
 
-	log.Printf("✅ Request successful (Status: %d)", responseApi.StatusCode)
+	logger.Debug("✅ Ответ Яндекс.Диска", "method", method, "status", responseApi.StatusCode)
 	return responseBody, nil
 }
+
+// metricsEndpoint - метка запроса для метрик. Ссылки загрузки/скачивания уникальны,
+// поэтому все они учитываются как "href"
+func metricsEndpoint(method string, pathUrl string) string {
+	if isHref(pathUrl) {
+		return method + " href"
+	}
+	if pathUrl == "" {
+		pathUrl = "/"
+	}
+	return method + " " + pathUrl
+}
+
+// isHref сообщает, что запрос идет по полной ссылке из ответа API, а не к методу API
+func isHref(pathUrl string) bool {
+	return strings.HasPrefix(pathUrl, "http://") || strings.HasPrefix(pathUrl, "https://")
+}
@@ -1,54 +1,95 @@
 package yandexapi
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"path"
 	"strings"
+	"time"
 
+	"telegramBot/logging"
+	"telegramBot/metrics"
+	"telegramBot/yandexapi/authenticated"
 	"telegramBot/yandexapi/method"
 )
 
+var logger = logging.For("yandex")
+
 // UploadFile читает локальный файл и передает его для загрузки на Яндекс.Диск
 func UploadFile(remotePathDirectory string, fileName string, fileData []byte) error {
 	// Получаем MIME-тип из данных
 	contentType := http.DetectContentType(fileData)
 	fileSize := int64(len(fileData))
 
-	fmt.Printf("📤 Загрузка файла: %s (%d байт, %s)\n", fileName, fileSize, contentType)
+	logger.Info("📤 Загрузка файла", "file", fileName, "bytes", fileSize, "content_type", contentType)
+
+	// Перед перезаписью переносим текущий файл в папку версий
+	versioned := isVersioned(remotePathDirectory)
+	if versioned {
+		if err := saveVersion(remotePathDirectory, fileName); err != nil {
+			return err
+		}
+	}
 
 	// Вызываем вашу функцию PostResourcesUpload (она должна быть адаптирована под []byte)
+	started := time.Now()
 	err := method.PostResourcesUpload(remotePathDirectory, fileData, contentType, fileSize, fileName)
+	metrics.Upload(len(fileData), time.Since(started), err)
 	if err != nil {
 		return fmt.Errorf("ошибка загрузки файла через PostResourcesUpload: %v", err)
 	}
 
-	fmt.Printf("✅ Файл успешно загружен: %s → %s\n", fileName, remotePathDirectory)
+	logger.Info("✅ Файл загружен", "file", fileName, "dir", remotePathDirectory)
+
+	if versioned {
+		if err := trimVersions(remotePathDirectory, fileName); err != nil {
+			logger.Warn("⚠️ Ошибка очистки старых версий", "file", fileName, "error", err)
+		}
+	}
 	return nil
 }
 
+// DownloadFile скачивает файл с Яндекс.Диска целиком
+func DownloadFile(filePath string) ([]byte, error) {
+	downloadURL, err := method.GetResourcesDownload(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("📥 Скачивание файла", "path", filePath)
+	data, err := authenticated.AuthenticatedRequest("GET", downloadURL, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка скачивания файла %s: %v", filePath, err)
+	}
+
+	logger.Info("✅ Файл скачан", "path", filePath, "bytes", len(data))
+	return data, nil
+}
+
 // CreateDirectory создание директории
 func CreateDirectory(pathDirectory string, nameDirectory string) error {
-	print("start createDir. pathDirectory = %s, nameDirectory = %s", pathDirectory, nameDirectory)
+	logger.Debug("📁 Создание директории", "path", pathDirectory, "name", nameDirectory)
 	directory, err := method.PutResources(pathDirectory, nameDirectory)
 
 	if err == nil {
 		return err
 	}
 
-	fmt.Printf("\n directory = %s\n", directory)
+	logger.Debug("📁 Директория создана", "directory", directory)
 	return nil
 }
 
 // CreateDirectory создание директории
 func DeleteDirectory(pathDirectory string, nameDirectory string) error {
-	print("start DelteDirectory. pathDirectory = %s, nameDirectory = %s", pathDirectory, nameDirectory)
-	directory, err := method.DeleteResources(pathDirectory, nameDirectory)
+	logger.Debug("🗑️ Удаление директории", "path", pathDirectory, "name", nameDirectory)
+	directory, err := method.DeleteResources(pathDirectory, nameDirectory, false)
 
 	if err == nil {
 		return err
 	}
 
-	fmt.Printf("\n directory = %s\n", directory)
+	logger.Debug("🗑️ Директория удалена", "directory", directory)
 	return nil
 }
 
@@ -59,9 +100,6 @@ func PrintDirectoryContents(pathDirectory string) ([]map[string]interface{}, err
 		return nil, err
 	}
 
-	fmt.Printf("📁 Contents of '%s':\n", pathDirectory)
-	fmt.Println(strings.Repeat("─", 60))
-
 	for _, file := range files {
 		name, _ := file["name"].(string)
 		fileType, _ := file["type"].(string)
@@ -69,28 +107,56 @@ func PrintDirectoryContents(pathDirectory string) ([]map[string]interface{}, err
 		if fileType == "file" {
 			size, _ := file["size"].(float64)
 			path, _ := file["path"].(string)
-			fmt.Printf("📄 %-30s %10s %s\n", name, FormatBytes(int64(size)), path)
+			logger.Debug("📄 "+name, "size", FormatBytes(int64(size)), "path", path)
 		} else {
-			fmt.Printf("📁 %s/\n", name)
+			logger.Debug("📁 " + name + "/")
 		}
 	}
-	fmt.Println(strings.Repeat("─", 60))
-	fmt.Printf("Total items: %d\n", len(files))
+	logger.Debug("📁 Содержимое директории", "path", pathDirectory, "items", len(files))
 
 	return files, nil
 }
 
+// ListFiles возвращает содержимое директории в виде типизированного списка
+func ListFiles(pathDirectory string) ([]method.Resource, error) {
+	items, err := method.GetResources(pathDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	// Пересобираем map в структуры через JSON, чтобы не разбирать поля вручную
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []method.Resource
+	if err := json.Unmarshal(data, &resources); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// FileInfo возвращает метаинформацию о файле, включая контрольные суммы
+func FileInfo(filePath string) (*method.Resource, error) {
+	return method.GetResourcesMeta(filePath)
+}
+
+// DeleteFile удаляет файл по полному пути
+func DeleteFile(filePath string, permanently bool) error {
+	dir, name := path.Split(path.Clean(filePath))
+	_, err := method.DeleteResources(strings.TrimSuffix(dir, "/"), name, permanently)
+	return err
+}
+
 // PrintDiskUsage выводит информацию о использовании диска
 func PrintDiskUsage() (string, error) {
-	fmt.Println("start PrintDiskUsage")
-
 	info, err := method.GetDiskInfo()
 	if err != nil {
 		return "", err
 	}
 
 	usagePercent := float64(info.UsedSpace) / float64(info.TotalSpace) * 100
-	// fmt.Printf("Usage: %.1f%%\n", usagePercent)
 
 	result := fmt.Sprintf(`
 💾 Yandex.Disk Usage:
@@ -103,7 +169,6 @@ Usage: %.1f%%`,
 		FormatBytes(info.TotalSpace-info.UsedSpace),
 		usagePercent,
 	)
-	fmt.Println("end PrintDiskUsage")
 	return result, nil
 }
 
@@ -1,18 +1,27 @@
 package initYD
 
 import (
-	"fmt"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"telegramBot/config"
+	"telegramBot/logging"
 )
 
 // YandexDiskAPI - структура клиента
 type YandexDiskAuth struct {
 	HostNameURL string
-	Token       string
 	Client      *http.Client
+	// TransferClient - для загрузки и скачивания файлов по ссылкам: без общего таймаута,
+	// иначе большие архивы не успевают передаться за время запроса к API
+	TransferClient *http.Client
+	token          atomic.Pointer[string] // меняется при подключении и обновлении через OAuth
+
+	// Настройки версионирования файлов
+	VersionsKeep   int
+	VersionsMaxAge time.Duration
+	VersionedPaths []string
 }
 
 // Глобальная переменная (неэкспортируемая)
@@ -27,30 +36,44 @@ func GetYandexDiskAPI() *YandexDiskAuth {
 }
 
 // NewYandexDiskAPI - создает новый экземпляр (приватный)
-func newYandexDiskAPI() *YandexDiskAuth {
-	config := config.LoadConfig()
-
-	return &YandexDiskAuth{
-		HostNameURL: config.UrlYandexDisk,
-		Token:       config.YandexDiskToken,
+func newYandexDiskAPI(config *config.Config) *YandexDiskAuth {
+	transport := &http.Transport{
+		MaxIdleConns:       10,
+		IdleConnTimeout:    30 * time.Second,
+		DisableCompression: false,
+		// Зависшее соединение при передаче файла обрывается, если сервер долго не отвечает
+		ResponseHeaderTimeout: 5 * time.Minute,
+	}
+	api := &YandexDiskAuth{
+		HostNameURL:    config.UrlYandexDisk,
+		VersionsKeep:   config.VersionsKeep,
+		VersionsMaxAge: config.VersionsMaxAge,
+		VersionedPaths: config.VersionedPaths,
 		Client: &http.Client{
-			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
-				MaxIdleConns:       10,
-				IdleConnTimeout:    30 * time.Second,
-				DisableCompression: false,
-			},
+			Timeout:   30 * time.Second,
+			Transport: transport,
 		},
+		TransferClient: &http.Client{Transport: transport},
 	}
+	api.SetToken(config.YandexDiskToken)
+	return api
+}
+
+// Token возвращает текущий OAuth-токен
+func (a *YandexDiskAuth) Token() string {
+	return *a.token.Load()
+}
+
+// SetToken заменяет токен для следующих запросов
+func (a *YandexDiskAuth) SetToken(token string) {
+	a.token.Store(&token)
 }
 
 // InitYandexDisk - инициализация глобального экземпляра (вызывается один раз)
-func InitYandexDisk() {
-	fmt.Println("🚀 Инициализация Yandex.Disk API...")
+func InitYandexDisk(config *config.Config) {
 
 	// Создаем и сохраняем в глобальную переменную
-	YandexDiskAPI = newYandexDiskAPI()
+	YandexDiskAPI = newYandexDiskAPI(config)
 
-	fmt.Printf("✅ Yandex.Disk API инициализирован. Token: %v\n",
-		YandexDiskAPI.Token != "")
+	logging.For("yandex").Info("✅ Yandex.Disk API инициализирован", "token", YandexDiskAPI.Token() != "")
 }
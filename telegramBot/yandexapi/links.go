@@ -0,0 +1,164 @@
+package yandexapi
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"telegramBot/yandexapi/authenticated"
+	"telegramBot/yandexapi/method"
+)
+
+const (
+	operationPollInterval = 2 * time.Second
+	operationTimeout      = 10 * time.Minute
+)
+
+// Хосты, на которых живут публичные ссылки Яндекс.Диска
+var publicLinkHosts = []string{
+	"disk.yandex.ru",
+	"disk.yandex.com",
+	"disk.yandex.by",
+	"disk.yandex.kz",
+	"disk.360.yandex.ru",
+	"yadi.sk",
+}
+
+// IsPublicLink проверяет, ведет ли ссылка на публичный ресурс Яндекс.Диска
+func IsPublicLink(link string) bool {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	for _, publicHost := range publicLinkHosts {
+		if host == publicHost {
+			return strings.HasPrefix(parsed.Path, "/d/") ||
+				strings.HasPrefix(parsed.Path, "/i/") ||
+				strings.HasPrefix(parsed.Path, "/public")
+		}
+	}
+	return false
+}
+
+// SavePublicResource сохраняет публичный файл или папку в folder на своем диске
+func SavePublicResource(publicKey string, folder string) (*method.Resource, error) {
+	public, err := method.GetPublicResources(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения публичного ресурса: %w", err)
+	}
+
+	if err := EnsureDirectory(folder); err != nil {
+		return nil, fmt.Errorf("ошибка создания папки %s: %w", folder, err)
+	}
+
+	name, err := freeName(folder, public.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("💾 Сохранение публичного ресурса", "name", public.Name, "dir", folder, "as", name)
+	link, err := method.PostPublicResourcesSaveToDisk(publicKey, folder, name)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сохранения на диск: %w", err)
+	}
+
+	// 201 - ресурс уже скопирован, 202 - ссылка на асинхронную операцию
+	if strings.Contains(link.Href, "/operations/") {
+		if err := waitOperation(link.Href); err != nil {
+			return nil, err
+		}
+	}
+
+	saved, err := method.GetResourcesMeta(path.Join(folder, name))
+	if err != nil {
+		return nil, fmt.Errorf("ресурс сохранен, но не удалось получить его размер: %w", err)
+	}
+	if saved.Size == 0 {
+		saved.Size = public.Size
+	}
+	return saved, nil
+}
+
+// UploadFromURL скачивает файл по ссылке силами Яндекс.Диска и ждет окончания загрузки
+func UploadFromURL(fileURL string, folder string) (*method.Resource, error) {
+	if err := EnsureDirectory(folder); err != nil {
+		return nil, fmt.Errorf("ошибка создания папки %s: %w", folder, err)
+	}
+
+	name, err := freeName(folder, fileNameFromURL(fileURL))
+	if err != nil {
+		return nil, err
+	}
+	remotePath := path.Join(folder, name)
+
+	logger.Info("🌐 Загрузка по ссылке", "url", fileURL, "path", remotePath)
+	link, err := method.PostResourcesUploadURL(fileURL, remotePath)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запуска загрузки по ссылке: %w", err)
+	}
+
+	if err := waitOperation(link.Href); err != nil {
+		return nil, err
+	}
+
+	saved, err := method.GetResourcesMeta(remotePath)
+	if err != nil {
+		return nil, fmt.Errorf("файл загружен, но не удалось получить его размер: %w", err)
+	}
+	return saved, nil
+}
+
+// waitOperation опрашивает асинхронную операцию до ее завершения
+func waitOperation(operationHref string) error {
+	deadline := time.Now().Add(operationTimeout)
+
+	for time.Now().Before(deadline) {
+		status, err := method.GetOperations(operationHref)
+		if err != nil {
+			return fmt.Errorf("ошибка получения статуса операции: %w", err)
+		}
+
+		switch status {
+		case method.OperationSuccess:
+			return nil
+		case method.OperationFailed:
+			return fmt.Errorf("Яндекс.Диск не смог выполнить операцию")
+		}
+
+		time.Sleep(operationPollInterval)
+	}
+	return fmt.Errorf("операция не завершилась за %v", operationTimeout)
+}
+
+// freeName подбирает имя, не занятое в папке: file.txt, file (1).txt, file (2).txt...
+func freeName(folder string, name string) (string, error) {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	candidate := name
+	for i := 1; i <= 100; i++ {
+		_, err := method.GetResourcesMeta(path.Join(folder, candidate))
+		if authenticated.IsNotFound(err) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("ошибка проверки имени файла: %w", err)
+		}
+		candidate = fmt.Sprintf("%s (%d)%s", base, i, ext)
+	}
+	return "", fmt.Errorf("не удалось подобрать свободное имя для %s", name)
+}
+
+// fileNameFromURL берет имя файла из пути ссылки
+func fileNameFromURL(fileURL string) string {
+	if parsed, err := url.Parse(fileURL); err == nil {
+		if name := path.Base(parsed.Path); name != "." && name != "/" && name != "" {
+			return name
+		}
+	}
+	return fmt.Sprintf("download_%d", time.Now().Unix())
+}
@@ -2,15 +2,17 @@ package method
 
 import (
 	"encoding/json"
+	"strconv"
 
 	. "telegramBot/yandexapi/authenticated"
 )
 
-func DeleteResources(pathDirectory string, nameDirectory string) (map[string]interface{}, error) {
+// DeleteResources удаляет ресурс в корзину или, если permanently, безвозвратно
+func DeleteResources(pathDirectory string, nameDirectory string, permanently bool) (map[string]interface{}, error) {
 
 	params := map[string]string{
 		"path":        pathDirectory + "/" + nameDirectory,
-		"permanently": "false",
+		"permanently": strconv.FormatBool(permanently),
 	}
 
 	body, err := AuthenticatedRequest("DELETE", "/resources", params, nil)
@@ -19,6 +21,10 @@ func DeleteResources(pathDirectory string, nameDirectory string) (map[string]int
 	}
 
 	var result map[string]interface{}
+	// DELETE отвечает 204 без тела
+	if len(body) == 0 {
+		return result, nil
+	}
 	err = json.Unmarshal(body, &result)
 	if err != nil {
 		return nil, err
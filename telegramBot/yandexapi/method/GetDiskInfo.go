@@ -1,9 +1,8 @@
 package method
 
 import (
-	"fmt"
-
 	"encoding/json"
+
 	"telegramBot/yandexapi/authenticated"
 )
 
@@ -15,7 +14,6 @@ type DiskInfo struct {
 
 // // GetDiskInfo получает информацию о диске
 func GetDiskInfo() (*DiskInfo, error) {
-	fmt.Println("start GetDiskInfo")
 	body, err := authenticated.AuthenticatedRequest("GET", "", nil, nil)
 	if err != nil {
 		return nil, err
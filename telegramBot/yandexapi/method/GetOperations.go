@@ -0,0 +1,32 @@
+package method
+
+import (
+	"encoding/json"
+
+	"telegramBot/yandexapi/authenticated"
+)
+
+// Статусы асинхронной операции
+const (
+	OperationSuccess    = "success"
+	OperationFailed     = "failed"
+	OperationInProgress = "in-progress"
+)
+
+// GetOperations возвращает статус асинхронной операции по ссылке из ответа API
+func GetOperations(operationHref string) (string, error) {
+	body, err := authenticated.AuthenticatedRequest("GET", operationHref, nil, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var operation struct {
+		Status string `json:"status"`
+	}
+	err = json.Unmarshal(body, &operation)
+	if err != nil {
+		return "", err
+	}
+
+	return operation.Status, nil
+}
@@ -0,0 +1,28 @@
+package method
+
+import (
+	"encoding/json"
+
+	"telegramBot/yandexapi/authenticated"
+)
+
+// GetPublicResources возвращает метаинформацию о публичном ресурсе по ссылке или ключу
+func GetPublicResources(publicKey string) (*Resource, error) {
+	params := map[string]string{
+		"public_key": publicKey,
+		"limit":      "0",
+	}
+
+	body, err := authenticated.AuthenticatedRequest("GET", "/public/resources", params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resource Resource
+	err = json.Unmarshal(body, &resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resource, nil
+}
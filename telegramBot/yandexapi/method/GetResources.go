@@ -25,7 +25,7 @@ func GetResources(pathDirectory string) ([]map[string]interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	fmt.Println("GetResources. BODY: %s", result)
+	logger.Debug("📂 Ответ GetResources", "path", pathDirectory, "body", fmt.Sprint(result))
 
 	embedded, ok := result["_embedded"].(map[string]interface{})
 	if !ok {
@@ -0,0 +1,34 @@
+package method
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"telegramBot/yandexapi/authenticated"
+)
+
+// GetResourcesDownload получает ссылку для скачивания файла с Яндекс.Диска
+func GetResourcesDownload(path string) (string, error) {
+	params := map[string]string{
+		"path": path,
+	}
+
+	body, err := authenticated.AuthenticatedRequest("GET", "/resources/download", params, nil)
+	if err != nil {
+		return "", fmt.Errorf("ошибка получения download URL: %v", err)
+	}
+
+	var response struct {
+		HREF string `json:"href"`
+	}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return "", fmt.Errorf("ошибка парсинга ответа: %v", err)
+	}
+
+	if response.HREF == "" {
+		return "", fmt.Errorf("пустой download URL в ответе")
+	}
+
+	return response.HREF, nil
+}
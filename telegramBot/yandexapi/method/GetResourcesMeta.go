@@ -0,0 +1,42 @@
+package method
+
+import (
+	"encoding/json"
+	"time"
+
+	"telegramBot/yandexapi/authenticated"
+)
+
+// Resource - метаинформация о файле или папке на Яндекс.Диске
+type Resource struct {
+	Name     string    `json:"name"`
+	Path     string    `json:"path"`
+	Type     string    `json:"type"`
+	Size     int64     `json:"size"`
+	MimeType string    `json:"mime_type"`
+	MD5      string    `json:"md5"`
+	SHA256   string    `json:"sha256"`
+	Created  time.Time `json:"created"`
+	Modified time.Time `json:"modified"`
+}
+
+// GetResourcesMeta возвращает метаинформацию о ресурсе без списка вложенных элементов
+func GetResourcesMeta(path string) (*Resource, error) {
+	params := map[string]string{
+		"path":  path,
+		"limit": "0",
+	}
+
+	body, err := authenticated.AuthenticatedRequest("GET", "/resources", params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resource Resource
+	err = json.Unmarshal(body, &resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resource, nil
+}
@@ -10,11 +10,10 @@ import (
 // GetResourcesUpload получает URL для загрузки файла на Яндекс.Диск
 func GetResourcesUpload(remotePathDirectory string, fileName string) (string, error) {
 	params := map[string]string{
-		"path": remotePathDirectory + "/" + fileName,
+		"path":      remotePathDirectory + "/" + fileName,
+		"overwrite": "true",
 	}
 
-	fmt.Printf("🔗 Запрос GET upload URL для: %s\n", remotePathDirectory+"/"+fileName)
-
 	body, err := authenticated.AuthenticatedRequest("GET", "/resources/upload", params, nil)
 	if err != nil {
 		return "", fmt.Errorf("ошибка получения upload URL: %v", err)
@@ -32,6 +31,5 @@ func GetResourcesUpload(remotePathDirectory string, fileName string) (string, er
 		return "", fmt.Errorf("пустой upload URL в ответе")
 	}
 
-	fmt.Printf("✅ Получен upload URL: %s\n", response.HREF)
 	return response.HREF, nil
 }
@@ -0,0 +1,38 @@
+package method
+
+import (
+	"encoding/json"
+
+	"telegramBot/yandexapi/authenticated"
+)
+
+// Link - ссылка из ответа API: на созданный ресурс или на асинхронную операцию
+type Link struct {
+	Href      string `json:"href"`
+	Method    string `json:"method"`
+	Templated bool   `json:"templated"`
+}
+
+// PostPublicResourcesSaveToDisk сохраняет публичный ресурс в папку savePath под именем name
+func PostPublicResourcesSaveToDisk(publicKey string, savePath string, name string) (*Link, error) {
+	params := map[string]string{
+		"public_key": publicKey,
+		"save_path":  savePath,
+	}
+	if name != "" {
+		params["name"] = name
+	}
+
+	body, err := authenticated.AuthenticatedRequest("POST", "/public/resources/save-to-disk", params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var link Link
+	err = json.Unmarshal(body, &link)
+	if err != nil {
+		return nil, err
+	}
+
+	return &link, nil
+}
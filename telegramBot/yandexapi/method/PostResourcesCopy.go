@@ -0,0 +1,30 @@
+package method
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"telegramBot/yandexapi/authenticated"
+)
+
+// PostResourcesCopy копирует ресурс from в path
+func PostResourcesCopy(from string, path string, overwrite bool) (map[string]interface{}, error) {
+	params := map[string]string{
+		"from":      from,
+		"path":      path,
+		"overwrite": strconv.FormatBool(overwrite),
+	}
+
+	body, err := authenticated.AuthenticatedRequest("POST", "/resources/copy", params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"fmt"
 
+	"telegramBot/logging"
 	"telegramBot/yandexapi/authenticated"
 )
 
+var logger = logging.For("yandex")
+
 // PostResourcesUpload загружает файл на Яндекс.Диск
 func PostResourcesUpload(remotePathDirectory string, fileData []byte, contentType string, fileSize int64, fileName string) error {
 
@@ -15,23 +18,18 @@ func PostResourcesUpload(remotePathDirectory string, fileData []byte, contentTyp
 		return fmt.Errorf("ошибка получения upload URL: %v", err)
 	}
 
-	fmt.Printf("🔗 Получен POST upload URL для: %s\n", remotePathDirectory)
-	fmt.Printf("📤 Загрузка файла: %d bytes, тип: %s\n", len(fileData), contentType)
-
 	// Создаем reader для файловых данных
 	reader := bytes.NewReader(fileData)
 
 	// Используем authenticatedRequest для загрузки файла (PUT запрос)
-	responseBody, err := authenticated.AuthenticatedRequest("POST", uploadURL, nil, reader)
+	responseBody, err := authenticated.AuthenticatedRequest("PUT", uploadURL, nil, reader)
 	if err != nil {
 		return fmt.Errorf("ошибка загрузки файла через authenticatedRequest: %v", err)
 	}
 
-	fmt.Printf("✅ Файл успешно загружен на Яндекс.Диск\n")
-
 	// Логируем ответ от сервера (если есть)
 	if len(responseBody) > 0 {
-		fmt.Printf("📨 Ответ от Яндекс.Диска: %s\n", string(responseBody))
+		logger.Debug("📨 Ответ на загрузку", "body", string(responseBody))
 	}
 
 	return nil
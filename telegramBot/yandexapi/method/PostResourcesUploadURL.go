@@ -0,0 +1,29 @@
+package method
+
+import (
+	"encoding/json"
+
+	"telegramBot/yandexapi/authenticated"
+)
+
+// PostResourcesUploadURL просит Яндекс.Диск скачать файл по ссылке fileURL в path.
+// Загрузка выполняется асинхронно, в ответе - ссылка на операцию.
+func PostResourcesUploadURL(fileURL string, path string) (*Link, error) {
+	params := map[string]string{
+		"url":  fileURL,
+		"path": path,
+	}
+
+	body, err := authenticated.AuthenticatedRequest("POST", "/resources/upload", params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var link Link
+	err = json.Unmarshal(body, &link)
+	if err != nil {
+		return nil, err
+	}
+
+	return &link, nil
+}
@@ -0,0 +1,339 @@
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"telegramBot/alerting"
+	"telegramBot/config"
+	"telegramBot/logging"
+)
+
+var logger = logging.For("yandex")
+
+const (
+	requestTimeout = 15 * time.Second
+	retryInterval  = 15 * time.Minute // повтор после неудачного обновления токена
+	minRefreshWait = time.Minute      // не чаще, даже если токен уже истек или живет недолго
+)
+
+// На сколько увеличивать интервал опроса после ответа slow_down
+var slowDownStep = 5 * time.Second
+
+// Token - токены Яндекс OAuth. Хранятся на диске зашифрованными ключом SECRETS_KEY
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	Expiry       time.Time `json:"expiry"`
+	Issued       time.Time `json:"issued,omitzero"`
+}
+
+// DeviceCode - код подтверждения, который пользователь вводит на странице Яндекса
+type DeviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURL string `json:"verification_url"`
+	Interval        int    `json:"interval"`
+	ExpiresIn       int    `json:"expires_in"`
+}
+
+// Error - ошибка, которую вернул OAuth-сервер
+type Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *Error) Error() string {
+	if e.Description == "" {
+		return "OAuth: " + e.Code
+	}
+	return fmt.Sprintf("OAuth: %s (%s)", e.Code, e.Description)
+}
+
+// Manager получает токены по /connectdisk, хранит их и обновляет до истечения
+type Manager struct {
+	config  config.YandexOAuthConfig
+	path    string
+	client  *http.Client
+	onToken func(accessToken string)
+	alerts  *alerting.Tracker
+
+	mu    sync.Mutex
+	token *Token
+	wake  chan struct{} // пересчитать время обновления после смены токена
+}
+
+// NewManager создает менеджер токенов. onToken вызывается с каждым новым токеном доступа,
+// notify - для предупреждений администраторам
+func NewManager(config config.YandexOAuthConfig, path string, onToken func(accessToken string), notify func(text string)) *Manager {
+	return &Manager{
+		config:  config,
+		path:    path,
+		client:  &http.Client{Timeout: requestTimeout},
+		onToken: onToken,
+		alerts:  alerting.NewTracker(notify),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Enabled - задано ли OAuth-приложение
+func (m *Manager) Enabled() bool {
+	return m.config.ClientID != ""
+}
+
+// Load читает сохраненные токены. Отсутствие файла - не ошибка: Диск еще не подключали
+func (m *Manager) Load() error {
+	data, err := os.ReadFile(m.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	key, err := config.SecretsKey()
+	if err != nil {
+		return err
+	}
+	plaintext, err := config.DecryptSecrets(key, data)
+	if err != nil {
+		return fmt.Errorf("%s: %v", m.path, err)
+	}
+
+	var token Token
+	if err := json.Unmarshal(plaintext, &token); err != nil {
+		return fmt.Errorf("%s: %v", m.path, err)
+	}
+	logging.AddSecret(token.AccessToken)
+	logging.AddSecret(token.RefreshToken)
+	m.setToken(&token)
+	logger.Info("🔑 Токен Яндекс.Диска загружен", "expiry", token.Expiry.Format(time.DateTime))
+	return nil
+}
+
+// Token возвращает текущие токены или nil, если Диск не подключен через OAuth
+func (m *Manager) Token() *Token {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.token
+}
+
+// Start обновляет токен в фоне за RefreshBefore до истечения, но не позже середины срока жизни токена
+func (m *Manager) Start() {
+	go func() {
+		for {
+			timer := time.NewTimer(m.untilRefresh())
+			select {
+			case <-timer.C:
+				m.refreshOrWarn()
+			case <-m.wake:
+				timer.Stop()
+			}
+		}
+	}()
+}
+
+// untilRefresh - сколько ждать до следующего обновления
+func (m *Manager) untilRefresh() time.Duration {
+	token := m.Token()
+	if token == nil || token.RefreshToken == "" {
+		return 24 * time.Hour
+	}
+	before := m.config.RefreshBefore
+	if !token.Issued.IsZero() {
+		// Токен, который живет меньше RefreshBefore, иначе обновлялся бы сразу после получения
+		before = min(before, token.Expiry.Sub(token.Issued)/2)
+	}
+	wait := time.Until(token.Expiry.Add(-before))
+	if m.alerts.IsActive("refresh") && wait < retryInterval {
+		return retryInterval
+	}
+	return max(wait, minRefreshWait)
+}
+
+func (m *Manager) refreshOrWarn() {
+	err := m.Refresh()
+	if err == nil {
+		m.alerts.Clear("refresh", "Токен Яндекс.Диска обновлен")
+		return
+	}
+
+	logger.Error("❌ Ошибка обновления токена Яндекс.Диска", "error", err)
+	message := fmt.Sprintf("Не удалось обновить токен Яндекс.Диска: %s", html.EscapeString(err.Error()))
+	if token := m.Token(); token != nil {
+		message += fmt.Sprintf("\nТокен действует до %s. Подключите Диск заново: /connectdisk", token.Expiry.Format("02.01.2006 15:04"))
+	}
+	m.alerts.Raise("refresh", message)
+}
+
+// AuthorizeURL - страница, где пользователь разрешает доступ и получает код для /connectdisk <код>
+func (m *Manager) AuthorizeURL() string {
+	query := url.Values{"response_type": {"code"}, "client_id": {m.config.ClientID}}
+	return m.endpoint("/authorize") + "?" + query.Encode()
+}
+
+// RequestDeviceCode начинает вход по коду устройства
+func (m *Manager) RequestDeviceCode() (*DeviceCode, error) {
+	var code DeviceCode
+	if err := m.post("/device/code", url.Values{"client_id": {m.config.ClientID}}, &code); err != nil {
+		return nil, err
+	}
+	if code.Interval <= 0 {
+		code.Interval = 5
+	}
+	return &code, nil
+}
+
+// WaitDevice опрашивает сервер, пока пользователь не подтвердит код или код не истечет
+func (m *Manager) WaitDevice(code *DeviceCode) error {
+	interval := time.Duration(code.Interval) * time.Second
+	deadline := time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+
+	for time.Now().Before(deadline) {
+		time.Sleep(interval)
+
+		err := m.requestToken(url.Values{"grant_type": {"device_code"}, "code": {code.DeviceCode}})
+		var oauthErr *Error
+		if errors.As(err, &oauthErr) {
+			switch oauthErr.Code {
+			case "authorization_pending":
+				continue
+			case "slow_down":
+				interval += slowDownStep
+				continue
+			}
+		}
+		return err
+	}
+	return errors.New("код подтверждения истек, начните заново: /connectdisk")
+}
+
+// Exchange обменивает код подтверждения со страницы AuthorizeURL на токены
+func (m *Manager) Exchange(code string) error {
+	return m.requestToken(url.Values{"grant_type": {"authorization_code"}, "code": {code}})
+}
+
+// Refresh получает новый токен доступа по refresh-токену
+func (m *Manager) Refresh() error {
+	token := m.Token()
+	if token == nil || token.RefreshToken == "" {
+		return errors.New("нет refresh-токена, подключите Диск: /connectdisk")
+	}
+	return m.requestToken(url.Values{"grant_type": {"refresh_token"}, "refresh_token": {token.RefreshToken}})
+}
+
+// requestToken запрашивает токены, сохраняет их и передает новый токен доступа
+func (m *Manager) requestToken(form url.Values) error {
+	form.Set("client_id", m.config.ClientID)
+	form.Set("client_secret", m.config.ClientSecret)
+
+	var response struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+	if err := m.post("/token", form, &response); err != nil {
+		return err
+	}
+	if response.AccessToken == "" {
+		return errors.New("OAuth: в ответе нет access_token")
+	}
+	if response.ExpiresIn <= 0 {
+		return fmt.Errorf("OAuth: некорректный срок действия токена: expires_in=%d", response.ExpiresIn)
+	}
+	logging.AddSecret(response.AccessToken)
+	logging.AddSecret(response.RefreshToken)
+
+	now := time.Now()
+	token := &Token{
+		AccessToken:  response.AccessToken,
+		RefreshToken: response.RefreshToken,
+		Expiry:       now.Add(time.Duration(response.ExpiresIn) * time.Second),
+		Issued:       now,
+	}
+	// Яндекс может не выдать новый refresh-токен - тогда остается прежний
+	if previous := m.Token(); token.RefreshToken == "" && previous != nil {
+		token.RefreshToken = previous.RefreshToken
+	}
+	if err := m.save(token); err != nil {
+		return fmt.Errorf("токен получен, но не сохранен: %v", err)
+	}
+	m.setToken(token)
+	logger.Info("🔑 Получен токен Яндекс.Диска", "expiry", token.Expiry.Format(time.DateTime))
+	return nil
+}
+
+func (m *Manager) setToken(token *Token) {
+	m.mu.Lock()
+	m.token = token
+	m.mu.Unlock()
+
+	m.onToken(token.AccessToken)
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+// save шифрует токены ключом SECRETS_KEY и атомарно заменяет файл
+func (m *Manager) save(token *Token) error {
+	key, err := config.SecretsKey()
+	if err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	data, err := config.EncryptSecrets(key, plaintext)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(m.path), 0o700); err != nil {
+		return err
+	}
+	temp := m.path + ".tmp"
+	if err := os.WriteFile(temp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(temp, m.path)
+}
+
+// post отправляет форму на OAuth-сервер и декодирует JSON-ответ
+func (m *Manager) post(path string, form url.Values, out any) error {
+	response, err := m.client.PostForm(m.endpoint(path), form)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		var oauthErr Error
+		if json.Unmarshal(body, &oauthErr) == nil && oauthErr.Code != "" {
+			return &oauthErr
+		}
+		return fmt.Errorf("OAuth: HTTP %d: %s", response.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("ошибка разбора ответа OAuth: %v", err)
+	}
+	return nil
+}
+
+func (m *Manager) endpoint(path string) string {
+	return strings.TrimRight(m.config.URL, "/") + path
+}
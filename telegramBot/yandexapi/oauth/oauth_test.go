@@ -0,0 +1,215 @@
+package oauth
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"telegramBot/config"
+)
+
+// testServer - OAuth-сервер, который отвечает на /token по очереди заданными ответами
+type testServer struct {
+	t *testing.T
+
+	mu        sync.Mutex
+	responses []testResponse
+	forms     []map[string]string
+}
+
+type testResponse struct {
+	status int
+	body   string
+}
+
+func (s *testServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		s.t.Errorf("ошибка разбора формы: %v", err)
+	}
+	if r.Form.Get("client_id") != "client" {
+		s.t.Errorf("client_id = %q", r.Form.Get("client_id"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.URL.Path {
+	case "/device/code":
+		w.Write([]byte(`{"device_code":"device","user_code":"ABCD","verification_url":"https://ya.ru/device","expires_in":60}`))
+	case "/token":
+		form := map[string]string{}
+		for key := range r.Form {
+			form[key] = r.Form.Get(key)
+		}
+		s.forms = append(s.forms, form)
+		if len(s.responses) == 0 {
+			s.t.Errorf("лишний запрос токена: %v", form)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		response := s.responses[0]
+		s.responses = s.responses[1:]
+		w.WriteHeader(response.status)
+		w.Write([]byte(response.body))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newTestManager(t *testing.T, responses ...testResponse) (*Manager, *testServer, *[]string) {
+	t.Helper()
+	t.Setenv("SECRETS_KEY", base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+
+	server := &testServer{t: t, responses: responses}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	var tokens []string
+	cfg := config.YandexOAuthConfig{
+		URL:           httpServer.URL,
+		ClientID:      "client",
+		ClientSecret:  "secret",
+		RefreshBefore: 7 * 24 * time.Hour,
+	}
+	path := filepath.Join(t.TempDir(), "yandex_token.enc")
+	manager := NewManager(cfg, path, func(token string) { tokens = append(tokens, token) }, func(string) {})
+	return manager, server, &tokens
+}
+
+func TestWaitDevice(t *testing.T) {
+	slowDownStep = 10 * time.Millisecond
+	t.Cleanup(func() { slowDownStep = 5 * time.Second })
+
+	manager, server, tokens := newTestManager(t,
+		testResponse{http.StatusBadRequest, `{"error":"authorization_pending"}`},
+		testResponse{http.StatusBadRequest, `{"error":"slow_down"}`},
+		testResponse{http.StatusBadRequest, `{"error":"authorization_pending"}`},
+		testResponse{http.StatusOK, `{"access_token":"access","refresh_token":"refresh","expires_in":3600}`},
+	)
+
+	code, err := manager.RequestDeviceCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code.Interval != 5 || code.UserCode != "ABCD" {
+		t.Fatalf("код устройства: %+v", code)
+	}
+
+	code.Interval = 0
+	if err := manager.WaitDevice(code); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.forms) != 4 {
+		t.Fatalf("запросов токена: %d, ожидалось 4", len(server.forms))
+	}
+	if form := server.forms[0]; form["grant_type"] != "device_code" || form["code"] != "device" {
+		t.Fatalf("форма запроса: %v", form)
+	}
+	if token := manager.Token(); token == nil || token.AccessToken != "access" {
+		t.Fatalf("токен: %+v", token)
+	}
+	if len(*tokens) != 1 || (*tokens)[0] != "access" {
+		t.Fatalf("onToken: %v", *tokens)
+	}
+}
+
+func TestWaitDeviceError(t *testing.T) {
+	manager, _, _ := newTestManager(t,
+		testResponse{http.StatusBadRequest, `{"error":"access_denied","error_description":"отказано"}`},
+	)
+	err := manager.WaitDevice(&DeviceCode{DeviceCode: "device", ExpiresIn: 60})
+	if err == nil || !strings.Contains(err.Error(), "access_denied") {
+		t.Fatalf("ожидалась ошибка access_denied, получено %v", err)
+	}
+	if manager.Token() != nil {
+		t.Fatal("токен не должен сохраняться после ошибки")
+	}
+}
+
+func TestExchangeAndRefresh(t *testing.T) {
+	manager, server, tokens := newTestManager(t,
+		testResponse{http.StatusOK, `{"access_token":"first","refresh_token":"refresh","expires_in":3600}`},
+		testResponse{http.StatusOK, `{"access_token":"second","expires_in":3600}`},
+		testResponse{http.StatusOK, `{"access_token":"third","expires_in":0}`},
+	)
+
+	if err := manager.Exchange("code"); err != nil {
+		t.Fatal(err)
+	}
+	if form := server.forms[0]; form["grant_type"] != "authorization_code" || form["code"] != "code" || form["client_secret"] != "secret" {
+		t.Fatalf("форма обмена: %v", form)
+	}
+
+	if err := manager.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if form := server.forms[1]; form["grant_type"] != "refresh_token" || form["refresh_token"] != "refresh" {
+		t.Fatalf("форма обновления: %v", form)
+	}
+	// Новый refresh-токен не выдан - остается прежний
+	if token := manager.Token(); token.AccessToken != "second" || token.RefreshToken != "refresh" {
+		t.Fatalf("токен после обновления: %+v", token)
+	}
+
+	if err := manager.Refresh(); err == nil {
+		t.Fatal("ответ с expires_in=0 должен отклоняться")
+	}
+	if token := manager.Token(); token.AccessToken != "second" {
+		t.Fatalf("токен заменен некорректным ответом: %+v", token)
+	}
+	if strings.Join(*tokens, ",") != "first,second" {
+		t.Fatalf("onToken: %v", *tokens)
+	}
+
+	// Сохраненный токен читается заново
+	loaded := NewManager(manager.config, manager.path, func(string) {}, func(string) {})
+	if err := loaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if token := loaded.Token(); token == nil || token.AccessToken != "second" || token.RefreshToken != "refresh" || token.Issued.IsZero() {
+		t.Fatalf("загруженный токен: %+v", token)
+	}
+}
+
+func TestUntilRefresh(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name  string
+		token *Token
+		want  time.Duration
+	}{
+		{"нет токена", nil, 24 * time.Hour},
+		{"нет refresh-токена", &Token{AccessToken: "a", Expiry: now.Add(time.Hour)}, 24 * time.Hour},
+		{"долгий токен", &Token{RefreshToken: "r", Issued: now, Expiry: now.Add(365 * 24 * time.Hour)}, 358 * 24 * time.Hour},
+		{"токен короче RefreshBefore", &Token{RefreshToken: "r", Issued: now, Expiry: now.Add(time.Hour)}, 30 * time.Minute},
+		{"токен короче RefreshBefore без времени выдачи", &Token{RefreshToken: "r", Expiry: now.Add(time.Hour)}, minRefreshWait},
+		{"токен истек", &Token{RefreshToken: "r", Issued: now.Add(-2 * time.Hour), Expiry: now.Add(-time.Hour)}, minRefreshWait},
+		{"мгновенно истекающий токен", &Token{RefreshToken: "r", Issued: now, Expiry: now}, minRefreshWait},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			manager, _, _ := newTestManager(t)
+			manager.token = test.token
+
+			got := manager.untilRefresh()
+			if diff := test.want - got; diff < 0 || diff > time.Second {
+				t.Fatalf("untilRefresh = %v, ожидалось %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUntilRefreshAfterFailure(t *testing.T) {
+	manager, _, _ := newTestManager(t)
+	now := time.Now()
+	manager.token = &Token{RefreshToken: "r", Issued: now.Add(-time.Hour), Expiry: now.Add(time.Minute)}
+	manager.alerts.Raise("refresh", "ошибка")
+
+	if got := manager.untilRefresh(); got != retryInterval {
+		t.Fatalf("untilRefresh = %v, ожидалось %v", got, retryInterval)
+	}
+}
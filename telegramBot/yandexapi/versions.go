@@ -0,0 +1,209 @@
+package yandexapi
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"telegramBot/yandexapi/authenticated"
+	"telegramBot/yandexapi/initYD"
+	"telegramBot/yandexapi/method"
+)
+
+// Скрытая папка с предыдущими версиями файлов создается рядом с файлом:
+// /docs/passport.pdf -> /docs/.versions/passport.pdf/20240131T120000_passport.pdf
+const (
+	versionsDirName   = ".versions"
+	versionTimeLayout = "20060102T150405"
+)
+
+// FileVersion - сохраненная версия файла
+type FileVersion struct {
+	Name    string
+	Path    string
+	Created time.Time
+	Size    int64
+}
+
+// ListVersions возвращает версии файла, отсортированные от новых к старым
+func ListVersions(filePath string) ([]FileVersion, error) {
+	dir, fileName := path.Split(path.Clean(filePath))
+	versionsDir := versionsDirectory(dir, fileName)
+
+	items, err := method.GetResources(versionsDir)
+	if err != nil {
+		if authenticated.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var versions []FileVersion
+	for _, item := range items {
+		name, _ := item["name"].(string)
+		created, ok := parseVersionName(name, fileName)
+		if !ok {
+			continue
+		}
+		size, _ := item["size"].(float64)
+		versions = append(versions, FileVersion{
+			Name:    name,
+			Path:    path.Join(versionsDir, name),
+			Created: created,
+			Size:    int64(size),
+		})
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Created.After(versions[j].Created)
+	})
+	return versions, nil
+}
+
+// RestoreVersion возвращает версию с номером number (1 - самая свежая) на место файла.
+// Текущее содержимое файла перед этим само сохраняется как версия, поэтому восстановление
+// доступно только в папках с включенным версионированием.
+func RestoreVersion(filePath string, number int) (*FileVersion, error) {
+	filePath = path.Clean(filePath)
+	dir, fileName := path.Split(filePath)
+	if !isVersioned(dir) {
+		return nil, fmt.Errorf("версионирование для папки %s выключено (YANDEX_VERSIONS_KEEP, YANDEX_VERSIONED_PATHS): текущее содержимое файла некуда сохранить", path.Clean(dir))
+	}
+
+	versions, err := ListVersions(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if number < 1 || number > len(versions) {
+		return nil, fmt.Errorf("версия %d не найдена (доступно версий: %d)", number, len(versions))
+	}
+	version := versions[number-1]
+
+	if err := saveVersion(dir, fileName); err != nil {
+		return nil, err
+	}
+
+	if _, err := method.PostResourcesCopy(version.Path, filePath, true); err != nil {
+		return nil, fmt.Errorf("ошибка восстановления версии: %w", err)
+	}
+
+	logger.Info("♻️ Файл восстановлен из версии", "path", filePath, "version", version.Name)
+
+	if err := trimVersions(dir, fileName); err != nil {
+		logger.Warn("⚠️ Ошибка очистки старых версий", "path", filePath, "error", err)
+	}
+	return &version, nil
+}
+
+// isVersioned проверяет, включено ли версионирование для директории
+func isVersioned(dir string) bool {
+	apiAuth := initYD.GetYandexDiskAPI()
+	if apiAuth.VersionsKeep <= 0 {
+		return false
+	}
+	if len(apiAuth.VersionedPaths) == 0 {
+		return true
+	}
+
+	dir = path.Clean("/" + strings.TrimPrefix(dir, "disk:"))
+	for _, prefix := range apiAuth.VersionedPaths {
+		prefix = path.Clean("/" + strings.TrimPrefix(prefix, "disk:"))
+		if dir == prefix || prefix == "/" || strings.HasPrefix(dir, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// saveVersion переносит существующий файл в папку версий. Если файла нет - ничего не делает.
+func saveVersion(dir string, fileName string) error {
+	filePath := path.Join(dir, fileName)
+
+	current, err := method.GetResourcesMeta(filePath)
+	if err != nil {
+		if authenticated.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("ошибка проверки существующего файла: %w", err)
+	}
+	if current.Type != "file" {
+		return nil
+	}
+
+	versionsDir := versionsDirectory(dir, fileName)
+	if err := EnsureDirectory(versionsDir); err != nil {
+		return fmt.Errorf("ошибка создания папки версий: %w", err)
+	}
+
+	versionName := time.Now().Format(versionTimeLayout) + "_" + fileName
+	if _, err := method.PostResourcesMove(filePath, path.Join(versionsDir, versionName), true); err != nil {
+		return fmt.Errorf("ошибка сохранения предыдущей версии: %w", err)
+	}
+
+	logger.Info("🗂️ Предыдущая версия сохранена", "path", filePath, "version", versionName)
+	return nil
+}
+
+// trimVersions удаляет версии сверх лимита по количеству и возрасту.
+// VersionsKeep <= 0 - версионирование выключено, а не "хранить ноль версий": тогда ничего не удаляется
+func trimVersions(dir string, fileName string) error {
+	apiAuth := initYD.GetYandexDiskAPI()
+	if apiAuth.VersionsKeep <= 0 {
+		return nil
+	}
+
+	versions, err := ListVersions(path.Join(dir, fileName))
+	if err != nil {
+		return err
+	}
+
+	versionsDir := versionsDirectory(dir, fileName)
+	for i, version := range versions {
+		tooMany := i >= apiAuth.VersionsKeep
+		tooOld := apiAuth.VersionsMaxAge > 0 && time.Since(version.Created) > apiAuth.VersionsMaxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+
+		if _, err := method.DeleteResources(versionsDir, version.Name, false); err != nil {
+			return fmt.Errorf("ошибка удаления версии %s: %w", version.Name, err)
+		}
+		logger.Info("🧹 Удалена старая версия", "path", version.Path)
+	}
+	return nil
+}
+
+// EnsureDirectory создает директорию вместе со всеми родительскими
+func EnsureDirectory(dirPath string) error {
+	current := ""
+	for _, part := range strings.Split(strings.Trim(path.Clean(dirPath), "/"), "/") {
+		if part == "" {
+			continue
+		}
+		if _, err := method.PutResources(current, part); err != nil && !authenticated.IsConflict(err) {
+			return err
+		}
+		current += "/" + part
+	}
+	return nil
+}
+
+func versionsDirectory(dir string, fileName string) string {
+	return path.Join(dir, versionsDirName, fileName)
+}
+
+// parseVersionName извлекает время создания из имени версии вида 20240131T120000_<имя файла>
+func parseVersionName(name string, fileName string) (time.Time, bool) {
+	timestamp, original, ok := strings.Cut(name, "_")
+	if !ok || original != fileName {
+		return time.Time{}, false
+	}
+
+	created, err := time.ParseInLocation(versionTimeLayout, timestamp, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return created, true
+}
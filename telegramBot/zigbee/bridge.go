@@ -0,0 +1,260 @@
+package zigbee
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"telegramBot/logging"
+	"telegramBot/mqttclient"
+)
+
+var logger = logging.For("zigbee")
+
+// Bridge следит за устройствами zigbee2mqtt и их состояниями через MQTT
+type Bridge struct {
+	mqtt      *mqttclient.Client
+	baseTopic string
+
+	mu      sync.RWMutex
+	devices map[string]Device // ключ: friendly_name
+	states  map[string]*State
+	waiters map[string][]chan map[string]any
+	online  map[string]bool // из топиков <имя>/availability
+
+	eventHandlers  []EventHandler
+	pending        map[string]chan bridgeResponse // ключ: transaction
+	transactionSeq uint64
+}
+
+func NewBridge(client *mqttclient.Client, baseTopic string) *Bridge {
+	return &Bridge{
+		mqtt:      client,
+		baseTopic: strings.TrimSuffix(baseTopic, "/"),
+		devices:   make(map[string]Device),
+		states:    make(map[string]*State),
+		waiters:   make(map[string][]chan map[string]any),
+		online:    make(map[string]bool),
+		pending:   make(map[string]chan bridgeResponse),
+	}
+}
+
+// Start подписывается на все топики zigbee2mqtt
+func (b *Bridge) Start() error {
+	_, err := b.mqtt.Subscribe(b.baseTopic+"/#", b.handleMessage)
+	return err
+}
+
+func (b *Bridge) handleMessage(message mqttclient.Message) {
+	topic := strings.TrimPrefix(message.Topic, b.baseTopic+"/")
+
+	switch {
+	case topic == "bridge/devices":
+		b.handleDevices(message.Payload)
+	case topic == "bridge/event":
+		b.handleEvent(message.Payload)
+	case strings.HasPrefix(topic, "bridge/response/"):
+		b.handleResponse(message.Payload)
+	case strings.HasPrefix(topic, "bridge/"):
+		// Остальные служебные топики моста
+	case strings.HasSuffix(topic, "/availability"):
+		b.handleAvailability(strings.TrimSuffix(topic, "/availability"), message.Payload)
+	case strings.HasSuffix(topic, "/set") || strings.HasSuffix(topic, "/get"):
+		// Команды, в том числе наши собственные
+	default:
+		b.handleState(topic, message.Payload)
+	}
+}
+
+func (b *Bridge) handleDevices(payload []byte) {
+	var devices []Device
+	if err := json.Unmarshal(payload, &devices); err != nil {
+		logger.Error("❌ Ошибка разбора bridge/devices", "error", err)
+		return
+	}
+
+	b.mu.Lock()
+	b.devices = make(map[string]Device, len(devices))
+	for _, device := range devices {
+		b.devices[device.FriendlyName] = device
+	}
+	b.mu.Unlock()
+
+	logger.Info("🐝 Получен список устройств", "devices", len(devices))
+}
+
+func (b *Bridge) handleState(name string, payload []byte) {
+	var state map[string]any
+	if err := json.Unmarshal(payload, &state); err != nil {
+		// Не JSON-объект: например, availability в старом формате
+		return
+	}
+
+	b.mu.Lock()
+	current, ok := b.states[name]
+	if !ok {
+		current = &State{Payload: make(map[string]any)}
+		b.states[name] = current
+	}
+	for key, value := range state {
+		current.Payload[key] = value
+	}
+	current.Updated = time.Now()
+	waiters := b.waiters[name]
+	b.mu.Unlock()
+
+	for _, waiter := range waiters {
+		select {
+		case waiter <- state:
+		default:
+		}
+	}
+}
+
+// handleAvailability понимает оба формата: "online" и {"state":"online"}
+func (b *Bridge) handleAvailability(name string, payload []byte) {
+	value := strings.TrimSpace(string(payload))
+
+	var state struct {
+		State string `json:"state"`
+	}
+	if json.Unmarshal(payload, &state) == nil && state.State != "" {
+		value = state.State
+	}
+
+	b.mu.Lock()
+	b.online[name] = value == "online"
+	b.mu.Unlock()
+}
+
+// Availability возвращает доступность устройства. known == false, если
+// в zigbee2mqtt не включено отслеживание доступности
+func (b *Bridge) Availability(name string) (online bool, known bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	online, known = b.online[name]
+	return online, known
+}
+
+// Devices возвращает устройства (без координатора), отсортированные по имени
+func (b *Bridge) Devices() []Device {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	devices := make([]Device, 0, len(b.devices))
+	for _, device := range b.devices {
+		if device.Type != "Coordinator" {
+			devices = append(devices, device)
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		return strings.ToLower(devices[i].FriendlyName) < strings.ToLower(devices[j].FriendlyName)
+	})
+	return devices
+}
+
+// Device ищет устройство по имени без учета регистра
+func (b *Bridge) Device(name string) (Device, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if device, ok := b.devices[name]; ok {
+		return device, true
+	}
+	for friendlyName, device := range b.devices {
+		if strings.EqualFold(friendlyName, name) {
+			return device, true
+		}
+	}
+	return Device{}, false
+}
+
+// State возвращает копию последнего состояния устройства
+func (b *Bridge) State(name string) (State, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	state, ok := b.states[name]
+	if !ok {
+		return State{}, false
+	}
+
+	copied := State{Payload: make(map[string]any, len(state.Payload)), Updated: state.Updated}
+	for key, value := range state.Payload {
+		copied.Payload[key] = value
+	}
+	return copied, true
+}
+
+// Set публикует команду в <base>/<имя>/set и ждет, пока устройство подтвердит
+// новое состояние в своем топике
+func (b *Bridge) Set(name string, command map[string]any, timeout time.Duration) (State, error) {
+	payload, err := json.Marshal(command)
+	if err != nil {
+		return State{}, err
+	}
+
+	updates := make(chan map[string]any, 1)
+	b.addWaiter(name, updates)
+	defer b.removeWaiter(name, updates)
+
+	if err := b.mqtt.Publish(b.baseTopic+"/"+name+"/set", payload, false); err != nil {
+		return State{}, err
+	}
+
+	deadline := time.After(timeout)
+	for {
+		select {
+		case update := <-updates:
+			if confirms(command, update) {
+				state, _ := b.State(name)
+				return state, nil
+			}
+		case <-deadline:
+			return State{}, fmt.Errorf("устройство %s не подтвердило команду за %v", name, timeout)
+		}
+	}
+}
+
+// confirms проверяет, что в обновлении состояния пришли запрошенные значения
+func confirms(command map[string]any, update map[string]any) bool {
+	for key, want := range command {
+		got, ok := update[key]
+		if !ok {
+			return false
+		}
+		// TOGGLE подтверждается любым новым значением
+		if want == "TOGGLE" {
+			continue
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *Bridge) addWaiter(name string, waiter chan map[string]any) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.waiters[name] = append(b.waiters[name], waiter)
+}
+
+func (b *Bridge) removeWaiter(name string, waiter chan map[string]any) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	waiters := b.waiters[name]
+	for i, existing := range waiters {
+		if existing == waiter {
+			b.waiters[name] = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(b.waiters[name]) == 0 {
+		delete(b.waiters, name)
+	}
+}
@@ -0,0 +1,105 @@
+package zigbee
+
+import (
+	"strconv"
+	"time"
+)
+
+// Device - устройство из zigbee2mqtt/bridge/devices
+type Device struct {
+	IEEEAddress  string      `json:"ieee_address"`
+	FriendlyName string      `json:"friendly_name"`
+	Type         string      `json:"type"` // Coordinator, Router, EndDevice
+	Supported    bool        `json:"supported"`
+	Disabled     bool        `json:"disabled"`
+	Definition   *Definition `json:"definition"`
+}
+
+// Definition - описание модели устройства
+type Definition struct {
+	Model       string   `json:"model"`
+	Vendor      string   `json:"vendor"`
+	Description string   `json:"description"`
+	Exposes     []Expose `json:"exposes"`
+}
+
+// Expose - возможность устройства (свойство, которое можно читать или менять)
+type Expose struct {
+	Type     string   `json:"type"`
+	Name     string   `json:"name"`
+	Property string   `json:"property"`
+	Access   int      `json:"access"`
+	ValueMax *float64 `json:"value_max"`
+	Features []Expose `json:"features"`
+}
+
+// Бит access, означающий, что свойство можно изменить через /set
+const accessSet = 0b010
+
+// State - последнее известное состояние устройства
+type State struct {
+	Payload map[string]any
+	Updated time.Time
+}
+
+// Model возвращает модель устройства или пустую строку
+func (d Device) Model() string {
+	if d.Definition == nil {
+		return ""
+	}
+	return d.Definition.Model
+}
+
+// Settable ищет изменяемое свойство с именем property (например, state или brightness)
+func (d Device) Settable(property string) (Expose, bool) {
+	if d.Definition == nil {
+		return Expose{}, false
+	}
+	return findExpose(d.Definition.Exposes, property)
+}
+
+func findExpose(exposes []Expose, property string) (Expose, bool) {
+	for _, expose := range exposes {
+		if expose.Property == property && expose.Access&accessSet != 0 {
+			return expose, true
+		}
+		if found, ok := findExpose(expose.Features, property); ok {
+			return found, true
+		}
+	}
+	return Expose{}, false
+}
+
+// LastSeen берет last_seen из состояния (если он включен в zigbee2mqtt),
+// иначе - время последнего сообщения от устройства
+func (s State) LastSeen() time.Time {
+	switch value := s.Payload["last_seen"].(type) {
+	case string:
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			return parsed
+		}
+	case float64:
+		return time.UnixMilli(int64(value))
+	}
+	return s.Updated
+}
+
+// LinkQuality возвращает linkquality или -1, если устройство его не сообщает
+func (s State) LinkQuality() int {
+	if value, ok := s.Payload["linkquality"].(float64); ok {
+		return int(value)
+	}
+	return -1
+}
+
+// Number возвращает числовое поле состояния
+func (s State) Number(key string) (float64, bool) {
+	switch value := s.Payload[key].(type) {
+	case float64:
+		return value, true
+	case string:
+		parsed, err := strconv.ParseFloat(value, 64)
+		return parsed, err == nil
+	}
+	return 0, false
+}
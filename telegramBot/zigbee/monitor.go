@@ -0,0 +1,161 @@
+package zigbee
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"telegramBot/alerting"
+	"telegramBot/config"
+)
+
+// Monitor периодически проверяет батарейки, доступность и качество связи устройств
+type Monitor struct {
+	bridge *Bridge
+	config atomic.Pointer[config.ZigbeeConfig] // пороги меняются через Reconfigure
+	alerts *alerting.Tracker
+}
+
+func NewMonitor(bridge *Bridge, config config.ZigbeeConfig, notify func(text string)) *Monitor {
+	m := &Monitor{
+		bridge: bridge,
+		alerts: alerting.NewTracker(notify),
+	}
+	m.config.Store(&config)
+	return m
+}
+
+// Reconfigure применяет новые пороги без перезапуска. Интервал проверок не меняется
+func (m *Monitor) Reconfigure(config config.ZigbeeConfig) {
+	m.config.Store(&config)
+}
+
+// Start запускает проверки в фоне
+func (m *Monitor) Start() {
+	config := m.config.Load()
+	logger.Info("🩺 Мониторинг устройств", "interval", config.MonitorInterval)
+
+	go func() {
+		ticker := time.NewTicker(config.MonitorInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			m.check()
+		}
+	}()
+}
+
+func (m *Monitor) check() {
+	config := m.config.Load()
+	for _, device := range m.bridge.Devices() {
+		if device.Disabled {
+			continue
+		}
+		name := device.FriendlyName
+		escaped := html.EscapeString(name)
+		state, hasState := m.bridge.State(name)
+
+		// Доступность: топик availability, а если он выключен - давность last_seen
+		if online, known := m.bridge.Availability(name); known {
+			if online {
+				m.alerts.Clear(name+":offline", fmt.Sprintf("Устройство <b>%s</b> снова в сети", escaped))
+			} else {
+				m.alerts.Raise(name+":offline", fmt.Sprintf("Устройство <b>%s</b> недоступно", escaped))
+			}
+		} else if hasState {
+			lastSeen := state.LastSeen()
+			if time.Since(lastSeen) > config.OfflineAfter {
+				m.alerts.Raise(name+":offline", fmt.Sprintf("Устройство <b>%s</b> молчит с %s", escaped, lastSeen.Format("02.01 15:04")))
+			} else {
+				m.alerts.Clear(name+":offline", fmt.Sprintf("Устройство <b>%s</b> снова на связи", escaped))
+			}
+		}
+
+		if !hasState {
+			continue
+		}
+
+		if battery, ok := state.Number("battery"); ok {
+			switch {
+			case battery < float64(config.BatteryLow):
+				m.alerts.Raise(name+":battery", fmt.Sprintf("🪫 Низкий заряд <b>%s</b>: %.0f%%", escaped, battery))
+			case battery >= float64(config.BatteryLow+config.BatteryHysteresis):
+				m.alerts.Clear(name+":battery", fmt.Sprintf("🔋 Заряд <b>%s</b> в норме: %.0f%%", escaped, battery))
+			}
+		}
+
+		if linkQuality := state.LinkQuality(); linkQuality >= 0 {
+			switch {
+			case linkQuality < config.LinkQualityLow:
+				m.alerts.Raise(name+":linkquality", fmt.Sprintf("📶 Плохая связь с <b>%s</b>: linkquality %d", escaped, linkQuality))
+			case linkQuality >= config.LinkQualityLow+config.LinkQualityHysteresis:
+				m.alerts.Clear(name+":linkquality", fmt.Sprintf("📶 Связь с <b>%s</b> восстановилась: linkquality %d", escaped, linkQuality))
+			}
+		}
+	}
+}
+
+// Report готовит сводку для /health zigbee
+func (m *Monitor) Report() string {
+	devices := m.bridge.Devices()
+
+	type battery struct {
+		name  string
+		level float64
+	}
+	var batteries []battery
+	online, offline, unknown := 0, 0, 0
+
+	for _, device := range devices {
+		name := device.FriendlyName
+		state, hasState := m.bridge.State(name)
+
+		switch {
+		case m.alerts.IsActive(name + ":offline"):
+			offline++
+		case hasState:
+			online++
+		default:
+			unknown++
+		}
+
+		if hasState {
+			if level, ok := state.Number("battery"); ok {
+				batteries = append(batteries, battery{name: name, level: level})
+			}
+		}
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "🩺 <b>Состояние Zigbee-сети</b>\n")
+	fmt.Fprintf(&builder, "🐝 Устройств: <b>%d</b> · 🟢 на связи: <b>%d</b> · 🔴 недоступно: <b>%d</b>", len(devices), online, offline)
+	if unknown > 0 {
+		fmt.Fprintf(&builder, " · ❔ нет данных: <b>%d</b>", unknown)
+	}
+	fmt.Fprintln(&builder)
+
+	if len(batteries) > 0 {
+		sort.Slice(batteries, func(i, j int) bool { return batteries[i].level < batteries[j].level })
+		fmt.Fprintf(&builder, "\n🔋 <b>Батарейки</b> (самые разряженные):\n")
+		for i, item := range batteries {
+			if i == 5 {
+				break
+			}
+			fmt.Fprintf(&builder, "• %s: %.0f%%\n", html.EscapeString(item.name), item.level)
+		}
+	}
+
+	alerts := m.alerts.Active()
+	if len(alerts) == 0 {
+		fmt.Fprint(&builder, "\n✅ Активных проблем нет")
+		return builder.String()
+	}
+
+	fmt.Fprintf(&builder, "\n🚨 <b>Активные проблемы</b> (%d):\n", len(alerts))
+	for _, alert := range alerts {
+		fmt.Fprintf(&builder, "• %s <i>(с %s)</i>\n", alert.Message, alert.Since.Format("02.01 15:04"))
+	}
+	return builder.String()
+}
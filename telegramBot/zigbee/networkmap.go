@@ -0,0 +1,59 @@
+package zigbee
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Сканирование сети опрашивает все роутеры и может занимать минуту и больше
+const networkMapTimeout = 3 * time.Minute
+
+// NetworkMap - ответ bridge/response/networkmap в формате raw
+type NetworkMap struct {
+	Nodes []MapNode `json:"nodes"`
+	Links []MapLink `json:"links"`
+}
+
+type MapNode struct {
+	IEEEAddress  string `json:"ieeeAddr"`
+	FriendlyName string `json:"friendlyName"`
+	Type         string `json:"type"` // Coordinator, Router, EndDevice
+	Failed       []any  `json:"failed"`
+}
+
+type MapLink struct {
+	Source struct {
+		IEEEAddress string `json:"ieeeAddr"`
+	} `json:"source"`
+	Target struct {
+		IEEEAddress string `json:"ieeeAddr"`
+	} `json:"target"`
+	LinkQuality  int `json:"linkquality"`
+	Relationship int `json:"relationship"` // 0 - родитель, 1 - потомок, 2 - сосед
+	Depth        int `json:"depth"`
+}
+
+// NetworkMap запрашивает карту сети у zigbee2mqtt. Возвращает разобранную карту и исходный JSON.
+func (b *Bridge) NetworkMap() (*NetworkMap, json.RawMessage, error) {
+	data, err := b.Request("networkmap", map[string]any{
+		"type":   "raw",
+		"routes": false,
+	}, networkMapTimeout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var response struct {
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, nil, fmt.Errorf("ошибка разбора карты сети: %w", err)
+	}
+
+	var networkMap NetworkMap
+	if err := json.Unmarshal(response.Value, &networkMap); err != nil {
+		return nil, nil, fmt.Errorf("ошибка разбора карты сети: %w", err)
+	}
+	return &networkMap, response.Value, nil
+}
@@ -0,0 +1,264 @@
+package zigbee
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"sort"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	mapSize         = 1400
+	routerRadius    = 330
+	endDeviceRadius = 580
+	nodeRadius      = 14
+)
+
+var (
+	colorBackground  = color.RGBA{255, 255, 255, 255}
+	colorText        = color.RGBA{33, 33, 33, 255}
+	colorCoordinator = color.RGBA{63, 81, 181, 255}
+	colorRouter      = color.RGBA{255, 152, 0, 255}
+	colorEndDevice   = color.RGBA{76, 175, 80, 255}
+	colorFailed      = color.RGBA{158, 158, 158, 255}
+)
+
+// Шрифт Go поддерживает кириллицу, поэтому имена устройств выводятся как есть
+var (
+	fontOnce  sync.Once
+	labelFace font.Face
+	smallFace font.Face
+	fontErr   error
+)
+
+func loadFonts() error {
+	fontOnce.Do(func() {
+		parsed, err := opentype.Parse(goregular.TTF)
+		if err != nil {
+			fontErr = err
+			return
+		}
+		if labelFace, err = opentype.NewFace(parsed, &opentype.FaceOptions{Size: 15, DPI: 72, Hinting: font.HintingFull}); err != nil {
+			fontErr = err
+			return
+		}
+		smallFace, fontErr = opentype.NewFace(parsed, &opentype.FaceOptions{Size: 12, DPI: 72, Hinting: font.HintingFull})
+	})
+	return fontErr
+}
+
+type point struct{ x, y float64 }
+
+// RenderNetworkMap рисует карту сети в PNG: координатор в центре, роутеры на внутреннем
+// круге, конечные устройства на внешнем рядом со своими родителями. Связи подписаны linkquality.
+func RenderNetworkMap(networkMap *NetworkMap) ([]byte, error) {
+	if err := loadFonts(); err != nil {
+		return nil, fmt.Errorf("ошибка загрузки шрифта: %w", err)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, mapSize, mapSize))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+
+	positions := layoutNodes(networkMap)
+
+	// Связи рисуем первыми, чтобы узлы оказались поверх линий
+	drawn := make(map[[2]string]bool)
+	for _, link := range networkMap.Links {
+		from, okFrom := positions[link.Source.IEEEAddress]
+		to, okTo := positions[link.Target.IEEEAddress]
+		key := [2]string{link.Source.IEEEAddress, link.Target.IEEEAddress}
+		if key[0] > key[1] {
+			key[0], key[1] = key[1], key[0]
+		}
+		if !okFrom || !okTo || drawn[key] {
+			continue
+		}
+		drawn[key] = true
+
+		lineColor := linkQualityColor(link.LinkQuality)
+		drawLine(img, from, to, lineColor)
+		drawLabel(img, smallFace, fmt.Sprint(link.LinkQuality), point{(from.x + to.x) / 2, (from.y + to.y) / 2}, lineColor, true)
+	}
+
+	for _, node := range networkMap.Nodes {
+		position := positions[node.IEEEAddress]
+		fill := colorEndDevice
+		switch {
+		case len(node.Failed) > 0:
+			fill = colorFailed
+		case node.Type == "Coordinator":
+			fill = colorCoordinator
+		case node.Type == "Router":
+			fill = colorRouter
+		}
+
+		radius := float64(nodeRadius)
+		if node.Type == "Coordinator" {
+			radius *= 1.6
+		}
+		drawCircle(img, position, radius, fill)
+
+		name := node.FriendlyName
+		if node.Type == "Coordinator" {
+			name = "Координатор"
+		}
+		drawLabel(img, labelFace, name, point{position.x, position.y + radius + 14}, colorText, false)
+	}
+
+	drawLegend(img, len(networkMap.Nodes), len(drawn))
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// layoutNodes раскладывает узлы по кругам
+func layoutNodes(networkMap *NetworkMap) map[string]point {
+	center := point{mapSize / 2, mapSize / 2}
+	positions := make(map[string]point)
+
+	var routers, endDevices []MapNode
+	for _, node := range networkMap.Nodes {
+		switch node.Type {
+		case "Coordinator":
+			positions[node.IEEEAddress] = center
+		case "Router":
+			routers = append(routers, node)
+		default:
+			endDevices = append(endDevices, node)
+		}
+	}
+
+	sort.Slice(routers, func(i, j int) bool { return routers[i].FriendlyName < routers[j].FriendlyName })
+	angles := make(map[string]float64)
+	for i, router := range routers {
+		angle := 2 * math.Pi * float64(i) / float64(len(routers))
+		angles[router.IEEEAddress] = angle
+		positions[router.IEEEAddress] = polar(center, routerRadius, angle)
+	}
+
+	// Конечное устройство ставим рядом с родителем, чтобы связи не пересекали всю картинку
+	parents := make(map[string]string)
+	for _, link := range networkMap.Links {
+		if link.Relationship == 0 {
+			parents[link.Source.IEEEAddress] = link.Target.IEEEAddress
+		}
+		if link.Relationship == 1 {
+			parents[link.Target.IEEEAddress] = link.Source.IEEEAddress
+		}
+	}
+	sort.Slice(endDevices, func(i, j int) bool {
+		angleI, angleJ := angles[parents[endDevices[i].IEEEAddress]], angles[parents[endDevices[j].IEEEAddress]]
+		if angleI != angleJ {
+			return angleI < angleJ
+		}
+		return endDevices[i].FriendlyName < endDevices[j].FriendlyName
+	})
+	for i, device := range endDevices {
+		angle := 2 * math.Pi * (float64(i) + 0.5) / float64(len(endDevices))
+		positions[device.IEEEAddress] = polar(center, endDeviceRadius, angle)
+	}
+
+	return positions
+}
+
+func polar(center point, radius float64, angle float64) point {
+	return point{center.x + radius*math.Cos(angle), center.y + radius*math.Sin(angle)}
+}
+
+// linkQualityColor: красный - плохая связь, зеленый - хорошая
+func linkQualityColor(linkQuality int) color.RGBA {
+	switch {
+	case linkQuality < 50:
+		return color.RGBA{229, 57, 53, 255}
+	case linkQuality < 100:
+		return color.RGBA{251, 140, 0, 255}
+	case linkQuality < 150:
+		return color.RGBA{192, 202, 51, 255}
+	}
+	return color.RGBA{67, 160, 71, 255}
+}
+
+// drawLine рисует отрезок толщиной 2 пикселя
+func drawLine(img *image.RGBA, from point, to point, lineColor color.Color) {
+	dx, dy := to.x-from.x, to.y-from.y
+	steps := int(math.Max(math.Abs(dx), math.Abs(dy)))
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(max(steps, 1))
+		x, y := int(from.x+dx*t), int(from.y+dy*t)
+		img.Set(x, y, lineColor)
+		img.Set(x+1, y, lineColor)
+		img.Set(x, y+1, lineColor)
+	}
+}
+
+func drawCircle(img *image.RGBA, center point, radius float64, fill color.Color) {
+	for y := int(center.y - radius); y <= int(center.y+radius); y++ {
+		for x := int(center.x - radius); x <= int(center.x+radius); x++ {
+			distance := math.Hypot(float64(x)-center.x, float64(y)-center.y)
+			switch {
+			case distance <= radius-2:
+				img.Set(x, y, fill)
+			case distance <= radius:
+				img.Set(x, y, colorText)
+			}
+		}
+	}
+}
+
+// drawLabel пишет текст с центром в точке at; withBox подкладывает белый фон
+func drawLabel(img *image.RGBA, face font.Face, text string, at point, textColor color.Color, withBox bool) {
+	width := font.MeasureString(face, text).Round()
+	metrics := face.Metrics()
+	ascent, descent := metrics.Ascent.Round(), metrics.Descent.Round()
+
+	x := int(at.x) - width/2
+	baseline := int(at.y) + (ascent-descent)/2
+
+	if withBox {
+		box := image.Rect(x-3, baseline-ascent-1, x+width+3, baseline+descent+1)
+		draw.Draw(img, box, &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+	}
+
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  &image.Uniform{textColor},
+		Face: face,
+		Dot:  fixed.P(x, baseline),
+	}
+	drawer.DrawString(text)
+}
+
+func drawLegend(img *image.RGBA, nodes int, links int) {
+	items := []struct {
+		fill  color.RGBA
+		label string
+	}{
+		{colorCoordinator, "Координатор"},
+		{colorRouter, "Роутер"},
+		{colorEndDevice, "Конечное устройство"},
+		{colorFailed, "Ошибка опроса"},
+	}
+
+	for i, item := range items {
+		y := 30 + float64(i)*28
+		drawCircle(img, point{30, y}, 9, item.fill)
+		drawer := &font.Drawer{Dst: img, Src: &image.Uniform{colorText}, Face: labelFace, Dot: fixed.P(48, int(y)+5)}
+		drawer.DrawString(item.label)
+	}
+
+	drawer := &font.Drawer{Dst: img, Src: &image.Uniform{colorText}, Face: labelFace, Dot: fixed.P(20, mapSize-20)}
+	drawer.DrawString(fmt.Sprintf("Узлов: %d, связей: %d. Подписи на линиях - linkquality", nodes, links))
+}
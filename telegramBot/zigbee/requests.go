@@ -0,0 +1,143 @@
+package zigbee
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const requestTimeout = 10 * time.Second
+
+// Event - сообщение из <base>/bridge/event
+type Event struct {
+	Type string `json:"type"` // device_joined, device_interview, device_leave, ...
+	Data struct {
+		FriendlyName string      `json:"friendly_name"`
+		IEEEAddress  string      `json:"ieee_address"`
+		Status       string      `json:"status"` // для device_interview: started, successful, failed
+		Supported    bool        `json:"supported"`
+		Definition   *Definition `json:"definition"`
+	} `json:"data"`
+}
+
+// EventHandler получает события моста
+type EventHandler func(event Event)
+
+// OnEvent добавляет обработчик событий моста (подключение, опрос, уход устройств)
+func (b *Bridge) OnEvent(handler EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.eventHandlers = append(b.eventHandlers, handler)
+}
+
+func (b *Bridge) handleEvent(payload []byte) {
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		logger.Error("❌ Ошибка разбора bridge/event", "error", err)
+		return
+	}
+
+	logger.Info("🐝 Событие", "type", event.Type, "device", event.Data.FriendlyName)
+
+	b.mu.RLock()
+	handlers := append([]EventHandler(nil), b.eventHandlers...)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// Request отправляет запрос в <base>/bridge/request/<name> и ждет ответ
+// в <base>/bridge/response/<name> с тем же номером транзакции
+func (b *Bridge) Request(name string, payload map[string]any, timeout time.Duration) (json.RawMessage, error) {
+	b.mu.Lock()
+	b.transactionSeq++
+	transaction := "tg-" + strconv.FormatUint(b.transactionSeq, 10)
+	response := make(chan bridgeResponse, 1)
+	b.pending[transaction] = response
+	b.mu.Unlock()
+
+	defer func() {
+		b.mu.Lock()
+		delete(b.pending, transaction)
+		b.mu.Unlock()
+	}()
+
+	request := map[string]any{"transaction": transaction}
+	for key, value := range payload {
+		request[key] = value
+	}
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.mqtt.Publish(b.baseTopic+"/bridge/request/"+name, data, false); err != nil {
+		return nil, err
+	}
+
+	select {
+	case result := <-response:
+		if result.Status != "ok" {
+			return nil, fmt.Errorf("zigbee2mqtt: %s", result.Error)
+		}
+		return result.Data, nil
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("zigbee2mqtt не ответил на %s за %v", name, timeout)
+	}
+}
+
+type bridgeResponse struct {
+	Status      string          `json:"status"`
+	Error       string          `json:"error"`
+	Data        json.RawMessage `json:"data"`
+	Transaction string          `json:"transaction"`
+}
+
+func (b *Bridge) handleResponse(payload []byte) {
+	var response bridgeResponse
+	if err := json.Unmarshal(payload, &response); err != nil || response.Transaction == "" {
+		return
+	}
+
+	b.mu.RLock()
+	waiter, ok := b.pending[response.Transaction]
+	b.mu.RUnlock()
+
+	if ok {
+		select {
+		case waiter <- response:
+		default:
+		}
+	}
+}
+
+// PermitJoin разрешает подключение новых устройств на seconds секунд (0 - запретить)
+func (b *Bridge) PermitJoin(seconds int) error {
+	_, err := b.Request("permit_join", map[string]any{
+		"value": seconds > 0,
+		"time":  seconds,
+	}, requestTimeout)
+	return err
+}
+
+// RenameDevice меняет friendly_name устройства
+func (b *Bridge) RenameDevice(from string, to string) error {
+	_, err := b.Request("device/rename", map[string]any{
+		"from":                 from,
+		"to":                   to,
+		"homeassistant_rename": true,
+	}, requestTimeout)
+	return err
+}
+
+// RemoveDevice удаляет устройство из сети. force удаляет его из базы, даже если устройство не отвечает.
+func (b *Bridge) RemoveDevice(id string, force bool) error {
+	_, err := b.Request("device/remove", map[string]any{
+		"id":    id,
+		"force": force,
+	}, requestTimeout)
+	return err
+}